@@ -0,0 +1,222 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Comment is a movetext comment and the annotator it is attributed to.
+// When a game is encoded, comments by the annotator named in the game's
+// Annotator tag are written as is and comments by anyone else are
+// prefixed with an annotator command:
+//
+//	{[%annotator Kasparov] The only move.}
+type Comment struct {
+	Annotator string
+	Text      string
+}
+
+const annotatorCommand = "[%annotator "
+
+// parseComment parses the text of a PGN comment.  Comments without an
+// annotator command are attributed to the given default annotator.
+func parseComment(text, annotator string) Comment {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, annotatorCommand) {
+		if end := strings.IndexByte(text, ']'); end != -1 {
+			return Comment{
+				Annotator: strings.TrimSpace(text[len(annotatorCommand):end]),
+				Text:      strings.TrimSpace(text[end+1:]),
+			}
+		}
+	}
+	return Comment{Annotator: annotator, Text: text}
+}
+
+// encode returns the text of the comment's brace comment.  A closing
+// brace, which can be in the text of a rest of line comment, can't be
+// written in a brace comment so it's written as a parenthesis.
+func (c Comment) encode(annotator string) string {
+	text := strings.Replace(c.Text, "}", ")", -1)
+	if c.Annotator == annotator {
+		return text
+	}
+	s := annotatorCommand + c.Annotator + "]"
+	if text != "" {
+		s += " " + text
+	}
+	return s
+}
+
+// Annotator returns the value of the game's Annotator tag or an empty
+// string if it isn't present.
+func (g *Game) Annotator() string {
+	if tp := g.GetTagPair("Annotator"); tp != nil {
+		return tp.Value
+	}
+	return ""
+}
+
+// AddComment adds a comment by the annotator after the move at the given
+// ply where 1 is the first move of the game.  A ply of 0 adds the comment
+// before the first move.  An error is returned if the ply is out of range.
+func (g *Game) AddComment(ply int, annotator, text string) error {
+	c := Comment{Annotator: annotator, Text: text}
+	if ply == 0 {
+		g.comments = append(g.comments, c)
+		return nil
+	}
+	if err := g.checkPly(ply); err != nil {
+		return err
+	}
+	m := g.moves[ply-1]
+	m.comments = append(m.comments, c)
+	return nil
+}
+
+// AddVariation adds a variation by the annotator as an alternative to the
+// move at the given ply where 1 is the first move of the game.  An error
+// is returned if the ply is out of range or the moves aren't legal from
+// the position before that move.
+func (g *Game) AddVariation(ply int, annotator string, moves ...*Move) error {
+	if err := g.checkPly(ply); err != nil {
+		return err
+	}
+	if len(moves) == 0 {
+		return fmt.Errorf("chess: variation at ply %d has no moves", ply)
+	}
+	pos := g.positions[ply-1]
+	variation := []*Move{}
+	for _, m := range moves {
+		valid := moveSlice(pos.ValidMoves()).find(m)
+		if valid == nil {
+			return fmt.Errorf("chess: invalid move %s in variation at ply %d", m, ply)
+		}
+		cp := valid.copy()
+		variation = append(variation, cp)
+		pos = pos.Update(cp)
+	}
+	variation[0].annotator = annotator
+	m := g.moves[ply-1]
+	m.variations = append(m.variations, variation)
+	return nil
+}
+
+// Annotators returns the annotators of the game's comments and variations
+// in the order they first appear.
+func (g *Game) Annotators() []string {
+	annotators := []string{}
+	add := func(name string) {
+		for _, a := range annotators {
+			if a == name {
+				return
+			}
+		}
+		annotators = append(annotators, name)
+	}
+	var walk func(moves []*Move)
+	walk = func(moves []*Move) {
+		for _, m := range moves {
+			if m.annotator != "" {
+				add(m.annotator)
+			}
+			for _, c := range append(append([]Comment(nil), m.preComments...), m.comments...) {
+				add(c.Annotator)
+			}
+			for _, v := range m.variations {
+				walk(v)
+			}
+		}
+	}
+	for _, c := range g.comments {
+		add(c.Annotator)
+	}
+	walk(g.moves)
+	return annotators
+}
+
+// FilterAnnotations returns a copy of the game with only the comments and
+// variations whose annotator satisfies keep.  It can be used to export a
+// single annotator's work or to strip an annotator's work before export.
+func (g *Game) FilterAnnotations(keep func(annotator string) bool) *Game {
+	cp := g.Clone()
+	cp.ignoreAutomaticDraws = g.ignoreAutomaticDraws
+	cp.comments = filterComments(g.comments, keep)
+	cp.moves = filterAnnotations(g.moves, keep)
+	return cp
+}
+
+func filterAnnotations(moves []*Move, keep func(annotator string) bool) []*Move {
+	filtered := []*Move{}
+	for _, m := range moves {
+		cp := m.copy()
+		cp.annotator = m.annotator
+		cp.layout = m.layout
+		cp.nags = append([]string(nil), m.nags...)
+		cp.preComments = filterComments(m.preComments, keep)
+		cp.comments = filterComments(m.comments, keep)
+		for _, v := range m.variations {
+			if keep(v[0].annotator) {
+				cp.variations = append(cp.variations, filterAnnotations(v, keep))
+			}
+		}
+		filtered = append(filtered, cp)
+	}
+	return filtered
+}
+
+func filterComments(comments []Comment, keep func(annotator string) bool) []Comment {
+	filtered := []Comment{}
+	for _, c := range comments {
+		if keep(c.Annotator) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// Comments returns the comments after the move.
+func (m *Move) Comments() []Comment {
+	return append([]Comment(nil), m.comments...)
+}
+
+// EscapeLines returns the text after the % of the game's PGN escape lines,
+// which the PGN standard reserves for private data.  Escape lines are only
+// decoded from PGN with PreservePGN and aren't encoded.
+func (g *Game) EscapeLines() []string {
+	return append([]string(nil), g.escapes...)
+}
+
+// Comments returns the comments after the move at the given ply where 1
+// is the first move of the game.  A ply of 0 returns the comments before
+// the first move.  Comments are only decoded from PGN with PreservePGN.
+// An error is returned if the ply is out of range.
+func (g *Game) Comments(ply int) ([]Comment, error) {
+	if ply == 0 {
+		return append([]Comment(nil), g.comments...), nil
+	}
+	if err := g.checkPly(ply); err != nil {
+		return nil, err
+	}
+	return g.moves[ply-1].Comments(), nil
+}
+
+// SetComment replaces the comments after the move at the given ply with
+// the text attributed to the game's annotator.  A ply of 0 sets the
+// comment before the first move and empty text removes the comments.
+// An error is returned if the ply is out of range.
+func (g *Game) SetComment(ply int, text string) error {
+	var comments []Comment
+	if text != "" {
+		comments = []Comment{{Annotator: g.Annotator(), Text: text}}
+	}
+	if ply == 0 {
+		g.comments = comments
+		return nil
+	}
+	if err := g.checkPly(ply); err != nil {
+		return err
+	}
+	g.moves[ply-1].comments = comments
+	return nil
+}
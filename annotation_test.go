@@ -0,0 +1,101 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+const annotatedPGN = `[Event "?"]
+[Annotator "Alice"]
+
+1. e4 {Best by test.} e5 2. Nf3 {[%annotator Bob] Developing.} (2. f4 {[%annotator Bob] Gambit!}) ({[%annotator Bob]} 2. Bc4 Nf6) Nc6 *`
+
+func TestAnnotators(t *testing.T) {
+	opt, err := PGN(strings.NewReader(annotatedPGN), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	if g.Annotator() != "Alice" {
+		t.Fatalf("expected annotator Alice but got %s", g.Annotator())
+	}
+	if err := g.AddComment(4, "Carol", "Solid."); err != nil {
+		t.Fatal(err)
+	}
+	annotators := g.Annotators()
+	if strings.Join(annotators, ",") != "Alice,Bob,Carol" {
+		t.Fatalf("expected annotators Alice,Bob,Carol but got %v", annotators)
+	}
+	s := g.String()
+	expected := `1.e4 {Best by test.} 1...e5 2.Nf3 {[%annotator Bob] Developing.} (2.f4 {[%annotator Bob] Gambit!}) ({[%annotator Bob]} 2.Bc4 Nf6) 2...Nc6 {[%annotator Carol] Solid.} *`
+	if !strings.HasSuffix(s, expected) {
+		t.Fatalf("expected pgn to end with\n%s\nbut got\n%s", expected, s)
+	}
+	alice := g.FilterAnnotations(func(a string) bool { return a == "Alice" })
+	expected = `1.e4 {Best by test.} 1...e5 2.Nf3 (2.f4) 2...Nc6  *`
+	if s := alice.String(); !strings.HasSuffix(s, expected) {
+		t.Fatalf("expected pgn to end with\n%s\nbut got\n%s", expected, s)
+	}
+	if len(g.Annotators()) != 3 {
+		t.Fatal("expected filtering to leave the original game unchanged")
+	}
+}
+
+func TestAddVariation(t *testing.T) {
+	g := NewGame()
+	for _, s := range []string{"e4", "e5"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c5 := &Move{s1: C7, s2: C5}
+	if err := g.AddVariation(2, "Alice", c5); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddVariation(2, "Alice", &Move{s1: C7, s2: C4}); err == nil {
+		t.Fatal("expected an illegal variation to return an error")
+	}
+	if err := g.AddVariation(3, "Alice", c5); err == nil {
+		t.Fatal("expected an out of range ply to return an error")
+	}
+	expected := "1.e4 e5 ({[%annotator Alice]} 1...c5) *"
+	if s := g.String(); !strings.HasSuffix(s, expected) {
+		t.Fatalf("expected pgn to end with %s but got %s", expected, s)
+	}
+}
+
+func TestSetComment(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("[Annotator \"Bob\"]\n\n{Intro} 1. e4 {Best by test} {[%annotator Alice] Too direct} e5 *"), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	comments, err := g.Comments(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0].Text != "Best by test" || comments[1].Annotator != "Alice" {
+		t.Fatalf("unexpected comments %v", comments)
+	}
+	if c := g.Moves()[0].Comments(); len(c) != 2 {
+		t.Fatalf("expected the move to have 2 comments but got %v", c)
+	}
+	if err := g.SetComment(1, "Good"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetComment(2, "Symmetric"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetComment(0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetComment(3, "Out of range"); err == nil {
+		t.Fatal("expected an error setting a comment out of range")
+	}
+	if !strings.HasSuffix(g.String(), "1.e4 {Good} 1...e5 {Symmetric} *") {
+		t.Fatalf("unexpected pgn %s", g.String())
+	}
+	if c, _ := g.Comments(0); len(c) != 0 {
+		t.Fatalf("expected the intro comment to be removed but got %v", c)
+	}
+}
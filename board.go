@@ -107,22 +107,14 @@ func (b *Board) Transpose() *Board {
 }
 
 // Draw returns visual representation of the board useful for debugging.
-func (b *Board) Draw() string {
-	s := "\n A B C D E F G H\n"
-	for r := 7; r >= 0; r-- {
-		s += Rank(r).String()
-		for f := 0; f < numOfSquaresInRow; f++ {
-			p := b.Piece(getSquare(File(f), Rank(r)))
-			if p == NoPiece {
-				s += "-"
-			} else {
-				s += p.String()
-			}
-			s += " "
-		}
-		s += "\n"
+// Options can be given to add overlays such as a move list sidebar,
+// captured piece trays, or an evaluation bar.
+func (b *Board) Draw(opts ...func(*textDrawer)) string {
+	d := &textDrawer{}
+	for _, opt := range opts {
+		opt(d)
 	}
-	return s
+	return d.draw(b)
 }
 
 // String implements the fmt.Stringer interface and returns
@@ -216,6 +208,10 @@ func (b *Board) UnmarshalBinary(data []byte) error {
 }
 
 func (b *Board) update(m *Move) {
+	if m.HasTag(KingSideCastle) || m.HasTag(QueenSideCastle) {
+		b.castle(m)
+		return
+	}
 	p1 := b.Piece(m.s1)
 	s1BB := bbForSquare(m.s1)
 	s2BB := bbForSquare(m.s2)
@@ -249,19 +245,63 @@ func (b *Board) update(m *Move) {
 			b.bbWhitePawn = ^(bbForSquare(m.s2) >> 8) & b.bbWhitePawn
 		}
 	}
-	// move rook for castle
-	if p1.Color() == White && m.HasTag(KingSideCastle) {
-		b.bbWhiteRook = (b.bbWhiteRook & ^bbForSquare(H1) | bbForSquare(F1))
-	} else if p1.Color() == White && m.HasTag(QueenSideCastle) {
-		b.bbWhiteRook = (b.bbWhiteRook & ^bbForSquare(A1)) | bbForSquare(D1)
-	} else if p1.Color() == Black && m.HasTag(KingSideCastle) {
-		b.bbBlackRook = (b.bbBlackRook & ^bbForSquare(H8) | bbForSquare(F8))
-	} else if p1.Color() == Black && m.HasTag(QueenSideCastle) {
-		b.bbBlackRook = (b.bbBlackRook & ^bbForSquare(A8)) | bbForSquare(D8)
-	}
 	b.calcConvienceBBs(m)
 }
 
+// castle updates the board for a castling move.  The king and rook may
+// start on any squares of the back rank (as in Chess960) and end on the
+// standard squares.  The rook is the outermost rook on the side unless
+// the move's destination is another of the king's rooks.
+func (b *Board) castle(m *Move) {
+	side := KingSide
+	if m.HasTag(QueenSideCastle) {
+		side = QueenSide
+	}
+	king := b.Piece(m.s1)
+	rook := getPiece(Rook, king.Color())
+	rookSq := b.castleRook(m.s1, side)
+	if b.Piece(m.s2) == rook {
+		// the king captures its rook when castling with an inner rook
+		rookSq = m.s2
+	}
+	kingTo, rookTo := castleTargets(m.s1.Rank(), side)
+	b.setBBForPiece(king, b.bbForPiece(king) & ^bbForSquare(m.s1))
+	b.setBBForPiece(rook, b.bbForPiece(rook) & ^bbForSquare(rookSq))
+	b.setBBForPiece(king, b.bbForPiece(king)|bbForSquare(kingTo))
+	b.setBBForPiece(rook, b.bbForPiece(rook)|bbForSquare(rookTo))
+	b.calcConvienceBBs(nil)
+}
+
+// castleRook returns the square of the outermost rook of the king's color
+// on the given side of the king or NoSquare if there isn't one.
+func (b *Board) castleRook(king Square, side Side) Square {
+	rook := getPiece(Rook, b.Piece(king).Color())
+	rank := king.Rank()
+	if side == KingSide {
+		for f := FileH; f > king.File(); f-- {
+			if sq := getSquare(f, rank); b.Piece(sq) == rook {
+				return sq
+			}
+		}
+		return NoSquare
+	}
+	for f := FileA; f < king.File(); f++ {
+		if sq := getSquare(f, rank); b.Piece(sq) == rook {
+			return sq
+		}
+	}
+	return NoSquare
+}
+
+// castleTargets returns the squares the king and rook end on after
+// castling on the side.
+func castleTargets(rank Rank, side Side) (Square, Square) {
+	if side == KingSide {
+		return getSquare(FileG, rank), getSquare(FileF, rank)
+	}
+	return getSquare(FileC, rank), getSquare(FileD, rank)
+}
+
 func (b *Board) calcConvienceBBs(m *Move) {
 	whiteSqs := b.bbWhiteKing | b.bbWhiteQueen | b.bbWhiteRook | b.bbWhiteBishop | b.bbWhiteKnight | b.bbWhitePawn
 	blackSqs := b.bbBlackKing | b.bbBlackQueen | b.bbBlackRook | b.bbBlackBishop | b.bbBlackKnight | b.bbBlackPawn
@@ -347,7 +387,7 @@ func (b *Board) hasSufficientMaterial() bool {
 		blackCount := 0
 		for sq, p := range pieceMap {
 			if p.Type() == Bishop {
-				switch sq.color() {
+				switch sq.Color() {
 				case White:
 					whiteCount++
 				case Black:
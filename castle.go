@@ -0,0 +1,182 @@
+package chess
+
+import "fmt"
+
+// InferCastleRights returns the plausible castling rights for the board:
+// a color may castle on a side if its king and that side's rook are on
+// their original squares.  Rights can't be known from the board alone
+// since the pieces may have moved and returned, so the result is the
+// most permissive rights consistent with the placement.
+func InferCastleRights(b *Board) CastleRights {
+	return castleRightsFromBoard(b)
+}
+
+// RepairFEN repairs inconsistencies in the FEN and returns the repaired
+// FEN along with a report of the changes.  Castling rights are removed
+// when the king isn't on its back rank or the castling rook isn't beside
+// it, including Chess960 rights written as file letters, and the en passant
+// square is removed when no en passant capture is legal.  An error is
+// returned if the FEN can't be decoded.
+func RepairFEN(fen string) (string, *RepairReport, error) {
+	pos, err := decodeFEN(fen)
+	if err != nil {
+		return "", nil, err
+	}
+	r := &RepairReport{}
+	pos.castleRights = repairCastleRights(pos, r)
+	if normalized := pos.normalizeEnPassant(); normalized != pos {
+		r.add("removed en passant square %s without a legal capture", pos.enPassantSquare)
+		pos = normalized
+	}
+	return pos.String(), r, nil
+}
+
+func repairCastleRights(pos *Position, r *RepairReport) CastleRights {
+	b := pos.board
+	rights := pos.castleRightsList()
+	kept := []castleRight{}
+	removed := false
+	for _, c := range []Color{White, Black} {
+		king, backRank := b.whiteKingSq, Rank1
+		if c == Black {
+			king, backRank = b.blackKingSq, Rank8
+		}
+		for _, side := range []Side{KingSide, QueenSide} {
+			for _, right := range rights {
+				if right.color != c || right.side != side {
+					continue
+				}
+				beside := right.rook.File() > king.File()
+				if side == QueenSide {
+					beside = right.rook.File() < king.File()
+				}
+				if king == NoSquare || king.Rank() != backRank || b.Piece(right.rook) != getPiece(Rook, c) || !beside {
+					r.add("removed castling right %s without king and rook on their original squares", formatCastleRights(b, []castleRight{right}))
+					removed = true
+					continue
+				}
+				kept = append(kept, right)
+			}
+		}
+	}
+	s := formatCastleRights(b, kept)
+	if !removed && s != pos.castleRights {
+		r.add("normalized castling rights %s to %s", pos.castleRights, s)
+	}
+	return s
+}
+
+// castleRight is a castling right of a color on a side with the square of
+// the rook it castles with.
+type castleRight struct {
+	color Color
+	side  Side
+	rook  Square
+}
+
+// parseCastleRights returns the castling rights of the FEN castling field
+// for the board.  K, Q, k and q are rights with the outermost rook on
+// their side and file letters (A-H for White and a-h for Black) are
+// rights with the rook on the file, as in X-FEN and Shredder-FEN.  An
+// error is returned if a file letter has no rook beside its king or a
+// color has two rights on the same side.
+func parseCastleRights(b *Board, cr CastleRights) ([]castleRight, error) {
+	rights := []castleRight{}
+	if cr == "-" {
+		return rights, nil
+	}
+	for _, r := range string(cr) {
+		c, king, backRank := White, b.whiteKingSq, Rank1
+		if r >= 'a' {
+			c, king, backRank = Black, b.blackKingSq, Rank8
+			r -= 'a' - 'A'
+		}
+		right := castleRight{color: c}
+		switch r {
+		case 'K', 'Q':
+			right.side, right.rook = KingSide, getSquare(FileH, backRank)
+			if r == 'Q' {
+				right.side, right.rook = QueenSide, getSquare(FileA, backRank)
+			}
+			// the castling rook may be on any square as in Chess960
+			if king != NoSquare && king.Rank() == backRank {
+				if sq := b.castleRook(king, right.side); sq != NoSquare {
+					right.rook = sq
+				}
+			}
+		default:
+			right.rook = getSquare(File(r-'A'), backRank)
+			if king == NoSquare || king.Rank() != backRank || b.Piece(right.rook) != getPiece(Rook, c) {
+				return nil, fmt.Errorf("chess: fen invalid castle rights %s", cr)
+			}
+			right.side = KingSide
+			if right.rook.File() < king.File() {
+				right.side = QueenSide
+			}
+		}
+		for _, other := range rights {
+			if other.color == right.color && other.side == right.side {
+				return nil, fmt.Errorf("chess: fen invalid castle rights %s", cr)
+			}
+		}
+		rights = append(rights, right)
+	}
+	return rights, nil
+}
+
+// formatCastleRights returns the FEN castling field of the rights for the
+// board.  A right is written as K, Q, k or q unless its rook isn't the
+// outermost rook on its side, in which case it's written as the rook's
+// file as in X-FEN, so Shredder-FEN fields are written in the standard
+// form when they don't need file letters.
+func formatCastleRights(b *Board, rights []castleRight) CastleRights {
+	s := ""
+	for _, r := range rights {
+		char := "k"
+		if r.side == QueenSide {
+			char = "q"
+		}
+		king := b.whiteKingSq
+		if r.color == Black {
+			king = b.blackKingSq
+		}
+		if king != NoSquare && king.Rank() == r.rook.Rank() &&
+			b.Piece(r.rook) == getPiece(Rook, r.color) && b.castleRook(king, r.side) != r.rook {
+			char = r.rook.File().String()
+		}
+		s += fenCastleChar(r.color, char)
+	}
+	if s == "" {
+		s = "-"
+	}
+	return CastleRights(s)
+}
+
+// castleRightsList returns the position's castling rights with their rooks.
+func (pos *Position) castleRightsList() []castleRight {
+	// the position's rights are valid for its board
+	rights, _ := parseCastleRights(pos.board, pos.castleRights)
+	return rights
+}
+
+// castleRook returns the square of the rook the color may castle with on
+// the side or NoSquare if it can't castle on the side.
+func (pos *Position) castleRook(c Color, side Side) Square {
+	for _, r := range pos.castleRightsList() {
+		if r.color == c && r.side == side && pos.board.Piece(r.rook) == getPiece(Rook, c) {
+			return r.rook
+		}
+	}
+	return NoSquare
+}
+
+// canCastle returns true if the color has a castling right on the side,
+// including rights written as file letters.
+func (pos *Position) canCastle(c Color, side Side) bool {
+	for _, r := range pos.castleRightsList() {
+		if r.color == c && r.side == side {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package chess
+
+import "testing"
+
+func TestInferCastleRights(t *testing.T) {
+	pos := unsafeFEN("r3k3/8/8/8/8/8/8/R3K2R w - - 0 1")
+	if cr := InferCastleRights(pos.Board()); cr != "KQq" {
+		t.Fatalf("expected castle rights KQq but got %s", cr)
+	}
+	pos = unsafeFEN("r3k3/8/8/8/8/8/8/R2K3R w - - 0 1")
+	if cr := InferCastleRights(pos.Board()); cr != "q" {
+		t.Fatalf("expected castle rights q but got %s", cr)
+	}
+}
+
+func TestRepairFEN(t *testing.T) {
+	tests := []struct {
+		fen      string
+		repaired string
+		changes  int
+	}{
+		{"r3k3/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "r3k3/8/8/8/8/8/8/R3K2R w KQq - 0 1", 1},
+		{"r3k3/8/8/8/8/8/8/4K3 w KQkq - 0 1", "r3k3/8/8/8/8/8/8/4K3 w q - 0 1", 3},
+		{"r3k3/8/8/8/8/8/8/R3K2R w qK - 0 1", "r3k3/8/8/8/8/8/8/R3K2R w Kq - 0 1", 1},
+		{startFEN, startFEN, 0},
+		{"bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w KQkq - 2 9", 0},
+		{"rk5r/8/8/8/8/8/8/RK5R w KQkq - 0 1", "rk5r/8/8/8/8/8/8/RK5R w KQkq - 0 1", 0},
+		{"rk6/8/8/8/8/8/8/RK5R w KQkq - 0 1", "rk6/8/8/8/8/8/8/RK5R w KQq - 0 1", 1},
+	}
+	for _, test := range tests {
+		fen, r, err := RepairFEN(test.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fen != test.repaired {
+			t.Fatalf("expected %s to be repaired to %s but got %s", test.fen, test.repaired, fen)
+		}
+		if len(r.Changes) != test.changes {
+			t.Fatalf("expected %d changes for %s but got %v", test.changes, test.fen, r.Changes)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package chess
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An Eval is an engine evaluation embedded in a comment with the [%eval]
+// command such as [%eval 0.31], [%eval #-4] or [%eval 0.31,20].
+type Eval struct {
+	// Centipawns is the score from White's perspective.  It is zero for
+	// mate scores.
+	Centipawns int
+	// Mate is the number of moves to mate, positive if White mates and
+	// negative if Black mates.  It is zero if the score isn't a mate.
+	Mate int
+	// Depth is the search depth or zero if it isn't known.
+	Depth int
+}
+
+// Pawns returns the evaluation in pawns from White's perspective.  Mates
+// are returned as plus or minus 100 pawns.
+func (e Eval) Pawns() float64 {
+	switch {
+	case e.Mate > 0:
+		return 100
+	case e.Mate < 0:
+		return -100
+	}
+	return float64(e.Centipawns) / 100
+}
+
+// String returns the evaluation in the format of the [%eval] command.
+func (e Eval) String() string {
+	s := strconv.FormatFloat(float64(e.Centipawns)/100, 'f', 2, 64)
+	if e.Mate != 0 {
+		s = "#" + strconv.Itoa(e.Mate)
+	}
+	if e.Depth > 0 {
+		s += "," + strconv.Itoa(e.Depth)
+	}
+	return s
+}
+
+// ParseEval parses the value of an [%eval] command such as 0.31, #-4 or
+// 0.31,20.
+func ParseEval(s string) (Eval, error) {
+	e := Eval{}
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ','); i != -1 {
+		depth, err := strconv.Atoi(strings.TrimSpace(s[i+1:]))
+		if err != nil {
+			return Eval{}, fmt.Errorf("chess: invalid eval depth %s", s)
+		}
+		e.Depth = depth
+		s = strings.TrimSpace(s[:i])
+	}
+	if strings.HasPrefix(s, "#") {
+		mate, err := strconv.Atoi(s[1:])
+		if err != nil {
+			return Eval{}, fmt.Errorf("chess: invalid eval %s", s)
+		}
+		e.Mate = mate
+		return e, nil
+	}
+	pawns, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Eval{}, fmt.Errorf("chess: invalid eval %s", s)
+	}
+	e.Centipawns = int(math.Round(pawns * 100))
+	return e, nil
+}
+
+// ParseClock parses the value of a [%clk] command such as 0:03:00 or
+// 1:59.9.
+func ParseClock(s string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("chess: invalid clock %s", s)
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("chess: invalid clock %s", s)
+	}
+	d := time.Duration(math.Round(seconds * float64(time.Second)))
+	units := []time.Duration{time.Minute, time.Hour}
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("chess: invalid clock %s", s)
+		}
+		d += time.Duration(n) * units[len(parts)-2-i]
+	}
+	return d, nil
+}
+
+// formatClock returns the duration in the format of the [%clk] command.
+// Tenths of a second are only written if there are any.
+func formatClock(d time.Duration) string {
+	tenths := d.Round(time.Second/10) / (time.Second / 10)
+	s := fmt.Sprintf("%d:%02d:%02d", tenths/36000, tenths/600%60, tenths/10%60)
+	if tenths%10 != 0 {
+		s += fmt.Sprintf(".%d", tenths%10)
+	}
+	return s
+}
+
+// Clock returns the remaining time on the clock of the side that made the
+// move from the [%clk] command in its comments.  False is returned if the
+// move has no valid clock command.  Comments are only kept by decoding
+// with PreservePGN.
+func (m *Move) Clock() (time.Duration, bool) {
+	value, ok := commentCommand(m.comments, "clk")
+	if !ok {
+		return 0, false
+	}
+	d, err := ParseClock(value)
+	return d, err == nil
+}
+
+// Eval returns the evaluation of the position after the move from the
+// [%eval] command in its comments.  False is returned if the move has no
+// valid eval command.  Comments are only kept by decoding with
+// PreservePGN.
+func (m *Move) Eval() (Eval, bool) {
+	value, ok := commentCommand(m.comments, "eval")
+	if !ok {
+		return Eval{}, false
+	}
+	e, err := ParseEval(value)
+	return e, err == nil
+}
+
+// MoveTime returns the time spent on the move from the [%emt] command in
+// its comments.  False is returned if the move has no valid emt command.
+// Comments are only kept by decoding with PreservePGN.
+func (m *Move) MoveTime() (time.Duration, bool) {
+	value, ok := commentCommand(m.comments, "emt")
+	if !ok {
+		return 0, false
+	}
+	d, err := ParseClock(value)
+	return d, err == nil
+}
+
+// SetClock sets the [%clk] command of the move at the given ply where 1
+// is the first move of the game so that it is written when the game is
+// encoded.  An error is returned if the ply is out of range.
+func (g *Game) SetClock(ply int, d time.Duration) error {
+	return g.setCommand(ply, "clk", formatClock(d))
+}
+
+// SetEval sets the [%eval] command of the move at the given ply where 1
+// is the first move of the game so that it is written when the game is
+// encoded.  An error is returned if the ply is out of range.
+func (g *Game) SetEval(ply int, e Eval) error {
+	return g.setCommand(ply, "eval", e.String())
+}
+
+// SetMoveTime sets the [%emt] command of the move at the given ply where
+// 1 is the first move of the game so that it is written when the game is
+// encoded.  An error is returned if the ply is out of range.
+func (g *Game) SetMoveTime(ply int, d time.Duration) error {
+	return g.setCommand(ply, "emt", formatClock(d))
+}
+
+func (g *Game) setCommand(ply int, name, value string) error {
+	if err := g.checkPly(ply); err != nil {
+		return err
+	}
+	m := g.moves[ply-1]
+	m.comments = setCommentCommand(m.comments, name, value, g.Annotator())
+	return nil
+}
+
+// commentCommand returns the value of the first command with the name
+// such as [%clk 0:03:00] in the comments.
+func commentCommand(comments []Comment, name string) (string, bool) {
+	values := commentCommands(comments, name)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// commentCommands returns the values of every command with the name in
+// the comments.
+func commentCommands(comments []Comment, name string) []string {
+	values := []string{}
+	for _, c := range comments {
+		text := c.Text
+		for {
+			start, end := findCommand(text, name)
+			if start == -1 {
+				break
+			}
+			values = append(values, strings.TrimSpace(text[start+len(name)+2:end]))
+			text = text[end+1:]
+		}
+	}
+	return values
+}
+
+// setCommentCommand returns the comments with the value of the first
+// command with the name replaced.  If no comment has the command it is
+// added to the first comment by the annotator or a new one.  An empty
+// value removes the command along with comments left empty.
+func setCommentCommand(comments []Comment, name, value, annotator string) []Comment {
+	if value == "" {
+		return removeCommentCommand(comments, name)
+	}
+	cmd := "[%" + name + " " + value + "]"
+	comments = append([]Comment(nil), comments...)
+	for i, c := range comments {
+		if start, end := findCommand(c.Text, name); start != -1 {
+			comments[i].Text = c.Text[:start] + cmd + c.Text[end+1:]
+			return comments
+		}
+	}
+	for i, c := range comments {
+		if c.Annotator == annotator {
+			comments[i].Text = strings.TrimSpace(cmd + " " + c.Text)
+			return comments
+		}
+	}
+	return append(comments, Comment{Annotator: annotator, Text: cmd})
+}
+
+// removeCommentCommand returns the comments without the commands with the
+// name.  Comments left empty are dropped.
+func removeCommentCommand(comments []Comment, name string) []Comment {
+	cp := []Comment{}
+	for _, c := range comments {
+		text := c.Text
+		removed := false
+		for {
+			start, end := findCommand(text, name)
+			if start == -1 {
+				break
+			}
+			text = strings.TrimSpace(text[:start]) + " " + strings.TrimSpace(text[end+1:])
+			removed = true
+		}
+		text = strings.TrimSpace(text)
+		if removed && text == "" {
+			continue
+		}
+		c.Text = text
+		cp = append(cp, c)
+	}
+	return cp
+}
+
+// findCommand returns the indexes of the opening and closing brackets of
+// the command with the name in the text or -1 if it isn't found.
+func findCommand(text, name string) (int, int) {
+	prefix := "[%" + name + " "
+	start := strings.Index(text, prefix)
+	if start == -1 {
+		return -1, -1
+	}
+	end := strings.IndexByte(text[start:], ']')
+	if end == -1 {
+		return -1, -1
+	}
+	return start, start + end
+}
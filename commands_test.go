@@ -0,0 +1,85 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEvalAndClock(t *testing.T) {
+	evals := []struct {
+		s string
+		e Eval
+	}{
+		{"0.31", Eval{Centipawns: 31}},
+		{"-1.5", Eval{Centipawns: -150}},
+		{"#-4", Eval{Mate: -4}},
+		{"0.31,20", Eval{Centipawns: 31, Depth: 20}},
+	}
+	for _, test := range evals {
+		e, err := ParseEval(test.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e != test.e {
+			t.Fatalf("expected %s to parse as %+v but got %+v", test.s, test.e, e)
+		}
+	}
+	if _, err := ParseEval("abc"); err == nil {
+		t.Fatal("expected an error parsing an invalid eval")
+	}
+	clocks := []struct {
+		s string
+		d time.Duration
+	}{
+		{"0:03:00", 3 * time.Minute},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"1:59.9", time.Minute + 59*time.Second + 900*time.Millisecond},
+	}
+	for _, test := range clocks {
+		d, err := ParseClock(test.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d != test.d {
+			t.Fatalf("expected %s to parse as %s but got %s", test.s, test.d, d)
+		}
+	}
+	if s := formatClock(time.Minute + 59*time.Second + 900*time.Millisecond); s != "0:01:59.9" {
+		t.Fatalf("expected 0:01:59.9 but got %s", s)
+	}
+}
+
+func TestMoveClockAndEval(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("1. e4 { [%eval 0.17] [%clk 0:03:00] } 1... e5 { Solid. } 2. Nf3 *"), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	moves := g.Moves()
+	if d, ok := moves[0].Clock(); !ok || d != 3*time.Minute {
+		t.Fatalf("expected a clock of 3m but got %s", d)
+	}
+	if e, ok := moves[0].Eval(); !ok || e.Centipawns != 17 {
+		t.Fatalf("expected an eval of 0.17 but got %s", e)
+	}
+	if _, ok := moves[1].Clock(); ok {
+		t.Fatal("expected the second move to have no clock")
+	}
+	if err := g.SetClock(1, 2*time.Minute+59*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetEval(2, Eval{Mate: -3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetClock(3, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetClock(4, time.Minute); err == nil {
+		t.Fatal("expected an error setting a clock out of range")
+	}
+	expected := "1.e4 {[%eval 0.17] [%clk 0:02:59]} 1...e5 {[%eval #-3] Solid.} 2.Nf3 {[%clk 0:01:00]} *"
+	if s := g.String(); !strings.HasSuffix(s, expected) {
+		t.Fatalf("expected pgn ending with %s but got %s", expected, s)
+	}
+}
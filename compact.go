@@ -0,0 +1,48 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Compact returns the board's piece placement as 64 characters from A8
+// to H1 rank by rank, the format of the board fields of the ICS style 12
+// protocol and many hardware boards.  Pieces are written as FEN letters
+// and empty squares as '-'.
+func (b *Board) Compact() string {
+	s := make([]byte, 0, numOfSquaresInBoard)
+	for r := 7; r >= 0; r-- {
+		for f := 0; f < numOfSquaresInRow; f++ {
+			p := b.Piece(getSquare(File(f), Rank(r)))
+			if p == NoPiece {
+				s = append(s, '-')
+			} else {
+				s = append(s, p.getFENChar()...)
+			}
+		}
+	}
+	return string(s)
+}
+
+// ParseCompactBoard parses a board from the 64 character format returned
+// by Compact.  Empty squares may be '-' or '.' and whitespace (such as
+// spaces between ranks) is ignored.
+func ParseCompactBoard(s string) (*Board, error) {
+	s = strings.Join(strings.Fields(s), "")
+	if len(s) != numOfSquaresInBoard {
+		return nil, fmt.Errorf("chess: compact board has %d squares but requires 64", len(s))
+	}
+	m := map[Square]Piece{}
+	for i := 0; i < numOfSquaresInBoard; i++ {
+		c := s[i]
+		if c == '-' || c == '.' {
+			continue
+		}
+		p, ok := fenPieceMap[string(c)]
+		if !ok {
+			return nil, fmt.Errorf("chess: compact board has invalid character %q", c)
+		}
+		m[getSquare(File(i%8), Rank(7-i/8))] = p
+	}
+	return NewBoard(m), nil
+}
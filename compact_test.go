@@ -0,0 +1,30 @@
+package chess
+
+import "testing"
+
+func TestCompactBoard(t *testing.T) {
+	b := StartingPosition().Board()
+	expected := "rnbqkbnrpppppppp--------------------------------PPPPPPPPRNBQKBNR"
+	if s := b.Compact(); s != expected {
+		t.Fatalf("expected %s but got %s", expected, s)
+	}
+	parsed, err := ParseCompactBoard(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.String() != b.String() {
+		t.Fatalf("expected %s but got %s", b, parsed)
+	}
+	parsed, err = ParseCompactBoard("rnbqkbnr pppp.ppp ........ ....p... ....P... ........ PPPP.PPP RNBQKBNR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := parsed.String(); s != "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR" {
+		t.Fatalf("unexpected board %s", s)
+	}
+	for _, s := range []string{"rnbqkbnr", expected + "-", expected[:63] + "x"} {
+		if _, err := ParseCompactBoard(s); err == nil {
+			t.Fatalf("expected an error parsing %s", s)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package chess
+
+import "context"
+
+// GameContext returns a function that attaches the context to the game,
+// such as one canceled when the connection to a server drops.  Once the
+// context is canceled the game's moves are rejected and code waiting on
+// the game, such as a DGT driver or engine analysis of its positions,
+// stops.  The returned function is designed to be used in the NewGame
+// constructor.
+func GameContext(ctx context.Context) func(*Game) {
+	return func(g *Game) {
+		g.ctx = ctx
+	}
+}
+
+// Context returns the game's context.  The context is
+// context.Background if none was attached with GameContext.
+func (g *Game) Context() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
@@ -0,0 +1,84 @@
+package chess
+
+// ControlMap counts the pieces of one color that attack each square.  It
+// is indexed by rank and then file so that ControlMap[0][0] is A1 and
+// ControlMap[7][7] is H8.
+type ControlMap [8][8]int
+
+// At returns the number of attackers of the square.
+func (m ControlMap) At(sq Square) int {
+	return m[sq.Rank()][sq.File()]
+}
+
+// Control returns the control maps of White and Black for the position.
+// A square is attacked by a piece if the piece could capture an enemy
+// piece on it, so pawns attack diagonally, pieces attack squares occupied
+// by their own side (defending them) and pinned pieces still count.
+// Attacks through other pieces such as batteries aren't counted.
+func (pos *Position) Control() (white, black ControlMap) {
+	occ := ^pos.board.emptySqs
+	for i := 0; i < numOfSquaresInBoard; i++ {
+		sq := Square(i)
+		p := pos.board.Piece(sq)
+		if p == NoPiece {
+			continue
+		}
+		m := &white
+		if p.Color() == Black {
+			m = &black
+		}
+		attacks := pieceAttacks(p, sq, occ)
+		for j := 0; j < numOfSquaresInBoard; j++ {
+			if attacks.Occupied(Square(j)) {
+				m[j/8][j%8]++
+			}
+		}
+	}
+	return white, black
+}
+
+// attackersOf returns the squares of the color's pieces that attack sq
+// given the occupied squares.
+func attackersOf(b *Board, sq Square, c Color, occ bitboard) []Square {
+	squares := []Square{}
+	for i := 0; i < numOfSquaresInBoard; i++ {
+		p := b.Piece(Square(i))
+		if p != NoPiece && p.Color() == c && pieceAttacks(p, Square(i), occ).Occupied(sq) {
+			squares = append(squares, Square(i))
+		}
+	}
+	return squares
+}
+
+// pieceAttacks returns the squares the piece on sq attacks given the
+// occupied squares.
+func pieceAttacks(p Piece, sq Square, occ bitboard) bitboard {
+	switch p.Type() {
+	case King:
+		return bbKingMoves[sq]
+	case Queen:
+		return diaAttack(occ, sq) | hvAttack(occ, sq)
+	case Rook:
+		return hvAttack(occ, sq)
+	case Bishop:
+		return diaAttack(occ, sq)
+	case Knight:
+		return bbKnightMoves[sq]
+	case Pawn:
+		rank := int(sq.Rank()) + 1
+		if p.Color() == Black {
+			rank = int(sq.Rank()) - 1
+		}
+		var bb bitboard
+		if rank < 0 || rank > 7 {
+			return bb
+		}
+		for _, file := range []int{int(sq.File()) - 1, int(sq.File()) + 1} {
+			if file >= 0 && file <= 7 {
+				bb |= bbForSquare(getSquare(File(file), Rank(rank)))
+			}
+		}
+		return bb
+	}
+	return 0
+}
@@ -0,0 +1,42 @@
+package chess
+
+import "testing"
+
+func TestControl(t *testing.T) {
+	white, black := StartingPosition().Control()
+	tests := []struct {
+		m     ControlMap
+		sq    Square
+		count int
+	}{
+		{white, F3, 3},
+		{white, H3, 2},
+		{white, E1, 1},
+		{white, A1, 0},
+		{white, E4, 0},
+		{black, D6, 2},
+		{black, F6, 3},
+		{black, D3, 0},
+	}
+	for _, test := range tests {
+		if count := test.m.At(test.sq); count != test.count {
+			t.Fatalf("expected %d attackers of %s but got %d", test.count, test.sq, count)
+		}
+	}
+
+	// the rook on d1 attacks through to d8 but not past the pawn on d7
+	fen, err := FEN("3k4/3p4/8/8/8/8/8/3RK3 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	white, black = NewGame(fen).Position().Control()
+	if white.At(D7) != 1 || white.At(D8) != 0 {
+		t.Fatalf("expected the rook to attack d7 but not d8 got %d and %d", white.At(D7), white.At(D8))
+	}
+	if black.At(C6) != 1 || black.At(E6) != 1 || black.At(D6) != 0 {
+		t.Fatalf("expected the black pawn to attack c6 and e6 only")
+	}
+	if black.At(D7) != 1 {
+		t.Fatalf("expected the king to defend d7 but got %d", black.At(D7))
+	}
+}
@@ -0,0 +1,89 @@
+package chess
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RandomSquare returns a random square for coordinate training such as
+// asking for the square's name or color.
+func RandomSquare(r *rand.Rand) Square {
+	return Square(r.Intn(numOfSquaresInBoard))
+}
+
+// ParseSquare returns the square with the name such as e4.  An error is
+// returned if the name isn't a square.
+func ParseSquare(s string) (Square, error) {
+	sq, ok := strToSquareMap[s]
+	if !ok {
+		return NoSquare, fmt.Errorf("chess: invalid square %q", s)
+	}
+	return sq, nil
+}
+
+// A KnightPath is a puzzle to find the shortest route of a knight between
+// two squares of an empty board.
+type KnightPath struct {
+	From Square
+	To   Square
+}
+
+// RandomKnightPath returns a knight path puzzle between two different
+// random squares.
+func RandomKnightPath(r *rand.Rand) KnightPath {
+	from := RandomSquare(r)
+	to := Square(r.Intn(numOfSquaresInBoard - 1))
+	if to >= from {
+		to++
+	}
+	return KnightPath{From: from, To: to}
+}
+
+// Moves returns the fewest knight moves from From to To.
+func (k KnightPath) Moves() int {
+	return len(k.Solution())
+}
+
+// Solution returns a shortest path as the squares the knight lands on
+// after From, ending with To.  Other paths may be as short.
+func (k KnightPath) Solution() []Square {
+	prev := map[Square]Square{k.From: NoSquare}
+	queue := []Square{k.From}
+	for len(queue) > 0 && queue[0] != k.To {
+		sq := queue[0]
+		queue = queue[1:]
+		for i := 0; i < numOfSquaresInBoard; i++ {
+			next := Square(i)
+			if _, seen := prev[next]; !seen && bbKnightMoves[sq].Occupied(next) {
+				prev[next] = sq
+				queue = append(queue, next)
+			}
+		}
+	}
+	path := []Square{}
+	for sq := k.To; sq != k.From; sq = prev[sq] {
+		path = append([]Square{sq}, path...)
+	}
+	return path
+}
+
+// Verify checks an answer to the puzzle given as the squares the knight
+// lands on after From, ending with To.  An error describing the mistake
+// is returned if a step isn't a knight move, the path doesn't end on To
+// or the path is longer than the shortest.
+func (k KnightPath) Verify(path []Square) error {
+	sq := k.From
+	for _, next := range path {
+		if !bbKnightMoves[sq].Occupied(next) {
+			return fmt.Errorf("chess: %s to %s isn't a knight move", sq, next)
+		}
+		sq = next
+	}
+	if sq != k.To {
+		return fmt.Errorf("chess: knight path ends on %s instead of %s", sq, k.To)
+	}
+	if n := k.Moves(); len(path) > n {
+		return fmt.Errorf("chess: knight path takes %d moves but %d are enough", len(path), n)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package chess
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSquareColor(t *testing.T) {
+	tests := map[Square]Color{A1: Black, H1: White, A8: White, E4: White, D4: Black}
+	for sq, c := range tests {
+		if sq.Color() != c {
+			t.Fatalf("expected %s to be %s", sq, c)
+		}
+	}
+	if sq, err := ParseSquare("e4"); err != nil || sq != E4 {
+		t.Fatalf("expected e4 but got %s %v", sq, err)
+	}
+	if _, err := ParseSquare("i9"); err == nil {
+		t.Fatal("expected an error parsing an invalid square")
+	}
+}
+
+func TestKnightPath(t *testing.T) {
+	tests := []struct {
+		k     KnightPath
+		moves int
+	}{
+		{KnightPath{G1, F3}, 1},
+		{KnightPath{A1, B2}, 4},
+		{KnightPath{A1, H8}, 6},
+		{KnightPath{E4, E5}, 3},
+	}
+	for _, test := range tests {
+		if n := test.k.Moves(); n != test.moves {
+			t.Fatalf("expected %s to %s in %d moves but got %d", test.k.From, test.k.To, test.moves, n)
+		}
+		if err := test.k.Verify(test.k.Solution()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	k := KnightPath{B1, C5}
+	if err := k.Verify([]Square{C3, B5}); err == nil {
+		t.Fatal("expected an error for a path ending on the wrong square")
+	}
+	if err := k.Verify([]Square{C3, C5}); err == nil {
+		t.Fatal("expected an error for a step that isn't a knight move")
+	}
+	if err := k.Verify([]Square{D2, B3, D4, B5, A3, B5, C3, A4, C5}); err == nil {
+		t.Fatal("expected an error for a path longer than the shortest")
+	}
+	if err := k.Verify([]Square{A3, B5, A3, C4, A5, C4, B6}); err == nil {
+		t.Fatal("expected an error for a wrong path")
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if k := RandomKnightPath(r); k.From == k.To {
+			t.Fatal("expected different squares")
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package chess
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CorrespondenceGame is a game played by correspondence, as on ICCF and
+// correspondence servers.  Instead of a clock each player has a fixed time
+// to reply to a move, which stops during leave they take from a yearly
+// allowance, and may leave conditional moves that are played for them
+// when the opponent plays the expected moves.
+type CorrespondenceGame struct {
+	game    *Game
+	perMove time.Duration
+	// allowance is the leave each player may take
+	allowance time.Duration
+	// last is the time of the last move or the start of the game
+	last         time.Time
+	leaves       map[Color][]leave
+	conditionals map[Color][][]*Move
+}
+
+// leave is a period when a player's time to move is stopped.
+type leave struct {
+	start, end time.Time
+}
+
+// CorrespondenceLeave is an option for NewCorrespondenceGame that sets the
+// leave each player may take, such as the 45 days a year of ICCF events.
+// There is no leave by default.
+func CorrespondenceLeave(d time.Duration) func(*CorrespondenceGame) {
+	return func(c *CorrespondenceGame) {
+		c.allowance = d
+	}
+}
+
+// NewCorrespondenceGame returns a correspondence game that starts at the
+// given time with the time each player has to reply to a move.
+func NewCorrespondenceGame(g *Game, start time.Time, perMove time.Duration, opts ...func(*CorrespondenceGame)) *CorrespondenceGame {
+	c := &CorrespondenceGame{
+		game:         g,
+		perMove:      perMove,
+		last:         start,
+		leaves:       map[Color][]leave{},
+		conditionals: map[Color][][]*Move{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Game returns the game being played.
+func (c *CorrespondenceGame) Game() *Game {
+	return c.game
+}
+
+// Deadline returns the time by which the side to move must move, which is
+// extended by the leave the side takes before it.
+func (c *CorrespondenceGame) Deadline() time.Time {
+	deadline := c.last.Add(c.perMove)
+	for _, l := range c.leaves[c.game.Position().Turn()] {
+		if !l.start.Before(deadline) {
+			break
+		}
+		start := l.start
+		if start.Before(c.last) {
+			start = c.last
+		}
+		if l.end.After(start) {
+			deadline = deadline.Add(l.end.Sub(start))
+		}
+	}
+	return deadline
+}
+
+// Move plays the move for the side to move at the given time and then the
+// opponent's conditional reply to it, which is returned or nil if the
+// opponent has none.  An error is returned if the move is invalid or made
+// after the deadline, in which case the game is lost on time.
+func (c *CorrespondenceGame) Move(m *Move, at time.Time) (*Move, error) {
+	if c.Expire(at) {
+		return nil, fmt.Errorf("chess: move %s made after the deadline %s", m, c.Deadline().Format(time.RFC3339))
+	}
+	if err := c.game.Move(m); err != nil {
+		return nil, err
+	}
+	c.last = at
+	played := c.game.moves[len(c.game.moves)-1]
+	return c.playConditional(c.game.Position().Turn(), played), nil
+}
+
+// playConditional plays the color's conditional reply to the move and
+// keeps the sequences that continue with it.  The reply is returned or nil
+// if no sequence expected the move.
+func (c *CorrespondenceGame) playConditional(color Color, played *Move) *Move {
+	var reply *Move
+	kept := [][]*Move{}
+	for _, seq := range c.conditionals[color] {
+		if seq[0].String() != played.String() || len(seq) < 2 {
+			continue
+		}
+		if reply == nil {
+			reply = seq[1]
+		}
+		if seq[1].String() == reply.String() && len(seq) > 2 {
+			kept = append(kept, seq[2:])
+		}
+	}
+	c.conditionals[color] = kept
+	if reply == nil || c.game.Move(reply) != nil {
+		delete(c.conditionals, color)
+		return nil
+	}
+	return c.game.moves[len(c.game.moves)-1]
+}
+
+// Expire ends the game as a loss on time for the side to move if the
+// deadline has passed at the given time and returns true if it has.  The
+// Termination tag pair is set to "time forfeit".
+func (c *CorrespondenceGame) Expire(now time.Time) bool {
+	if c.game.Outcome() != NoOutcome || !now.After(c.Deadline()) {
+		return false
+	}
+	c.game.outcome = WhiteWon
+	if c.game.Position().Turn() == White {
+		c.game.outcome = BlackWon
+	}
+	c.game.AddTagPair("Termination", "time forfeit")
+	return true
+}
+
+// TakeLeave records leave for the player from the start for the duration
+// during which their time to move is stopped.  An error is returned if the
+// player doesn't have enough leave left or the leave overlaps leave they
+// have already taken.
+func (c *CorrespondenceGame) TakeLeave(color Color, start time.Time, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("chess: leave of %s isn't positive", d)
+	}
+	if d > c.LeaveRemaining(color) {
+		return fmt.Errorf("chess: %s has %s of leave left but requested %s", color.Name(), c.LeaveRemaining(color), d)
+	}
+	l := leave{start: start, end: start.Add(d)}
+	for _, taken := range c.leaves[color] {
+		if l.start.Before(taken.end) && taken.start.Before(l.end) {
+			return fmt.Errorf("chess: leave from %s overlaps leave already taken", start.Format(time.RFC3339))
+		}
+	}
+	leaves := append(c.leaves[color], l)
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].start.Before(leaves[j].start)
+	})
+	c.leaves[color] = leaves
+	return nil
+}
+
+// LeaveRemaining returns the leave the player has left.
+func (c *CorrespondenceGame) LeaveRemaining(color Color) time.Duration {
+	remaining := c.allowance
+	for _, l := range c.leaves[color] {
+		remaining -= l.end.Sub(l.start)
+	}
+	return remaining
+}
+
+// AddConditional adds a conditional sequence for the player which begins
+// with the opponent's next move and alternates between the player's
+// replies and the opponent's moves, such as "if 12...Nf6 then 13.e5, if
+// 13...Nd5 then 14.c4".  When the opponent plays the first move the reply
+// is played automatically and sequences that don't match are discarded.
+// Sequences with the same moves as an earlier one but a different reply
+// are ignored.  An error is returned if it isn't the opponent's turn or a
+// move is invalid.
+func (c *CorrespondenceGame) AddConditional(color Color, moves ...*Move) error {
+	pos := c.game.Position()
+	if pos.Turn() == color {
+		return fmt.Errorf("chess: conditional moves for %s must begin with the opponent's move", color.Name())
+	}
+	if len(moves) < 2 {
+		return fmt.Errorf("chess: conditional moves need the opponent's move and a reply")
+	}
+	seq := []*Move{}
+	for _, m := range moves {
+		valid := moveSlice(pos.ValidMoves()).find(m)
+		if valid == nil {
+			return fmt.Errorf("chess: invalid conditional move %s in position %s", m, pos)
+		}
+		seq = append(seq, valid)
+		pos = pos.Update(valid)
+	}
+	c.conditionals[color] = append(c.conditionals[color], seq)
+	return nil
+}
+
+// Conditionals returns the player's remaining conditional sequences.
+func (c *CorrespondenceGame) Conditionals(color Color) [][]*Move {
+	seqs := [][]*Move{}
+	for _, seq := range c.conditionals[color] {
+		seqs = append(seqs, append([]*Move(nil), seq...))
+	}
+	return seqs
+}
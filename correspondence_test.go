@@ -0,0 +1,97 @@
+package chess
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCorrespondenceDeadlineAndLeave(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCorrespondenceGame(NewGame(), start, 3*day, CorrespondenceLeave(10*day))
+	if d := c.Deadline(); !d.Equal(start.Add(3 * day)) {
+		t.Fatalf("expected a deadline three days after the start but got %s", d)
+	}
+	if err := c.TakeLeave(White, start.Add(day), 4*day); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.TakeLeave(White, start.Add(2*day), day); err == nil {
+		t.Fatal("expected overlapping leave to be rejected")
+	}
+	if err := c.TakeLeave(White, start.Add(20*day), 7*day); err == nil {
+		t.Fatal("expected leave beyond the allowance to be rejected")
+	}
+	if r := c.LeaveRemaining(White); r != 6*day {
+		t.Fatalf("expected six days of leave left but got %s", r)
+	}
+	if d := c.Deadline(); !d.Equal(start.Add(7 * day)) {
+		t.Fatalf("expected the leave to extend the deadline to seven days but got %s", d)
+	}
+	e4 := moveFromUCI(t, c.Game().Position(), "e2e4")
+	if _, err := c.Move(e4, start.Add(6*day)); err != nil {
+		t.Fatal(err)
+	}
+	// black has no leave so the white leave doesn't extend black's time
+	if d := c.Deadline(); !d.Equal(start.Add(9 * day)) {
+		t.Fatalf("expected black's deadline three days after the move but got %s", d)
+	}
+	e5 := moveFromUCI(t, c.Game().Position(), "e7e5")
+	if _, err := c.Move(e5, start.Add(10*day)); err == nil {
+		t.Fatal("expected a move after the deadline to be rejected")
+	}
+	g := c.Game()
+	if g.Outcome() != WhiteWon || g.GetTagPair("Termination").Value != "time forfeit" {
+		t.Fatalf("expected black to lose on time but got %s", g.Outcome())
+	}
+}
+
+func TestCorrespondenceConditionals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCorrespondenceGame(NewGame(), start, 72*time.Hour)
+	play := func(uci string) *Move {
+		reply, err := c.Move(moveFromUCI(t, c.Game().Position(), uci), start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return reply
+	}
+	if reply := play("e2e4"); reply != nil {
+		t.Fatalf("expected no conditional reply but got %s", reply)
+	}
+	if err := c.AddConditional(Black, moveFromUCI(t, c.Game().Position(), "e7e5")); err == nil {
+		t.Fatal("expected conditionals to be rejected on the player's turn")
+	}
+	play("c7c5")
+	pos := c.Game().Position()
+	nf3 := moveFromUCI(t, pos, "g1f3")
+	d6 := moveFromUCI(t, pos.Update(nf3), "d7d6")
+	d4 := moveFromUCI(t, pos.Update(nf3).Update(d6), "d2d4")
+	cxd4 := moveFromUCI(t, pos.Update(nf3).Update(d6).Update(d4), "c5d4")
+	nc3 := moveFromUCI(t, pos, "b1c3")
+	nc6 := moveFromUCI(t, pos.Update(nc3), "b8c6")
+	for _, seq := range [][]*Move{{nf3, d6, d4, cxd4}, {nc3, nc6}, {nf3, moveFromUCI(t, pos.Update(nf3), "b8c6")}} {
+		if err := c.AddConditional(Black, seq...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reply := play("g1f3"); reply == nil || reply.String() != "d7d6" {
+		t.Fatalf("expected the first matching conditional d7d6 but got %v", reply)
+	}
+	if seqs := c.Conditionals(Black); len(seqs) != 1 || len(seqs[0]) != 2 {
+		t.Fatalf("expected only the continuation of the played sequence but got %v", seqs)
+	}
+	if reply := play("d2d4"); reply == nil || reply.String() != "c5d4" {
+		t.Fatalf("expected the conditional capture but got %v", reply)
+	}
+	if len(c.Conditionals(Black)) != 0 || len(c.Game().Moves()) != 6 {
+		t.Fatalf("expected the conditionals to be used up after six moves but got %s", c.Game())
+	}
+}
+
+func moveFromUCI(t *testing.T, pos *Position, s string) *Move {
+	m, err := UCINotation{}.Decode(pos, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
@@ -0,0 +1,59 @@
+package chess
+
+// HalfMoveClock returns the number of half moves since the last capture
+// or pawn move which is used by the fifty move rule.
+func (pos *Position) HalfMoveClock() int {
+	return pos.halfMoveClock
+}
+
+// FullMoveNumber returns the number of the full move which starts at 1
+// and is incremented after black's move.
+func (pos *Position) FullMoveNumber() int {
+	return pos.moveCount
+}
+
+// ResetHalfMoveClock returns a copy of the position with the half move
+// clock set to zero.
+func (pos *Position) ResetHalfMoveClock() *Position {
+	cp := pos.copy()
+	cp.halfMoveClock = 0
+	return cp
+}
+
+// ResetFullMoveNumber returns a copy of the position with the full move
+// number set to one.
+func (pos *Position) ResetFullMoveNumber() *Position {
+	cp := pos.copy()
+	cp.moveCount = 1
+	return cp
+}
+
+// PliesSinceIrreversible returns the number of half moves since the last
+// irreversible move: a pawn move, a capture or a move that loses castling
+// rights.  Positions before an irreversible move can't repeat so draw
+// claims and adjudication only need to consider the plies since it.  If
+// the game started from a FEN without such a move, the FEN's half move
+// clock is included.
+func (g *Game) PliesSinceIrreversible() int {
+	if g.lastIrreversible > 0 {
+		return len(g.moves) - g.lastIrreversible
+	}
+	return len(g.moves) + g.positions[0].halfMoveClock
+}
+
+// LastIrreversiblePly returns the ply of the game's most recent
+// irreversible move where 1 is the first move of the game, or zero if
+// there hasn't been one.  Positions before it can't be repeated so it is
+// a reset point for repetition checks.
+func (g *Game) LastIrreversiblePly() int {
+	return g.lastIrreversible
+}
+
+func isIrreversible(pos *Position, m *Move) bool {
+	if m.IsNull() {
+		return false
+	}
+	return pos.board.Piece(m.s1).Type() == Pawn ||
+		m.HasTag(Capture) ||
+		len(pos.updateCastleRights(m)) != len(pos.castleRightsList())
+}
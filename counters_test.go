@@ -0,0 +1,62 @@
+package chess
+
+import "testing"
+
+func TestCounters(t *testing.T) {
+	pos := unsafeFEN("4k3/8/8/8/8/8/8/R3K3 b Q - 12 30")
+	if pos.HalfMoveClock() != 12 || pos.FullMoveNumber() != 30 {
+		t.Fatalf("unexpected counters %d %d", pos.HalfMoveClock(), pos.FullMoveNumber())
+	}
+	if s := pos.ResetHalfMoveClock().ResetFullMoveNumber().String(); s != "4k3/8/8/8/8/8/8/R3K3 b Q - 0 1" {
+		t.Fatalf("unexpected reset position %s", s)
+	}
+	if pos.HalfMoveClock() != 12 {
+		t.Fatal("expected reset to copy the position")
+	}
+}
+
+func TestPliesSinceIrreversible(t *testing.T) {
+	opt, err := FEN("4k3/8/8/8/8/8/8/R3K3 b Q - 12 30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt, UseNotation(LongAlgebraicNotation{}))
+	if n := g.PliesSinceIrreversible(); n != 12 {
+		t.Fatalf("expected 12 plies but got %d", n)
+	}
+	moves := []struct {
+		s     string
+		plies int
+	}{
+		{"Ke8d8", 13},
+		{"Ra1a2", 0},
+		{"Kd8e8", 1},
+		{"Ra2a3", 2},
+	}
+	for _, m := range moves {
+		if err := g.MoveStr(m.s); err != nil {
+			t.Fatal(err)
+		}
+		if n := g.PliesSinceIrreversible(); n != m.plies {
+			t.Fatalf("expected %d plies after %s but got %d", m.plies, m.s, n)
+		}
+	}
+}
+
+func TestLastIrreversiblePly(t *testing.T) {
+	g := NewGame()
+	for _, s := range []string{"e4", "e5", "Nf3", "Nc6", "Ng1", "Nb8", "Nf3"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if g.LastIrreversiblePly() != 2 {
+		t.Fatalf("expected last irreversible ply 2 but got %d", g.LastIrreversiblePly())
+	}
+	if g.Clone().PliesSinceIrreversible() != 5 {
+		t.Fatalf("expected 5 plies since irreversible but got %d", g.Clone().PliesSinceIrreversible())
+	}
+	if g.numOfRepitions() != 2 {
+		t.Fatalf("expected 2 repetitions but got %d", g.numOfRepitions())
+	}
+}
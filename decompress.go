@@ -0,0 +1,86 @@
+package chess
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sync"
+)
+
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+type decompressor struct {
+	magic []byte
+	open  func(io.Reader) (io.Reader, error)
+}
+
+var (
+	decompressorsMu sync.RWMutex
+	decompressors   = []decompressor{
+		{magic: []byte{0x1f, 0x8b}, open: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		}},
+		{magic: []byte("BZh"), open: func(r io.Reader) (io.Reader, error) {
+			return bzip2.NewReader(r), nil
+		}},
+	}
+)
+
+// RegisterDecompressor registers a decompressor for streams starting with
+// the magic bytes.  NewScanner and PGN decompress input that starts with
+// registered magic bytes.  Gzip and bzip2 are registered by default.  The
+// standard library has no zstd decoder so zstd compressed input, such as
+// the lichess database dumps, needs one registered:
+//
+//	chess.RegisterDecompressor([]byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.Reader, error) {
+//		return zstd.NewReader(r)
+//	})
+//
+// A decompressor registered with the same magic bytes as an existing one
+// replaces it.
+func RegisterDecompressor(magic []byte, open func(io.Reader) (io.Reader, error)) {
+	decompressorsMu.Lock()
+	defer decompressorsMu.Unlock()
+	d := decompressor{magic: append([]byte(nil), magic...), open: open}
+	for i, existing := range decompressors {
+		if bytes.Equal(existing.magic, d.magic) {
+			decompressors[i] = d
+			return
+		}
+	}
+	decompressors = append(decompressors, d)
+}
+
+// decompress returns a reader of r's decompressed contents if it starts
+// with the magic bytes of a registered decompressor or of r otherwise.  An
+// error is returned for zstd input without a registered decompressor.
+func decompress(r io.Reader) (*bufio.Reader, error) {
+	br := bufio.NewReader(r)
+	decompressorsMu.RLock()
+	defer decompressorsMu.RUnlock()
+	for _, d := range decompressors {
+		if b, _ := br.Peek(len(d.magic)); bytes.Equal(b, d.magic) {
+			dr, err := d.open(br)
+			if err != nil {
+				return nil, err
+			}
+			return bufio.NewReader(dr), nil
+		}
+	}
+	if b, _ := br.Peek(len(zstdMagic)); bytes.Equal(b, zstdMagic) {
+		return nil, errors.New("chess: zstd compressed PGN requires a decompressor registered with RegisterDecompressor")
+	}
+	return br, nil
+}
+
+// errReader is a reader that always fails with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,86 @@
+package chess
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+const compressedPGN = "[Event \"One\"]\n\n1. e4 e5 2. Nf3 1-0\n\n[Event \"Two\"]\n\n1. d4 d5 *\n"
+
+func scanEvents(t *testing.T, r io.Reader) []string {
+	t.Helper()
+	scanner := NewScanner(r)
+	events := []string{}
+	for scanner.Scan() {
+		events = append(events, scanner.Next().GetTagPair("Event").Value)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestScannerGzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(compressedPGN)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if events := scanEvents(t, &buf); strings.Join(events, ",") != "One,Two" {
+		t.Fatalf("expected events One,Two but got %v", events)
+	}
+}
+
+func TestPGNGzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte("1. e4 e5 2. Nf3 *"))
+	w.Close()
+	opt, err := PGN(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := NewGame(opt); len(g.Moves()) != 3 {
+		t.Fatalf("expected 3 moves but got %d", len(g.Moves()))
+	}
+}
+
+func TestScannerBzip2(t *testing.T) {
+	f, err := os.Open("assets/games.pgn.bz2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if events := scanEvents(t, f); strings.Join(events, ",") != "One,Two" {
+		t.Fatalf("expected events One,Two but got %v", events)
+	}
+}
+
+func TestScannerZstdUnregistered(t *testing.T) {
+	scanner := NewScanner(bytes.NewReader(append(zstdMagic, 0, 0, 0)))
+	if scanner.Scan() || scanner.Err() == nil {
+		t.Fatal("expected an error for zstd input without a decompressor")
+	}
+}
+
+func TestRegisterDecompressor(t *testing.T) {
+	magic := []byte("TEST")
+	RegisterDecompressor(magic, func(r io.Reader) (io.Reader, error) {
+		return strings.NewReader(compressedPGN), nil
+	})
+	defer func() {
+		decompressorsMu.Lock()
+		decompressors = decompressors[:len(decompressors)-1]
+		decompressorsMu.Unlock()
+	}()
+	if events := scanEvents(t, bytes.NewReader(magic)); strings.Join(events, ",") != "One,Two" {
+		t.Fatalf("expected events One,Two but got %v", events)
+	}
+}
@@ -0,0 +1,99 @@
+package chess
+
+import (
+	"fmt"
+	"time"
+)
+
+// GameDelta is the change to a game after a number of plies, such as the
+// move just played, which a server broadcasting games can send to its
+// clients instead of the whole game after each move.  Its fields are
+// basic types so it can be serialized with encoding/json or similar
+// packages, and fields that didn't change are omitted.
+type GameDelta struct {
+	// From is the number of plies the game had before the delta.
+	From int `json:"from"`
+	// Moves are the moves played since.
+	Moves []DeltaMove `json:"moves,omitempty"`
+	// FEN is the position after the moves.
+	FEN string `json:"fen"`
+	// Outcome and Method are set once the game is over.
+	Outcome Outcome `json:"outcome,omitempty"`
+	Method  string  `json:"method,omitempty"`
+}
+
+// DeltaMove is a move of a GameDelta with the mover's clock from the
+// move's [%clk] command if it has one.
+type DeltaMove struct {
+	SAN   string        `json:"san"`
+	UCI   string        `json:"uci"`
+	Clock time.Duration `json:"clock,omitempty"`
+}
+
+// Delta returns the change to the game after its first from plies, such as
+// len(g.Moves())-1 for the move just played.  An error is returned if from
+// is out of range.
+func (g *Game) Delta(from int) (GameDelta, error) {
+	if from != 0 {
+		if err := g.checkPly(from); err != nil {
+			return GameDelta{}, err
+		}
+	}
+	d := GameDelta{From: from, FEN: g.pos.String()}
+	for i, m := range g.moves[from:] {
+		pos := g.positions[from+i]
+		dm := DeltaMove{SAN: AlgebraicNotation{}.Encode(pos, m), UCI: UCINotation{}.Encode(pos, m)}
+		if clock, ok := m.Clock(); ok {
+			dm.Clock = clock
+		}
+		d.Moves = append(d.Moves, dm)
+	}
+	if g.outcome != NoOutcome {
+		d.Outcome = g.outcome
+		d.Method = g.method.String()
+	}
+	return d, nil
+}
+
+// ApplyDelta updates the game with a delta from Delta, as a client
+// following a broadcast game does.  An error is returned if the delta
+// doesn't start at the game's number of plies, a move is invalid or the
+// resulting position isn't the delta's position, in which case the game is
+// left unchanged.
+func (g *Game) ApplyDelta(d GameDelta) error {
+	if d.From != len(g.moves) {
+		return fmt.Errorf("chess: delta from ply %d can't be applied to a game with %d moves", d.From, len(g.moves))
+	}
+	// the delta is applied to a copy that shares the game's moves, which
+	// aren't modified, and replaces the game once it has been applied
+	cp := *g
+	cp.moves = append([]*Move(nil), g.moves...)
+	cp.positions = append([]*Position(nil), g.positions...)
+	for _, dm := range d.Moves {
+		m, err := UCINotation{}.Decode(cp.pos, dm.UCI)
+		if err != nil {
+			return err
+		}
+		if err := cp.Move(m); err != nil {
+			return err
+		}
+		if dm.Clock != 0 {
+			if err := cp.SetClock(len(cp.moves), dm.Clock); err != nil {
+				return err
+			}
+		}
+	}
+	if d.FEN != "" && d.FEN != cp.pos.String() {
+		return fmt.Errorf("chess: delta position %s doesn't match the game's position %s", d.FEN, cp.pos)
+	}
+	if d.Outcome != "" && cp.outcome == NoOutcome {
+		cp.outcome = d.Outcome
+		for m := NoMethod; m <= InsufficientMaterial; m++ {
+			if m.String() == d.Method {
+				cp.method = m
+			}
+		}
+	}
+	*g = cp
+	return nil
+}
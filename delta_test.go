@@ -0,0 +1,98 @@
+package chess
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGameDelta(t *testing.T) {
+	g := NewGame()
+	follower := NewGame()
+	for i, s := range []string{"f3", "e5", "g4", "Qh4#"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.SetClock(i+1, time.Duration(180-i)*time.Second); err != nil {
+			t.Fatal(err)
+		}
+		d, err := g.Delta(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 && string(b) != `{"from":0,"moves":[{"san":"f3","uci":"f2f3","clock":180000000000}],"fen":"rnbqkbnr/pppppppp/8/8/8/5P2/PPPPP1PP/RNBQKBNR b KQkq - 0 1"}` {
+			t.Fatalf("unexpected delta %s", b)
+		}
+		var received GameDelta
+		if err := json.Unmarshal(b, &received); err != nil {
+			t.Fatal(err)
+		}
+		if err := follower.ApplyDelta(received); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if follower.Outcome() != BlackWon || follower.Method() != Checkmate {
+		t.Fatalf("expected black to win by checkmate but got %s %s", follower.Outcome(), follower.Method())
+	}
+	if d, ok := follower.Moves()[3].Clock(); !ok || d != 177*time.Second {
+		t.Fatalf("expected the clock 2:57 but got %s", d)
+	}
+	if follower.String() != g.String() {
+		t.Fatalf("expected the follower's game\n%s\nbut got\n%s", g, follower)
+	}
+}
+
+func TestGameDeltaResign(t *testing.T) {
+	g := NewGame()
+	if err := g.MoveStr("e4"); err != nil {
+		t.Fatal(err)
+	}
+	follower := NewGame()
+	d, _ := g.Delta(0)
+	if err := follower.ApplyDelta(d); err != nil {
+		t.Fatal(err)
+	}
+	g.Resign(Black)
+	d, err := g.Delta(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Moves) != 0 || d.Outcome != WhiteWon || d.Method != "Resignation" {
+		t.Fatalf("expected a resignation without moves but got %+v", d)
+	}
+	if err := follower.ApplyDelta(d); err != nil {
+		t.Fatal(err)
+	}
+	if follower.Method() != Resignation {
+		t.Fatalf("expected resignation but got %s", follower.Method())
+	}
+	if err := follower.ApplyDelta(GameDelta{From: 3}); err == nil {
+		t.Fatal("expected an error applying a delta from the wrong ply")
+	}
+	if _, err := g.Delta(2); err == nil {
+		t.Fatal("expected an error for a ply out of range")
+	}
+}
+
+func TestApplyDeltaInvalid(t *testing.T) {
+	for _, d := range []GameDelta{
+		{From: 1, Moves: []DeltaMove{{UCI: "e7e5"}, {UCI: "a1a8"}}},
+		{From: 1, Moves: []DeltaMove{{UCI: "e7e5", Clock: time.Minute}}, FEN: startFEN},
+	} {
+		g := NewGame()
+		if err := g.MoveStr("e4"); err != nil {
+			t.Fatal(err)
+		}
+		before := g.String()
+		if err := g.ApplyDelta(d); err == nil {
+			t.Fatalf("expected an error applying %+v", d)
+		}
+		if len(g.Moves()) != 1 || len(g.Positions()) != 2 || g.String() != before {
+			t.Fatalf("expected the game to be unchanged but got %s", g)
+		}
+	}
+}
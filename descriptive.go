@@ -0,0 +1,413 @@
+package chess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DescriptiveNotation is English descriptive notation, which names the
+// files after the pieces that start on them and counts the ranks from the
+// side of the player making the move.  It was used in books and on
+// scoresheets before algebraic notation.  Moves are decoded in the common
+// variants of the notation, such as Kt for the knight, (Q) or /Q for a
+// promotion and R(1) or R/QR1 for the origin of a piece, and encoded in
+// the shortest unambiguous form.  Examples: P-K4, N-KB3, PxP, BxN,
+// R/1-Q1, P-K8=Q, O-O
+type DescriptiveNotation struct{}
+
+// String implements the fmt.Stringer interface and returns
+// the notation's name.
+func (DescriptiveNotation) String() string {
+	return "Descriptive Notation"
+}
+
+// Encode implements the Encoder interface.
+func (n DescriptiveNotation) Encode(pos *Position, m *Move) string {
+	if m.IsNull() {
+		return pgnNullMove
+	}
+	suffix := ""
+	switch getCheckChar(pos, m) {
+	case "+":
+		suffix = "ch"
+	case "#":
+		suffix = "mate"
+	}
+	if m.HasTag(KingSideCastle) {
+		return "O-O" + suffix
+	} else if m.HasTag(QueenSideCastle) {
+		return "O-O-O" + suffix
+	}
+	candidates := descriptiveCandidates(pos, m)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i]) < len(candidates[j])
+	})
+	for _, s := range candidates {
+		if d, err := n.Decode(pos, s); err == nil && d.s1 == m.s1 && d.s2 == m.s2 && d.promo == m.promo {
+			return s + suffix
+		}
+	}
+	// the origin square and destination always identify the move
+	return candidates[len(candidates)-1] + suffix
+}
+
+// descriptiveCandidates returns the ways of writing the move from the
+// least to the most specific.
+func descriptiveCandidates(pos *Position, m *Move) []string {
+	turn := pos.turn
+	p := pos.board.Piece(m.s1)
+	pieces := descriptivePieces(p.Type(), m.s1)
+	targets := []string{}
+	sep := "-"
+	if m.HasTag(Capture) || m.HasTag(EnPassant) {
+		sep = "x"
+		captured := m.s2
+		if m.HasTag(EnPassant) {
+			captured = getSquare(m.s2.File(), m.s1.Rank())
+		}
+		for _, t := range descriptivePieces(pos.board.Piece(captured).Type(), captured) {
+			targets = append(targets, t, t+"/"+descriptiveSquare(m.s2, turn, false))
+		}
+	} else {
+		targets = append(targets, descriptiveSquare(m.s2, turn, true), descriptiveSquare(m.s2, turn, false))
+	}
+	origins := []string{"", "/" + descriptiveRank(m.s1, turn), "/" + descriptiveSquare(m.s1, turn, false)}
+	promo := ""
+	if m.promo != NoPieceType {
+		promo = "=" + charFromPieceType(m.promo)
+	}
+	candidates := []string{}
+	for _, origin := range origins {
+		for _, piece := range pieces {
+			for _, target := range targets {
+				candidates = append(candidates, piece+origin+sep+target+promo)
+			}
+		}
+	}
+	return candidates
+}
+
+// descriptivePieces returns the designations of the piece on the square
+// from the least to the most specific, such as P, BP and KBP.
+func descriptivePieces(pt PieceType, sq Square) []string {
+	switch pt {
+	case Pawn:
+		return []string{"P", descriptiveFileShortNames[sq.File()] + "P", descriptiveFileNames[sq.File()] + "P"}
+	case King, Queen:
+		return []string{charFromPieceType(pt)}
+	}
+	side := "Q"
+	if sq.File() >= FileE {
+		side = "K"
+	}
+	return []string{charFromPieceType(pt), side + charFromPieceType(pt)}
+}
+
+// descriptiveSquare returns the square from the point of view of the
+// color such as KB3, or B3 if short is true.
+func descriptiveSquare(sq Square, c Color, short bool) string {
+	name := descriptiveFileNames[sq.File()]
+	if short {
+		name = descriptiveFileShortNames[sq.File()]
+	}
+	return name + descriptiveRank(sq, c)
+}
+
+// descriptiveRank returns the rank of the square counted from the color's
+// side of the board.
+func descriptiveRank(sq Square, c Color) string {
+	r := sq.Rank()
+	if c == Black {
+		r = Rank8 - r
+	}
+	return r.String()
+}
+
+// Decode implements the Decoder interface.
+func (DescriptiveNotation) Decode(pos *Position, s string) (*Move, error) {
+	if isNullMoveText(s) {
+		return NullMove(), nil
+	}
+	text := removeSubstrings(s, " ", "?", "!", "+", "#", "e.p.", "ep", "dblch", "disch", "ch", "mate")
+	if castle, ok := descriptiveCastle(text); ok {
+		for _, m := range pos.ValidMoves() {
+			if m.HasTag(castle) {
+				return m, nil
+			}
+		}
+		return nil, fmt.Errorf("chess: could not decode descriptive notation %s for position %s", s, pos)
+	}
+	move, err := parseDescriptive(text, pos.turn)
+	if err != nil {
+		return nil, fmt.Errorf("chess: invalid descriptive notation %s", s)
+	}
+	matches := []*Move{}
+	for _, m := range pos.ValidMoves() {
+		if move.matches(pos, m) {
+			matches = append(matches, m)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("chess: could not decode descriptive notation %s for position %s", s, pos)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("chess: ambiguous descriptive notation %s for position %s", s, pos)
+}
+
+// descriptiveCastle returns the castling tag of castling text such as
+// O-O, 0-0-0 or Castles QR.
+func descriptiveCastle(text string) (MoveTag, bool) {
+	switch strings.ToUpper(strings.Replace(text, "0", "O", -1)) {
+	case "O-O", "CASTLES", "CASTLESK", "CASTLESKR":
+		return KingSideCastle, true
+	case "O-O-O", "CASTLESQ", "CASTLESQR":
+		return QueenSideCastle, true
+	}
+	return 0, false
+}
+
+// descriptiveMove is the description of a move in descriptive notation.
+type descriptiveMove struct {
+	piece PieceType
+	// files limit the files the piece or pawn is on or are nil
+	files []File
+	// origin limits the squares the piece moves from or is nil
+	origin func(sq Square) bool
+	// squares are the squares the piece may move to when it doesn't
+	// capture
+	squares []Square
+	capture bool
+	// target and targetFiles describe the captured piece
+	target      PieceType
+	targetFiles []File
+	// destination limits the squares of a capture or is nil
+	destination func(sq Square) bool
+	promo       PieceType
+}
+
+// parseDescriptive parses the text of a move without check symbols and
+// annotations such as NxP/KB4, P-K8(Q) or R(1)-Q1.
+func parseDescriptive(text string, turn Color) (*descriptiveMove, error) {
+	i := strings.IndexAny(text, "-x")
+	if i == -1 {
+		return nil, fmt.Errorf("chess: invalid descriptive notation %s", text)
+	}
+	left, right := text[:i], text[i+1:]
+	move := &descriptiveMove{capture: text[i] == 'x', promo: NoPieceType}
+	left, qualifier := splitDescriptiveQualifier(left)
+	var ok bool
+	if move.piece, move.files, ok = parseDescriptivePiece(left); !ok {
+		return nil, fmt.Errorf("chess: invalid descriptive notation %s", text)
+	}
+	if qualifier != "" {
+		if move.origin, ok = parseDescriptiveQualifier(qualifier, turn); !ok {
+			return nil, fmt.Errorf("chess: invalid descriptive notation %s", text)
+		}
+	}
+	right, promo := splitDescriptivePromo(right)
+	if promo != "" {
+		if move.promo, _, ok = parseDescriptivePiece(promo); !ok || move.promo == Pawn || move.promo == King {
+			return nil, fmt.Errorf("chess: invalid descriptive notation %s", text)
+		}
+	}
+	if !move.capture {
+		if move.squares, ok = parseDescriptiveSquare(right, turn); !ok {
+			return nil, fmt.Errorf("chess: invalid descriptive notation %s", text)
+		}
+		return move, nil
+	}
+	right, qualifier = splitDescriptiveQualifier(right)
+	if move.target, move.targetFiles, ok = parseDescriptivePiece(right); !ok {
+		return nil, fmt.Errorf("chess: invalid descriptive notation %s", text)
+	}
+	if qualifier != "" {
+		if move.destination, ok = parseDescriptiveQualifier(qualifier, turn); !ok {
+			return nil, fmt.Errorf("chess: invalid descriptive notation %s", text)
+		}
+	}
+	return move, nil
+}
+
+// matches returns true if the valid move fits the description.
+func (d *descriptiveMove) matches(pos *Position, m *Move) bool {
+	if pos.board.Piece(m.s1).Type() != d.piece || !containsFile(d.files, m.s1.File()) {
+		return false
+	}
+	if d.origin != nil && !d.origin(m.s1) {
+		return false
+	}
+	if m.promo != NoPieceType || d.promo != NoPieceType {
+		promo := d.promo
+		if promo == NoPieceType {
+			// a promotion without a piece was to a queen
+			promo = Queen
+		}
+		if m.promo != promo {
+			return false
+		}
+	}
+	if !d.capture {
+		for _, sq := range d.squares {
+			if sq == m.s2 {
+				return true
+			}
+		}
+		return false
+	}
+	captured := m.s2
+	if m.HasTag(EnPassant) {
+		captured = getSquare(m.s2.File(), m.s1.Rank())
+	} else if !m.HasTag(Capture) {
+		return false
+	}
+	if pos.board.Piece(captured).Type() != d.target || !containsFile(d.targetFiles, captured.File()) {
+		return false
+	}
+	return d.destination == nil || d.destination(m.s2)
+}
+
+// splitDescriptiveQualifier splits a designation such as R(QR1) or N/1
+// into the piece and the qualifier.
+func splitDescriptiveQualifier(s string) (string, string) {
+	if i := strings.IndexByte(s, '/'); i != -1 {
+		return s[:i], s[i+1:]
+	}
+	if i := strings.IndexByte(s, '('); i != -1 && strings.HasSuffix(s, ")") {
+		return s[:i], s[i+1 : len(s)-1]
+	}
+	return s, ""
+}
+
+// splitDescriptivePromo splits the destination of a promotion such as
+// K8=Q, K8(Q) or K8/Q into the destination and the piece.
+func splitDescriptivePromo(s string) (string, string) {
+	for _, sep := range []string{"=", "(", "/"} {
+		i := strings.LastIndex(s, sep)
+		if i == -1 {
+			continue
+		}
+		piece := strings.TrimSuffix(s[i+1:], ")")
+		if _, _, ok := parseDescriptivePiece(piece); ok && piece != "P" {
+			return s[:i], piece
+		}
+	}
+	return s, ""
+}
+
+// parseDescriptivePiece parses a piece such as N, KKt or QBP and returns
+// its type and the files it may be on, which are nil for any file.
+func parseDescriptivePiece(s string) (PieceType, []File, bool) {
+	if strings.HasSuffix(s, "P") {
+		if s == "P" {
+			return Pawn, nil, true
+		}
+		files, ok := descriptiveFiles[s[:len(s)-1]]
+		return Pawn, files, ok
+	}
+	switch s {
+	case "K":
+		return King, nil, true
+	case "Q":
+		return Queen, nil, true
+	}
+	for _, side := range []struct {
+		prefix string
+		files  []File
+	}{{"", nil}, {"K", []File{FileE, FileF, FileG, FileH}}, {"Q", []File{FileA, FileB, FileC, FileD}}} {
+		if !strings.HasPrefix(s, side.prefix) {
+			continue
+		}
+		switch s[len(side.prefix):] {
+		case "N", "Kt":
+			return Knight, side.files, true
+		case "B":
+			return Bishop, side.files, true
+		case "R":
+			return Rook, side.files, true
+		}
+	}
+	return NoPieceType, nil, false
+}
+
+// parseDescriptiveSquare parses a square such as KB3 or B3 from the point
+// of view of the color and returns the squares it may be.
+func parseDescriptiveSquare(s string, c Color) ([]Square, bool) {
+	if len(s) < 2 || s[len(s)-1] < '1' || s[len(s)-1] > '8' {
+		return nil, false
+	}
+	files, ok := descriptiveFiles[s[:len(s)-1]]
+	if !ok {
+		return nil, false
+	}
+	r := Rank(s[len(s)-1] - '1')
+	if c == Black {
+		r = Rank8 - r
+	}
+	squares := []Square{}
+	for _, f := range files {
+		squares = append(squares, getSquare(f, r))
+	}
+	return squares, true
+}
+
+// parseDescriptiveQualifier parses the qualifier of a piece, which is a
+// rank such as 1, a square such as QR1 or a file such as QR.
+func parseDescriptiveQualifier(s string, c Color) (func(sq Square) bool, bool) {
+	if len(s) == 1 && s[0] >= '1' && s[0] <= '8' {
+		rank := s
+		return func(sq Square) bool { return descriptiveRank(sq, c) == rank }, true
+	}
+	if squares, ok := parseDescriptiveSquare(s, c); ok {
+		return func(sq Square) bool {
+			for _, square := range squares {
+				if sq == square {
+					return true
+				}
+			}
+			return false
+		}, true
+	}
+	if files, ok := descriptiveFiles[s]; ok {
+		return func(sq Square) bool { return containsFile(files, sq.File()) }, true
+	}
+	return nil, false
+}
+
+// containsFile returns true if the file is one of the files or if files
+// is nil.
+func containsFile(files []File, f File) bool {
+	if files == nil {
+		return true
+	}
+	for _, file := range files {
+		if file == f {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// descriptiveFiles are the files named by each name
+	descriptiveFiles = map[string][]File{
+		"QR":  {FileA},
+		"QN":  {FileB},
+		"QKt": {FileB},
+		"QB":  {FileC},
+		"Q":   {FileD},
+		"K":   {FileE},
+		"KB":  {FileF},
+		"KN":  {FileG},
+		"KKt": {FileG},
+		"KR":  {FileH},
+		"R":   {FileA, FileH},
+		"N":   {FileB, FileG},
+		"Kt":  {FileB, FileG},
+		"B":   {FileC, FileF},
+	}
+	descriptiveFileNames      = []string{"QR", "QN", "QB", "Q", "K", "KB", "KN", "KR"}
+	descriptiveFileShortNames = []string{"R", "N", "B", "Q", "K", "B", "N", "R"}
+)
@@ -0,0 +1,82 @@
+package chess
+
+import "testing"
+
+func TestDescriptiveDecode(t *testing.T) {
+	tests := []struct {
+		moves []string
+		san   string
+	}{
+		{[]string{"P-K4"}, "e4"},
+		{[]string{"P-K4", "P-K4", "N-KB3"}, "Nf3"},
+		{[]string{"P-K4", "P-QB4", "Kt-KB3"}, "Nf3"},
+		{[]string{"P-K4", "P-Q4", "PxP"}, "exd5"},
+		{[]string{"P-K4", "P-Q4", "P-K5", "P-KB4", "PxP e.p."}, "exf6"},
+		{[]string{"P-K4", "P-K4", "N-KB3", "N-QB3", "B-N5"}, "Bb5"},
+		{[]string{"P-K4", "P-K4", "N-KB3", "N-QB3", "B-N5", "P-QR3", "BxN"}, "Bxc6"},
+		{[]string{"P-K4", "P-K4", "N-KB3", "N-QB3", "B-N5", "P-QR3", "BxN", "QPxB"}, "dxc6"},
+		{[]string{"P-K4", "P-K4", "N-KB3", "N-QB3", "B-B4", "B-B4", "O-O"}, "O-O"},
+		{[]string{"P-K4", "P-K4", "Q-R5", "N-QB3", "B-B4", "N-B3", "QxBP mate"}, "Qxf7#"},
+		{[]string{"N-KB3", "N-KB3", "N-B3", "N-B3", "N-Q4", "N-Q4", "N(QB3)-N5"}, "Ncb5"},
+		{[]string{"N-KB3", "N-KB3", "N-Q4", "N-Q4", "N-QB3", "P-K3", "N(3)-N5"}, "Ncb5"},
+		{[]string{"P-QR4", "P-K4", "R-R3", "P-K5", "R-KR3", "P-Q4", "R(KR3)-Q3"}, "Rd3"},
+	}
+	for _, test := range tests {
+		g := NewGame(UseNotation(DescriptiveNotation{}))
+		for _, s := range test.moves[:len(test.moves)-1] {
+			if err := g.MoveStr(s); err != nil {
+				t.Fatalf("%v: %v", test.moves, err)
+			}
+		}
+		last := test.moves[len(test.moves)-1]
+		m, err := DescriptiveNotation{}.Decode(g.Position(), last)
+		if err != nil {
+			t.Fatalf("%v: %v", test.moves, err)
+		}
+		if s := (AlgebraicNotation{}).Encode(g.Position(), m); s != test.san {
+			t.Fatalf("expected %s to decode to %s but got %s", last, test.san, s)
+		}
+	}
+}
+
+func TestDescriptiveDecodeErrors(t *testing.T) {
+	pos := unsafeFEN("r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3")
+	for _, s := range []string{"N-KB3", "P-K5", "B-X5", "K4", "NxQ"} {
+		if _, err := (DescriptiveNotation{}).Decode(pos, s); err == nil {
+			t.Fatalf("expected %s to be rejected", s)
+		}
+	}
+}
+
+func TestDescriptiveEncode(t *testing.T) {
+	tests := []struct {
+		fen         string
+		san         string
+		descriptive string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Nf3", "N-KB3"},
+		{"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", "e5", "P-K4"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 2", "exd5", "PxP"},
+		{"r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 0 4", "Bxc6", "BxN"},
+		{"r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 0 4", "Nxe5", "NxP"},
+		{"r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - 4 4", "Qxf7#", "QxBPmate"},
+		{"r1bqkbnr/pppp1ppp/2n5/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4", "O-O", "O-O"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b8=Q+", "P-N8=Qch"},
+		{"4k3/8/8/8/8/8/4K3/R6R w - - 0 1", "Rad1", "QR-Q1"},
+		{"4k3/8/8/R7/8/8/4K3/R7 w - - 0 1", "R1a3", "R/1-R3"},
+	}
+	for _, test := range tests {
+		pos := unsafeFEN(test.fen)
+		m, err := AlgebraicNotation{}.Decode(pos, test.san)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := DescriptiveNotation{}.Encode(pos, m)
+		if s != test.descriptive {
+			t.Fatalf("expected %s to be encoded as %s but got %s", test.san, test.descriptive, s)
+		}
+		if decoded, err := (DescriptiveNotation{}).Decode(pos, s); err != nil || decoded != m {
+			t.Fatalf("expected %s to decode to %s but got %v", s, test.san, err)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Package dgt is a driver for DGT electronic chess boards.  It speaks the DGT
+// serial protocol over any io.ReadWriter (such as a serial or USB port opened
+// at 9600 baud 8N1) and reconciles the physical piece placement against a game
+// to detect moves.
+package dgt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/notnil/chess"
+)
+
+// commands sent to the board
+const (
+	cmdReset       byte = 0x40
+	cmdSendBoard   byte = 0x42
+	cmdSendUpdates byte = 0x44
+)
+
+// messages received from the board
+const (
+	msgBit         byte = 0x80
+	msgBoardDump        = msgBit | 0x06
+	msgFieldUpdate      = msgBit | 0x0e
+)
+
+var (
+	dgtPieces = map[byte]chess.Piece{
+		0x00: chess.NoPiece,
+		0x01: chess.WhitePawn,
+		0x02: chess.WhiteRook,
+		0x03: chess.WhiteKnight,
+		0x04: chess.WhiteBishop,
+		0x05: chess.WhiteKing,
+		0x06: chess.WhiteQueen,
+		0x07: chess.BlackPawn,
+		0x08: chess.BlackRook,
+		0x09: chess.BlackKnight,
+		0x0a: chess.BlackBishop,
+		0x0b: chess.BlackKing,
+		0x0c: chess.BlackQueen,
+	}
+)
+
+// Rotated is an option for the New function for boards set up with the
+// black pieces on the side of the cable connector.
+func Rotated(d *Driver) {
+	d.rotated = true
+}
+
+// Driver reads piece placement from a DGT board and emits the moves made
+// on it.  Driver is safe for concurrent use.
+type Driver struct {
+	rw      io.ReadWriter
+	r       *bufio.Reader
+	game    *chess.Game
+	squares map[chess.Square]chess.Piece
+	rotated bool
+	moves   chan *chess.Move
+	mu      *sync.RWMutex
+	// pending is a rook move that may be the first half of castling
+	pending *chess.Move
+	// flushed are pending moves applied by Move that haven't been emitted
+	flushed []*chess.Move
+}
+
+// New constructs a driver that reads from and writes to the given port and
+// reconciles the board with the game.  New resets the board and requests a
+// full board dump followed by field updates.  Once created the driver is
+// started with the Run method.
+func New(rw io.ReadWriter, game *chess.Game, opts ...func(d *Driver)) (*Driver, error) {
+	d := &Driver{
+		rw:      rw,
+		r:       bufio.NewReader(rw),
+		game:    game,
+		squares: map[chess.Square]chess.Piece{},
+		moves:   make(chan *chess.Move, 16),
+		mu:      &sync.RWMutex{},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if _, err := rw.Write([]byte{cmdReset, cmdSendBoard, cmdSendUpdates}); err != nil {
+		return nil, fmt.Errorf("dgt: failed to initialize board %w", err)
+	}
+	return d, nil
+}
+
+// Moves returns a channel of moves detected on the physical board.  Detected
+// moves have already been applied to the game.  The channel is closed
+// when Run returns.
+func (d *Driver) Moves() <-chan *chess.Move {
+	return d.moves
+}
+
+// Board returns the piece placement most recently reported by the board.
+func (d *Driver) Board() *chess.Board {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return chess.NewBoard(d.squares)
+}
+
+// Game returns the game the driver reconciles against.  The game should
+// only be modified through the driver's Move method while Run is active.
+func (d *Driver) Game() *chess.Game {
+	return d.game
+}
+
+// Move applies a move (for example an engine's reply) to the game.  The
+// move isn't emitted on the Moves channel and the board is in sync again once
+// the move has been made on it.  A rook move held as the possible start of
+// castling is applied first since the opponent is replying to it, and is
+// emitted with the next board update.
+func (d *Driver) Move(m *chess.Move) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending != nil {
+		if err := d.game.Move(d.pending); err != nil {
+			return err
+		}
+		d.flushed = append(d.flushed, d.pending)
+		d.pending = nil
+	}
+	return d.game.Move(m)
+}
+
+// Run reads messages from the board until the reader returns an error and
+// emits detected moves.  Run returns nil if the reader reaches io.EOF and
+// the context's error once the game's context is canceled.  A read that is
+// blocked waiting for the board isn't interrupted, so Run returns after
+// the board's next message or when the reader is closed.
+func (d *Driver) Run() error {
+	defer close(d.moves)
+	ctx := d.game.Context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		id, data, err := readMessage(d.r)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		switch id {
+		case msgBoardDump:
+			if len(data) != 64 {
+				return fmt.Errorf("dgt: invalid board dump length %d", len(data))
+			}
+			d.mu.Lock()
+			for i, b := range data {
+				d.setSquare(byte(i), b)
+			}
+			d.mu.Unlock()
+		case msgFieldUpdate:
+			if len(data) != 2 {
+				return fmt.Errorf("dgt: invalid field update length %d", len(data))
+			}
+			d.mu.Lock()
+			d.setSquare(data[0], data[1])
+			d.mu.Unlock()
+		default:
+			continue
+		}
+		for _, m := range d.reconcile() {
+			select {
+			case d.moves <- m:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (d *Driver) setSquare(field, code byte) {
+	if field > 63 {
+		return
+	}
+	// fields are numbered from a8 to h1
+	sq := chess.Square((7-int(field)/8)*8 + int(field)%8)
+	if d.rotated {
+		sq = chess.Square(63 - int(sq))
+	}
+	p := dgtPieces[code]
+	if p == chess.NoPiece {
+		delete(d.squares, sq)
+		return
+	}
+	d.squares[sq] = p
+}
+
+// reconcile applies and returns the moves that bring the game to the
+// physical placement, if any, after the moves flushed by Move.
+// Intermediate placements (such as a lifted piece) don't match a move and
+// are ignored.  A rook move that could be the first half of castling is
+// held until the king lands, and is only applied as a rook move once the
+// opponent's reply is on the board or is passed to Move.
+func (d *Driver) reconcile() []*chess.Move {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	moves := append(d.flushed, d.reconcileBoard()...)
+	d.flushed = nil
+	return moves
+}
+
+// reconcileBoard is reconcile without the flushed moves.  The driver must
+// be locked.
+func (d *Driver) reconcileBoard() []*chess.Move {
+	b := chess.NewBoard(d.squares)
+	pos := d.game.Position()
+	if len(pos.Board().Diff(b)) == 0 {
+		d.pending = nil
+		return nil
+	}
+	if m := pos.InferMove(b); m != nil {
+		if isCastleRookMove(pos, m) {
+			d.pending = m
+			return nil
+		}
+		d.pending = nil
+		if err := d.game.Move(m); err != nil {
+			return nil
+		}
+		return []*chess.Move{m}
+	}
+	if d.pending == nil {
+		return nil
+	}
+	reply := pos.Update(d.pending).InferMove(b)
+	if reply == nil {
+		return nil
+	}
+	moves := []*chess.Move{d.pending, reply}
+	d.pending = nil
+	for _, m := range moves {
+		if err := d.game.Move(m); err != nil {
+			return nil
+		}
+	}
+	return moves
+}
+
+// isCastleRookMove returns true if the move is a rook move to the square
+// its rook ends on when castling, which is how castling starts when the
+// rook is moved before the king.
+func isCastleRookMove(pos *chess.Position, m *chess.Move) bool {
+	if pos.Board().Piece(m.S1()).Type() != chess.Rook {
+		return false
+	}
+	// the rook ends on the f or d file of its rank
+	kingSide := chess.Square(int(m.S1().Rank())*8 + int(chess.FileF))
+	queenSide := chess.Square(int(m.S1().Rank())*8 + int(chess.FileD))
+	for _, c := range pos.ValidMoves() {
+		switch {
+		case c.HasTag(chess.KingSideCastle) && m.S2() == kingSide:
+			return true
+		case c.HasTag(chess.QueenSideCastle) && m.S2() == queenSide:
+			return true
+		}
+	}
+	return false
+}
+
+func readMessage(r *bufio.Reader) (byte, []byte, error) {
+	id, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	if id&msgBit == 0 {
+		return 0, nil, errors.New("dgt: invalid message id")
+	}
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	// the length is encoded in two 7 bit bytes and includes the header
+	size := int(header[0])<<7 | int(header[1])
+	if size < 3 {
+		return 0, nil, fmt.Errorf("dgt: invalid message length %d", size)
+	}
+	data := make([]byte, size-3)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	return id, data, nil
+}
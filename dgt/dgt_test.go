@@ -0,0 +1,190 @@
+package dgt_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/dgt"
+)
+
+type port struct {
+	io.Reader
+	written bytes.Buffer
+}
+
+func (p *port) Write(b []byte) (int, error) {
+	return p.written.Write(b)
+}
+
+// field returns the DGT field number of the square.
+func field(sq chess.Square) byte {
+	return byte((7-int(sq.Rank()))*8 + int(sq.File()))
+}
+
+func TestDriver(t *testing.T) {
+	dump := []byte{0x86, 0, 67}
+	dump = append(dump, 0x08, 0x09, 0x0a, 0x0c, 0x0b, 0x0a, 0x09, 0x08)
+	dump = append(dump, bytes.Repeat([]byte{0x07}, 8)...)
+	dump = append(dump, make([]byte, 32)...)
+	dump = append(dump, bytes.Repeat([]byte{0x01}, 8)...)
+	dump = append(dump, 0x02, 0x03, 0x04, 0x06, 0x05, 0x04, 0x03, 0x02)
+	msgs := append([]byte{}, dump...)
+	// lift the e2 pawn and place it on e4
+	msgs = append(msgs, 0x8e, 0, 5, field(chess.E2), 0x00)
+	msgs = append(msgs, 0x8e, 0, 5, field(chess.E4), 0x01)
+	// lift and replace a knight which isn't a move
+	msgs = append(msgs, 0x8e, 0, 5, field(chess.G8), 0x00)
+	msgs = append(msgs, 0x8e, 0, 5, field(chess.G8), 0x09)
+	// play e5
+	msgs = append(msgs, 0x8e, 0, 5, field(chess.E7), 0x00)
+	msgs = append(msgs, 0x8e, 0, 5, field(chess.E5), 0x07)
+
+	p := &port{Reader: bytes.NewReader(msgs)}
+	g := chess.NewGame()
+	d, err := dgt.New(p, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p.written.Bytes(), []byte{0x40, 0x42, 0x44}) {
+		t.Fatalf("expected initialization commands but got %x", p.written.Bytes())
+	}
+	if err := d.Run(); err != nil {
+		t.Fatal(err)
+	}
+	moves := []string{}
+	for m := range d.Moves() {
+		moves = append(moves, m.String())
+	}
+	if len(moves) != 2 || moves[0] != "e2e4" || moves[1] != "e7e5" {
+		t.Fatalf("expected moves e2e4 e7e5 but got %v", moves)
+	}
+	if len(g.Moves()) != 2 {
+		t.Fatalf("expected game to have 2 moves but got %d", len(g.Moves()))
+	}
+}
+
+func TestDriverContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g := chess.NewGame(chess.GameContext(ctx))
+	d, err := dgt.New(&port{Reader: bytes.NewReader(nil)}, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Run(); err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+	if _, ok := <-d.Moves(); ok {
+		t.Fatal("expected the moves channel to be closed")
+	}
+}
+
+// dump returns a board dump message of the position's board.
+func dump(pos *chess.Position) []byte {
+	codes := map[chess.Piece]byte{
+		chess.WhitePawn: 0x01, chess.WhiteRook: 0x02, chess.WhiteKnight: 0x03,
+		chess.WhiteBishop: 0x04, chess.WhiteKing: 0x05, chess.WhiteQueen: 0x06,
+		chess.BlackPawn: 0x07, chess.BlackRook: 0x08, chess.BlackKnight: 0x09,
+		chess.BlackBishop: 0x0a, chess.BlackKing: 0x0b, chess.BlackQueen: 0x0c,
+	}
+	msg := make([]byte, 67)
+	msg[0], msg[2] = 0x86, 67
+	for sq, p := range pos.Board().SquareMap() {
+		msg[3+int(field(sq))] = codes[p]
+	}
+	return msg
+}
+
+func TestDriverCastleRookFirst(t *testing.T) {
+	fen, err := chess.FEN("r3k3/8/8/8/8/8/8/4K2R w Kq - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		updates  [][2]byte
+		expected string
+	}{
+		// the rook is moved before the king when castling
+		{[][2]byte{{field(chess.H1), 0}, {field(chess.F1), 0x02}, {field(chess.E1), 0}, {field(chess.G1), 0x05}}, "[e1g1]"},
+		// a rook move is emitted with the opponent's reply
+		{[][2]byte{{field(chess.H1), 0}, {field(chess.F1), 0x02}, {field(chess.E8), 0}, {field(chess.C8), 0x0b}, {field(chess.A8), 0}, {field(chess.D8), 0x08}}, "[h1f1 e8c8]"},
+	}
+	for _, test := range tests {
+		g := chess.NewGame(fen)
+		msgs := dump(g.Position())
+		for _, u := range test.updates {
+			msgs = append(msgs, 0x8e, 0, 5, u[0], u[1])
+		}
+		d, err := dgt.New(&port{Reader: bytes.NewReader(msgs)}, g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Run(); err != nil {
+			t.Fatal(err)
+		}
+		moves := []string{}
+		for m := range d.Moves() {
+			moves = append(moves, m.String())
+		}
+		if s := fmt.Sprint(moves); s != test.expected {
+			t.Fatalf("expected moves %s but got %s", test.expected, s)
+		}
+		if len(g.Moves()) != len(moves) {
+			t.Fatalf("expected the game to have the moves %s but got %v", test.expected, g.Moves())
+		}
+	}
+}
+
+func TestDriverRookMoveEngineReply(t *testing.T) {
+	fen, err := chess.FEN("r3k3/8/8/8/8/8/8/4K2R w Kq - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := chess.NewGame(fen)
+	r, w := io.Pipe()
+	d, err := dgt.New(&port{Reader: r}, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- d.Run() }()
+	msgs := dump(g.Position())
+	// the rook move could be the start of castling so it's held
+	msgs = append(msgs, 0x8e, 0, 5, field(chess.H1), 0, 0x8e, 0, 5, field(chess.F1), 0x02)
+	if _, err := w.Write(msgs); err != nil {
+		t.Fatal(err)
+	}
+	// the engine replies to the rook move once the driver holds it
+	reply, err := chess.UCINotation{}.Decode(nil, "a8a7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for start := time.Now(); d.Move(reply) != nil; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("expected the engine's reply to be applied after the rook move")
+		}
+	}
+	if s := fmt.Sprint(g.Moves()); s != "[h1f1 a8a7]" {
+		t.Fatalf("expected the rook move before the engine's reply but got %s", s)
+	}
+	// the engine's reply is made on the board
+	if _, err := w.Write([]byte{0x8e, 0, 5, field(chess.A8), 0, 0x8e, 0, 5, field(chess.A7), 0x08}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	moves := []string{}
+	for m := range d.Moves() {
+		moves = append(moves, m.String())
+	}
+	if s := fmt.Sprint(moves); s != "[h1f1]" {
+		t.Fatalf("expected the rook move to be emitted but got %s", s)
+	}
+}
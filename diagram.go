@@ -0,0 +1,139 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseDiagram parses a text board diagram, such as the output of the
+// Board's Draw method, into a board.  Pieces may be given as FEN letters
+// (KQRBNP / kqrbnp) or Unicode chess symbols and empty squares as '-', '.'
+// or '·'.  Borders ('|', '+', '-', '='), file headers, and rank labels are
+// ignored.  Ranks are read top down from the eighth rank unless each row is
+// labeled with its rank number in which case the labels are used.
+// An error is returned if eight ranks of eight squares can't be found.
+func ParseDiagram(s string) (*Board, error) {
+	type row struct {
+		rank  Rank
+		cells []Piece
+	}
+	rows := []row{}
+	labeled := true
+	reversed := false
+	for _, line := range strings.Split(s, "\n") {
+		if strings.ToLower(strings.Join(strings.Fields(line), "")) == "hgfedcba" {
+			reversed = true
+			continue
+		}
+		cells, rank, ok := diagramRow(line)
+		if !ok {
+			continue
+		}
+		if rank == -1 {
+			labeled = false
+		}
+		rows = append(rows, row{rank: rank, cells: cells})
+	}
+	if len(rows) != numOfSquaresInRow {
+		return nil, fmt.Errorf("chess: diagram has %d ranks but requires 8", len(rows))
+	}
+	m := map[Square]Piece{}
+	seen := map[Rank]bool{}
+	for i, r := range rows {
+		rank := Rank(7 - i)
+		if reversed {
+			rank = Rank(i)
+		}
+		if labeled {
+			rank = r.rank
+		}
+		if seen[rank] {
+			return nil, fmt.Errorf("chess: diagram has duplicate rank %s", rank)
+		}
+		seen[rank] = true
+		for f, p := range r.cells {
+			file := File(f)
+			if reversed {
+				file = File(7 - f)
+			}
+			if p != NoPiece {
+				m[getSquare(file, rank)] = p
+			}
+		}
+	}
+	return NewBoard(m), nil
+}
+
+// diagramRow returns the pieces of a diagram line and its rank label (or -1
+// if the line is unlabeled).  ok is false if the line isn't a row of squares.
+func diagramRow(line string) (cells []Piece, rank Rank, ok bool) {
+	runes := []rune{}
+	for _, r := range line {
+		switch r {
+		case ' ', '\t', '\r', '|':
+		default:
+			runes = append(runes, r)
+		}
+	}
+	if isDiagramBorder(runes) {
+		return nil, 0, false
+	}
+	rank = -1
+	if len(runes) == numOfSquaresInRow+1 || len(runes) == numOfSquaresInRow+2 {
+		first, last := runes[0], runes[len(runes)-1]
+		if isRankLabel(first) {
+			rank = Rank(first - '1')
+			runes = runes[1:]
+		}
+		if len(runes) == numOfSquaresInRow+1 && isRankLabel(last) {
+			if rank == -1 {
+				rank = Rank(last - '1')
+			}
+			runes = runes[:len(runes)-1]
+		}
+	}
+	if len(runes) != numOfSquaresInRow {
+		return nil, 0, false
+	}
+	for _, r := range runes {
+		p, ok := diagramPiece(r)
+		if !ok {
+			return nil, 0, false
+		}
+		cells = append(cells, p)
+	}
+	return cells, rank, true
+}
+
+func isDiagramBorder(runes []rune) bool {
+	for _, r := range runes {
+		switch r {
+		case '+', '-', '=':
+		default:
+			return false
+		}
+	}
+	// a rank of empty squares drawn with '-' is indistinguishable from a
+	// border except for its length
+	return len(runes) != numOfSquaresInRow
+}
+
+func isRankLabel(r rune) bool {
+	return r >= '1' && r <= '8'
+}
+
+func diagramPiece(r rune) (Piece, bool) {
+	switch r {
+	case '-', '.', '·':
+		return NoPiece, true
+	}
+	if p, ok := fenPieceMap[string(r)]; ok {
+		return p, true
+	}
+	for i, u := range pieceUnicodes {
+		if i != int(NoPiece) && u == string(r) {
+			return Piece(i), true
+		}
+	}
+	return NoPiece, false
+}
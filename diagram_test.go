@@ -0,0 +1,45 @@
+package chess
+
+import "testing"
+
+func TestParseDiagram(t *testing.T) {
+	fen := "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R"
+	diagrams := []string{
+		unsafeFEN(fen + " w KQkq - 0 1").board.Draw(),
+		`
+   +------------------------+
+ 8 | r  .  b  q  k  b  n  r |
+ 7 | p  p  p  p  .  p  p  p |
+ 6 | .  .  n  .  .  .  .  . |
+ 5 | .  .  .  .  p  .  .  . |
+ 4 | .  .  .  .  P  .  .  . |
+ 3 | .  .  .  .  .  N  .  . |
+ 2 | P  P  P  P  .  P  P  P |
+ 1 | R  N  B  Q  K  B  .  R |
+   +------------------------+
+     a  b  c  d  e  f  g  h`,
+		// from black's perspective
+		`
+1 R . B K Q B N R
+2 P P P . P P P P
+3 . . N . . . . .
+4 . . . P . . . .
+5 . . . p . . . .
+6 . . . . . n . .
+7 p p p . p p p p
+8 r n b k q b . r
+  h g f e d c b a`,
+	}
+	for i, d := range diagrams {
+		b, err := ParseDiagram(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != fen {
+			t.Fatalf("expected diagram %d to parse as %s but got %s", i, fen, b.String())
+		}
+	}
+	if _, err := ParseDiagram("rnbqkbnr\npppppppp"); err == nil {
+		t.Fatal("expected error for incomplete diagram")
+	}
+}
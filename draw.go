@@ -0,0 +1,139 @@
+package chess
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// DrawMoveList is designed to be used as an optional argument to
+// the Board's Draw method.  It prints the game's moves, in the game's
+// notation, in a sidebar to the right of the board.  If the game has
+// more moves than there are rows only the most recent moves are shown.
+func DrawMoveList(g *Game) func(*textDrawer) {
+	lines := []string{}
+	for i, m := range g.moves {
+		txt := g.notation.Encode(g.positions[i], m)
+		if i%2 == 0 {
+			lines = append(lines, fmt.Sprintf("%d. %s", (i/2)+1, txt))
+		} else {
+			lines[len(lines)-1] += " " + txt
+		}
+	}
+	return DrawSidebar(lines...)
+}
+
+// DrawSidebar is designed to be used as an optional argument to
+// the Board's Draw method.  It prints the given lines in a sidebar to the
+// right of the board.  If there are more lines than rows only the last
+// lines are shown.
+func DrawSidebar(lines ...string) func(*textDrawer) {
+	return func(d *textDrawer) {
+		d.sidebar = append([]string(nil), lines...)
+	}
+}
+
+// DrawCaptured is designed to be used as an optional argument to
+// the Board's Draw method.  It prints trays of captured pieces above
+// and below the board.  White pieces (captured by black) are shown above
+// the board and black pieces (captured by white) are shown below it.
+func DrawCaptured(pieces ...Piece) func(*textDrawer) {
+	return func(d *textDrawer) {
+		d.showTrays = true
+		d.captured = append([]Piece(nil), pieces...)
+	}
+}
+
+// DrawEvalBar is designed to be used as an optional argument to
+// the Board's Draw method.  It prints a vertical evaluation bar to the
+// left of the board for the given score in centipawns from white's
+// perspective.  The white portion of the bar grows from the bottom.
+func DrawEvalBar(centipawns int) func(*textDrawer) {
+	return func(d *textDrawer) {
+		d.showEval = true
+		d.eval = centipawns
+	}
+}
+
+type textDrawer struct {
+	sidebar   []string
+	showTrays bool
+	captured  []Piece
+	showEval  bool
+	eval      int
+}
+
+const (
+	evalBarWhite = "█"
+	evalBarBlack = "░"
+)
+
+func (d *textDrawer) draw(b *Board) string {
+	rows := []string{" A B C D E F G H"}
+	for r := 7; r >= 0; r-- {
+		s := Rank(r).String()
+		for f := 0; f < numOfSquaresInRow; f++ {
+			p := b.Piece(getSquare(File(f), Rank(r)))
+			if p == NoPiece {
+				s += "-"
+			} else {
+				s += p.String()
+			}
+			s += " "
+		}
+		rows = append(rows, s)
+	}
+	if d.showEval {
+		filled := d.evalBarRows()
+		rows[0] = "  " + rows[0]
+		for i := 1; i < len(rows); i++ {
+			// rows are printed top down so the white portion is at the end
+			c := evalBarBlack
+			if len(rows)-i <= filled {
+				c = evalBarWhite
+			}
+			rows[i] = c + " " + rows[i]
+		}
+	}
+	if len(d.sidebar) > 0 {
+		lines := d.sidebar
+		if len(lines) > len(rows) {
+			lines = lines[len(lines)-len(rows):]
+		}
+		width := 0
+		for _, row := range rows {
+			if n := utf8.RuneCountInString(row); n > width {
+				width = n
+			}
+		}
+		for i, line := range lines {
+			pad := width - utf8.RuneCountInString(rows[i])
+			rows[i] += strings.Repeat(" ", pad) + "  " + line
+		}
+	}
+	if d.showTrays {
+		rows = append([]string{d.tray(White)}, rows...)
+		rows = append(rows, d.tray(Black))
+	}
+	return "\n" + strings.Join(rows, "\n") + "\n"
+}
+
+func (d *textDrawer) tray(c Color) string {
+	s := ""
+	for _, p := range d.captured {
+		if p.Color() == c {
+			s += p.String()
+		}
+	}
+	return s
+}
+
+// evalBarRows returns the number of the eight rank rows that are filled in
+// for white.  The score is converted to an expected result using the
+// DefaultWDLModel so that small advantages are visible but large ones
+// saturate.
+func (d *textDrawer) evalBarRows() int {
+	expected := DefaultWDLModel.WDL(d.eval).Expected()
+	return int(math.Round(expected * numOfSquaresInRow))
+}
@@ -0,0 +1,45 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBoardDraw(t *testing.T) {
+	b := StartingPosition().Board()
+	expected := "\n A B C D E F G H\n" +
+		"8♜ ♞ ♝ ♛ ♚ ♝ ♞ ♜ \n" +
+		"7♟ ♟ ♟ ♟ ♟ ♟ ♟ ♟ \n" +
+		"6- - - - - - - - \n" +
+		"5- - - - - - - - \n" +
+		"4- - - - - - - - \n" +
+		"3- - - - - - - - \n" +
+		"2♙ ♙ ♙ ♙ ♙ ♙ ♙ ♙ \n" +
+		"1♖ ♘ ♗ ♕ ♔ ♗ ♘ ♖ \n"
+	if s := b.Draw(); s != expected {
+		t.Fatalf("expected draw to be %s but got %s", expected, s)
+	}
+}
+
+func TestBoardDrawOverlays(t *testing.T) {
+	g := NewGame()
+	for _, s := range []string{"e4", "d5", "exd5"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := g.Position().Board().Draw(DrawMoveList(g), DrawCaptured(BlackPawn), DrawEvalBar(100))
+	lines := strings.Split(s, "\n")
+	if lines[1] != "" {
+		t.Fatalf("expected empty white tray but got %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "1. e4 d5") || !strings.HasSuffix(lines[3], "2. exd5") {
+		t.Fatalf("expected move list sidebar but got\n%s", s)
+	}
+	if !strings.HasPrefix(lines[3], evalBarBlack) || !strings.HasPrefix(lines[10], evalBarWhite) {
+		t.Fatalf("expected eval bar but got\n%s", s)
+	}
+	if lines[11] != BlackPawn.String() {
+		t.Fatalf("expected black tray to contain %s but got %q", BlackPawn, lines[11])
+	}
+}
@@ -0,0 +1,161 @@
+package chess
+
+import "strings"
+
+// EndgameType is the theme of an endgame by the pieces on the board.
+type EndgameType uint8
+
+const (
+	// NoEndgame indicates that the position has too many pieces to be an
+	// endgame.
+	NoEndgame EndgameType = iota
+	// PawnEnding indicates that only kings and pawns are on the board.
+	PawnEnding
+	// KnightEnding indicates that the only pieces are knights.
+	KnightEnding
+	// BishopEnding indicates that the only pieces are bishops.
+	BishopEnding
+	// MinorPieceEnding indicates that the only pieces are bishops and
+	// knights.
+	MinorPieceEnding
+	// RookEnding indicates that the only pieces are rooks.
+	RookEnding
+	// QueenEnding indicates that the only pieces are queens.
+	QueenEnding
+	// MixedEnding indicates any other combination of pieces.
+	MixedEnding
+)
+
+// String implements the fmt.Stringer interface.
+func (t EndgameType) String() string {
+	switch t {
+	case PawnEnding:
+		return "Pawn Ending"
+	case KnightEnding:
+		return "Knight Ending"
+	case BishopEnding:
+		return "Bishop Ending"
+	case MinorPieceEnding:
+		return "Minor Piece Ending"
+	case RookEnding:
+		return "Rook Ending"
+	case QueenEnding:
+		return "Queen Ending"
+	case MixedEnding:
+		return "Mixed Ending"
+	}
+	return "No Endgame"
+}
+
+// Endgame is the classification of a position's endgame.
+type Endgame struct {
+	// Signature is the material of the stronger side followed by the
+	// weaker side's such as KRPKR or KBNK.  Pieces are listed in the
+	// order KQRBNP so the same material always has the same signature
+	// regardless of color.
+	Signature string
+	// Type is the theme of the endgame.
+	Type EndgameType
+	// OppositeColoredBishops is true if each side has a single bishop
+	// and they are on different colored squares.
+	OppositeColoredBishops bool
+}
+
+// maxEndgamePieces is the most pieces other than the king and pawns
+// each side may have in an endgame.
+const maxEndgamePieces = 2
+
+// Endgame returns the classification of the position's endgame.  The
+// Type is NoEndgame if either side has more than two pieces other than
+// its king and pawns.
+func (pos *Position) Endgame() *Endgame {
+	b := pos.board
+	strong, weak := White, Black
+	if compareMaterial(b, Black, White) > 0 {
+		strong, weak = Black, White
+	}
+	e := &Endgame{
+		Signature: materialSignature(b, strong) + materialSignature(b, weak),
+		Type:      endgameType(b),
+	}
+	wb := pieceSquares(b, WhiteBishop)
+	bb := pieceSquares(b, BlackBishop)
+	if len(wb) == 1 && len(bb) == 1 && wb[0].Color() != bb[0].Color() {
+		e.OppositeColoredBishops = true
+	}
+	return e
+}
+
+func endgameType(b *Board) EndgameType {
+	types := map[PieceType]bool{}
+	for _, c := range []Color{White, Black} {
+		count := 0
+		for _, pt := range []PieceType{Queen, Rook, Bishop, Knight} {
+			n := len(pieceSquares(b, getPiece(pt, c)))
+			count += n
+			if n > 0 {
+				types[pt] = true
+			}
+		}
+		if count > maxEndgamePieces {
+			return NoEndgame
+		}
+	}
+	switch {
+	case len(types) == 0:
+		return PawnEnding
+	case len(types) == 1 && types[Knight]:
+		return KnightEnding
+	case len(types) == 1 && types[Bishop]:
+		return BishopEnding
+	case len(types) == 2 && types[Bishop] && types[Knight]:
+		return MinorPieceEnding
+	case len(types) == 1 && types[Rook]:
+		return RookEnding
+	case len(types) == 1 && types[Queen]:
+		return QueenEnding
+	}
+	return MixedEnding
+}
+
+// materialSignature returns the pieces of the color such as KRPP.
+func materialSignature(b *Board, c Color) string {
+	s := ""
+	for _, pt := range PieceTypes() {
+		n := len(pieceSquares(b, getPiece(pt, c)))
+		s += strings.Repeat(strings.ToUpper(pt.String()), n)
+	}
+	return s
+}
+
+// compareMaterial returns a positive number if c1 has more material than
+// c2, a negative number if it has less and zero if the material is the
+// same.  Material is compared by value and then by piece in the order
+// QRBNP.
+func compareMaterial(b *Board, c1, c2 Color) int {
+	v1 := DefaultEvalParams.Material(b, c1)
+	v2 := DefaultEvalParams.Material(b, c2)
+	if v1 != v2 {
+		return v1 - v2
+	}
+	for _, pt := range []PieceType{Queen, Rook, Bishop, Knight, Pawn} {
+		n1 := len(pieceSquares(b, getPiece(pt, c1)))
+		n2 := len(pieceSquares(b, getPiece(pt, c2)))
+		if n1 != n2 {
+			return n1 - n2
+		}
+	}
+	return 0
+}
+
+// pieceSquares returns the squares of the piece.
+func pieceSquares(b *Board, p Piece) []Square {
+	squares := []Square{}
+	bb := b.bbForPiece(p)
+	for sq := 0; sq < numOfSquaresInBoard; sq++ {
+		if bb.Occupied(Square(sq)) {
+			squares = append(squares, Square(sq))
+		}
+	}
+	return squares
+}
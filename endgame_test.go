@@ -0,0 +1,31 @@
+package chess
+
+import "testing"
+
+func TestEndgame(t *testing.T) {
+	tests := []struct {
+		fen       string
+		signature string
+		typ       EndgameType
+		opposite  bool
+	}{
+		{"8/8/8/4k3/8/4P3/4K3/8 w - - 0 1", "KPK", PawnEnding, false},
+		{"8/8/8/4k3/8/4p3/4K3/8 w - - 0 1", "KPK", PawnEnding, false},
+		{"8/8/3rk3/8/8/4P3/4K3/4R3 w - - 0 1", "KRPKR", RookEnding, false},
+		{"8/8/8/4k3/8/8/8/1BN1K3 w - - 0 1", "KBNK", MinorPieceEnding, false},
+		{"8/8/2n1k3/8/8/8/4P3/2B1K3 w - - 0 1", "KBPKN", MinorPieceEnding, false},
+		{"8/8/2n1k3/8/8/8/4p3/2B1K3 w - - 0 1", "KNPKB", MinorPieceEnding, false},
+		{"8/3b4/4k3/8/8/3P4/4K3/2B5 w - - 0 1", "KBPKB", BishopEnding, true},
+		{"8/4b3/4k3/8/8/3P4/4K3/2B5 w - - 0 1", "KBPKB", BishopEnding, false},
+		{"8/8/4k3/8/8/8/2n5/n3K3 w - - 0 1", "KNNK", KnightEnding, false},
+		{"8/8/4k3/8/q7/8/8/Q3K3 w - - 0 1", "KQKQ", QueenEnding, false},
+		{"8/8/4k3/8/r7/8/8/Q3K3 w - - 0 1", "KQKR", MixedEnding, false},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "KQRRBBNNPPPPPPPPKQRRBBNNPPPPPPPP", NoEndgame, false},
+	}
+	for _, test := range tests {
+		e := unsafeFEN(test.fen).Endgame()
+		if e.Signature != test.signature || e.Type != test.typ || e.OppositeColoredBishops != test.opposite {
+			t.Fatalf("%s: expected %s %s %v but got %s %s %v", test.fen, test.signature, test.typ, test.opposite, e.Signature, e.Type, e.OppositeColoredBishops)
+		}
+	}
+}
@@ -90,7 +90,8 @@ func standardMoves(pos *Position, first bool) []*Move {
 
 func addTags(m *Move, pos *Position) {
 	p := pos.board.Piece(m.s1)
-	if pos.board.isOccupied(m.s2) {
+	castle := m.HasTag(KingSideCastle) || m.HasTag(QueenSideCastle)
+	if pos.board.isOccupied(m.s2) && !castle {
 		m.addTag(Capture)
 	} else if m.s2 == pos.enPassantSquare && p.Type() == Pawn {
 		m.addTag(EnPassant)
@@ -200,54 +201,73 @@ func bbForPossibleMoves(pos *Position, pt PieceType, sq Square) bitboard {
 	return bitboard(0)
 }
 
-// TODO can calc isInCheck twice
+// castleMoves returns the castling moves of the position.  The king and
+// rook may start on any squares of the back rank as in Chess960.  The
+// move's destination is the king's destination (ex. e1g1) from the
+// standard squares and otherwise the rook's square (ex. b1a1) so that
+// castling can't be confused with a king move.
 func castleMoves(pos *Position) []*Move {
 	moves := []*Move{}
-	kingSide := pos.castleRights.CanCastle(pos.Turn(), KingSide)
-	queenSide := pos.castleRights.CanCastle(pos.Turn(), QueenSide)
-	// white king side
-	if pos.turn == White && kingSide &&
-		(^pos.board.emptySqs&(bbForSquare(F1)|bbForSquare(G1))) == 0 &&
-		!squaresAreAttacked(pos, F1, G1) &&
-		!pos.inCheck {
-		m := &Move{s1: E1, s2: G1}
-		m.addTag(KingSideCastle)
-		addTags(m, pos)
-		moves = append(moves, m)
-	}
-	// white queen side
-	if pos.turn == White && queenSide &&
-		(^pos.board.emptySqs&(bbForSquare(B1)|bbForSquare(C1)|bbForSquare(D1))) == 0 &&
-		!squaresAreAttacked(pos, C1, D1) &&
-		!pos.inCheck {
-		m := &Move{s1: E1, s2: C1}
-		m.addTag(QueenSideCastle)
-		addTags(m, pos)
-		moves = append(moves, m)
+	if pos.inCheck {
+		return moves
 	}
-	// black king side
-	if pos.turn == Black && kingSide &&
-		(^pos.board.emptySqs&(bbForSquare(F8)|bbForSquare(G8))) == 0 &&
-		!squaresAreAttacked(pos, F8, G8) &&
-		!pos.inCheck {
-		m := &Move{s1: E8, s2: G8}
-		m.addTag(KingSideCastle)
-		addTags(m, pos)
-		moves = append(moves, m)
+	king := pos.board.whiteKingSq
+	if pos.turn == Black {
+		king = pos.board.blackKingSq
 	}
-	// black queen side
-	if pos.turn == Black && queenSide &&
-		(^pos.board.emptySqs&(bbForSquare(B8)|bbForSquare(C8)|bbForSquare(D8))) == 0 &&
-		!squaresAreAttacked(pos, C8, D8) &&
-		!pos.inCheck {
-		m := &Move{s1: E8, s2: C8}
-		m.addTag(QueenSideCastle)
+	for _, side := range []Side{KingSide, QueenSide} {
+		if king == NoSquare {
+			continue
+		}
+		rook := pos.castleRook(pos.turn, side)
+		if rook == NoSquare || rook.Rank() != king.Rank() {
+			continue
+		}
+		kingTo, rookTo := castleTargets(king.Rank(), side)
+		kingPath := squaresBetween(king, kingTo)
+		clear := true
+		for _, sq := range append(kingPath, squaresBetween(rook, rookTo)...) {
+			if sq != king && sq != rook && pos.board.isOccupied(sq) {
+				clear = false
+			}
+		}
+		if !clear || squaresAreAttacked(pos, kingPath...) {
+			continue
+		}
+		m := &Move{s1: king, s2: kingTo}
+		if !isStandardCastle(king, rook) {
+			m.s2 = rook
+		}
+		if side == KingSide {
+			m.addTag(KingSideCastle)
+		} else {
+			m.addTag(QueenSideCastle)
+		}
 		addTags(m, pos)
 		moves = append(moves, m)
 	}
 	return moves
 }
 
+func isStandardCastle(king, rook Square) bool {
+	return king.File() == FileE && (rook.File() == FileA || rook.File() == FileH)
+}
+
+// squaresBetween returns the squares on the same rank from s1 (exclusive)
+// through s2 (inclusive).
+func squaresBetween(s1, s2 Square) []Square {
+	squares := []Square{}
+	step := Square(1)
+	if s2 < s1 {
+		step = -1
+	}
+	for sq := s1; sq != s2; {
+		sq += step
+		squares = append(squares, sq)
+	}
+	return squares
+}
+
 func pawnMoves(pos *Position, sq Square) bitboard {
 	bb := bbForSquare(sq)
 	var bbEnPassant bitboard
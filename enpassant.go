@@ -0,0 +1,39 @@
+package chess
+
+// fenDecoder holds the options used to decode FEN text.
+type fenDecoder struct {
+	normalizeEnPassant bool
+}
+
+// NormalizeEnPassant is an option for the FEN function that removes the
+// en passant square unless an en passant capture is legal.  Some FEN
+// producers always set the square after a double pawn push, which makes
+// otherwise equal positions compare and hash differently.
+func NormalizeEnPassant(d *fenDecoder) {
+	d.normalizeEnPassant = true
+}
+
+// NormalizedHash returns the position's hash with the en passant square
+// ignored unless an en passant capture is legal.  Positions that only
+// differ by an en passant square that can't be used have the same
+// normalized hash.
+func (pos *Position) NormalizedHash() [16]byte {
+	return pos.normalizeEnPassant().Hash()
+}
+
+// normalizeEnPassant returns the position without its en passant square
+// if no en passant capture is legal.  The position is returned unchanged
+// otherwise.
+func (pos *Position) normalizeEnPassant() *Position {
+	if pos.enPassantSquare == NoSquare {
+		return pos
+	}
+	for _, m := range pos.ValidMoves() {
+		if m.HasTag(EnPassant) {
+			return pos
+		}
+	}
+	cp := pos.copy()
+	cp.enPassantSquare = NoSquare
+	return cp
+}
@@ -0,0 +1,52 @@
+package chess
+
+import "testing"
+
+func TestNormalizeEnPassant(t *testing.T) {
+	withEP := "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"
+	withoutEP := "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1"
+	opt, err := FEN(withEP, NormalizeEnPassant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fen := NewGame(opt).FEN(); fen != withoutEP {
+		t.Fatalf("expected normalized fen %s but got %s", withoutEP, fen)
+	}
+	if unsafeFEN(withEP).Hash() == unsafeFEN(withoutEP).Hash() {
+		t.Fatal("expected hashes to differ without normalization")
+	}
+	if unsafeFEN(withEP).NormalizedHash() != unsafeFEN(withoutEP).NormalizedHash() {
+		t.Fatal("expected normalized hashes to be equal")
+	}
+	capturable := "rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"
+	opt, err = FEN(capturable, NormalizeEnPassant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fen := NewGame(opt).FEN(); fen != capturable {
+		t.Fatalf("expected en passant square to be kept but got %s", fen)
+	}
+	if fen, r, _ := RepairFEN(withEP); fen != withoutEP || len(r.Changes) != 1 {
+		t.Fatalf("expected repaired fen %s but got %s", withoutEP, fen)
+	}
+}
+
+func TestNormalizeEnPassantInCheck(t *testing.T) {
+	// hxg3 blocks the check so the en passant square is kept
+	opt, err := FEN("rk6/8/8/8/6Pp/8/7B/K7 b q g3 0 1", NormalizeEnPassant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	if !g.Position().inCheck {
+		t.Fatal("expected the position to be in check")
+	}
+	for _, m := range g.ValidMoves() {
+		if m.HasTag(QueenSideCastle) {
+			t.Fatalf("expected no castling out of check but got %s", m)
+		}
+	}
+	if len(g.ValidMoves()) != 4 {
+		t.Fatalf("expected 4 moves but got %v", g.ValidMoves())
+	}
+}
@@ -0,0 +1,290 @@
+package chess
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EPD is a position in Extended Position Description format with its
+// operations, the format of engine test suites such as Win at Chess and
+// the Strategic Test Suite:
+//
+//	r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - bm Qxf7#; id "WAC.000";
+//
+// The common opcodes are decoded into the typed fields and every opcode's
+// operands are kept in Ops.
+type EPD struct {
+	// Position is the position with the half move clock and move number
+	// of the hmvc and fmvn opcodes, or 0 and 1 without them.
+	Position *Position
+	// Ops are the operands of each opcode without the quotes of strings.
+	Ops map[string][]string
+	// ID is the operand of the id opcode.
+	ID string
+	// BestMoves and AvoidMoves are the moves of the bm and am opcodes.
+	BestMoves  []*Move
+	AvoidMoves []*Move
+	// PV is the predicted variation of the pv opcode.
+	PV []*Move
+	// CentipawnEval is the evaluation of the ce opcode from the side to
+	// move's perspective and DirectMate the number of moves of the dm
+	// opcode.  They are zero if the opcode is missing.
+	CentipawnEval int
+	DirectMate    int
+	// Depth is the analysis depth of the acd opcode.
+	Depth int
+}
+
+// ParseEPD parses a line of EPD.  Moves are decoded in algebraic notation
+// and other notations accepted by MultiNotation against the position.  An
+// error is returned if the position or an operation of a decoded opcode
+// is invalid.
+func ParseEPD(s string) (*EPD, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("chess: epd %q must have at least 4 fields", s)
+	}
+	rest := strings.TrimSpace(s)
+	for i := 0; i < 4; i++ {
+		rest = strings.TrimLeft(rest[len(fields[i]):], " \t")
+	}
+	ops, err := parseEPDOps(rest)
+	if err != nil {
+		return nil, err
+	}
+	e := &EPD{Ops: ops}
+	hmvc, fmvn := "0", "1"
+	if v := ops["hmvc"]; len(v) == 1 {
+		hmvc = v[0]
+	}
+	if v := ops["fmvn"]; len(v) == 1 {
+		fmvn = v[0]
+	}
+	e.Position, err = decodeFEN(strings.Join(append(fields[:4:4], hmvc, fmvn), " "))
+	if err != nil {
+		return nil, err
+	}
+	if v := ops["id"]; len(v) > 0 {
+		e.ID = v[0]
+	}
+	if e.BestMoves, err = decodeEPDMoves(e.Position, ops["bm"], false); err != nil {
+		return nil, err
+	}
+	if e.AvoidMoves, err = decodeEPDMoves(e.Position, ops["am"], false); err != nil {
+		return nil, err
+	}
+	if e.PV, err = decodeEPDMoves(e.Position, ops["pv"], true); err != nil {
+		return nil, err
+	}
+	for op, n := range map[string]*int{"ce": &e.CentipawnEval, "dm": &e.DirectMate, "acd": &e.Depth} {
+		v := ops[op]
+		if len(v) == 0 {
+			continue
+		}
+		if *n, err = strconv.Atoi(v[0]); err != nil {
+			return nil, fmt.Errorf("chess: epd invalid %s operand %s", op, v[0])
+		}
+	}
+	return e, nil
+}
+
+// decodeEPDMoves decodes the moves of an operation in the position, which
+// must be legal.  The moves of a variation are decoded in the positions
+// they lead to.
+func decodeEPDMoves(pos *Position, operands []string, variation bool) ([]*Move, error) {
+	var moves []*Move
+	for _, s := range operands {
+		// MultiNotation only returns legal moves other than the null move
+		m, err := MultiNotation{}.Decode(pos, s)
+		if err != nil || m.IsNull() {
+			return nil, fmt.Errorf("chess: epd invalid move %s for position %s", s, pos)
+		}
+		moves = append(moves, m)
+		if variation {
+			pos = pos.Update(m)
+		}
+	}
+	return moves, nil
+}
+
+// parseEPDOps parses the operations after the position.  Each operation is
+// an opcode followed by operands and ends with a semicolon.  Operands in
+// double quotes may contain spaces and semicolons.
+func parseEPDOps(s string) (map[string][]string, error) {
+	ops := map[string][]string{}
+	var words []string
+	var word strings.Builder
+	inWord, quoted := false, false
+	end := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	for _, r := range s {
+		switch {
+		case quoted && r == '"':
+			quoted = false
+		case quoted:
+			word.WriteRune(r)
+		case r == '"':
+			inWord, quoted = true, true
+		case r == ';':
+			end()
+			if len(words) == 0 {
+				return nil, fmt.Errorf("chess: epd empty operation in %q", s)
+			}
+			ops[words[0]] = words[1:]
+			words = nil
+		case r == ' ' || r == '\t':
+			end()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("chess: epd unterminated string in %q", s)
+	}
+	end()
+	if len(words) > 0 {
+		// the semicolon of the last operation is often left out
+		ops[words[0]] = words[1:]
+	}
+	return ops, nil
+}
+
+// ReadEPD reads the EPD lines of the reader, such as a test suite, and
+// skips blank lines.  An error is returned with the line number if a line
+// is invalid.
+func ReadEPD(r io.Reader) ([]*EPD, error) {
+	scanner := bufio.NewScanner(r)
+	records := []*EPD{}
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		e, err := ParseEPD(line)
+		if err != nil {
+			return nil, fmt.Errorf("chess: epd line %d: %w", n, err)
+		}
+		records = append(records, e)
+	}
+	return records, scanner.Err()
+}
+
+// epdOpOrder is the order of the opcodes written before the others.
+var epdOpOrder = []string{"bm", "am", "dm", "ce", "acd", "pv", "id"}
+
+// String returns the EPD as a line of EPD.  The moves of the bm, am and pv
+// opcodes are written in algebraic notation resolved against the position
+// and the typed fields take precedence over Ops.  CentipawnEval,
+// DirectMate and Depth are only written if they aren't zero or their
+// opcode is in Ops.  The hmvc and fmvn opcodes are written from the
+// position unless its half move clock and move number are 0 and 1.
+// Opcodes are written in the order bm, am, dm, ce, acd, pv and id, then
+// the others in alphabetical order and then hmvc and fmvn.
+func (e *EPD) String() string {
+	ops := map[string][]string{}
+	for op, operands := range e.Ops {
+		ops[op] = operands
+	}
+	if len(e.BestMoves) > 0 {
+		ops["bm"] = encodeEPDMoves(e.Position, e.BestMoves, false)
+	}
+	if len(e.AvoidMoves) > 0 {
+		ops["am"] = encodeEPDMoves(e.Position, e.AvoidMoves, false)
+	}
+	if len(e.PV) > 0 {
+		ops["pv"] = encodeEPDMoves(e.Position, e.PV, true)
+	}
+	for op, n := range map[string]int{"dm": e.DirectMate, "ce": e.CentipawnEval, "acd": e.Depth} {
+		if _, ok := ops[op]; ok || n != 0 {
+			ops[op] = []string{strconv.Itoa(n)}
+		}
+	}
+	if e.ID != "" {
+		ops["id"] = []string{e.ID}
+	}
+	delete(ops, "hmvc")
+	delete(ops, "fmvn")
+	order := []string{}
+	for _, op := range epdOpOrder {
+		if _, ok := ops[op]; ok {
+			order = append(order, op)
+		}
+	}
+	rest := []string{}
+	for op := range ops {
+		if !isEPDOrderedOp(op) {
+			rest = append(rest, op)
+		}
+	}
+	sort.Strings(rest)
+	order = append(order, rest...)
+	if e.Position.halfMoveClock != 0 || e.Position.moveCount != 1 {
+		ops["hmvc"] = []string{strconv.Itoa(e.Position.halfMoveClock)}
+		ops["fmvn"] = []string{strconv.Itoa(e.Position.moveCount)}
+		order = append(order, "hmvc", "fmvn")
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.Join(strings.Fields(e.Position.String())[:4], " "))
+	for _, op := range order {
+		sb.WriteString(" " + op)
+		for _, operand := range ops[op] {
+			sb.WriteString(" " + quoteEPDOperand(op, operand))
+		}
+		sb.WriteString(";")
+	}
+	return sb.String()
+}
+
+func isEPDOrderedOp(op string) bool {
+	for _, o := range epdOpOrder {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeEPDMoves encodes the moves in algebraic notation.  The moves of a
+// variation are encoded in the positions they lead to.
+func encodeEPDMoves(pos *Position, moves []*Move, variation bool) []string {
+	operands := []string{}
+	for _, m := range moves {
+		operands = append(operands, AlgebraicNotation{}.Encode(pos, m))
+		if variation {
+			pos = pos.Update(m)
+		}
+	}
+	return operands
+}
+
+// quoteEPDOperand returns the operand in double quotes if it's the string
+// of an id or c0 to c9 opcode or it's empty or contains a space or
+// semicolon.
+func quoteEPDOperand(op, operand string) string {
+	isString := op == "id" || (len(op) == 2 && op[0] == 'c' && op[1] >= '0' && op[1] <= '9')
+	if isString || operand == "" || strings.ContainsAny(operand, " \t;") {
+		return `"` + operand + `"`
+	}
+	return operand
+}
+
+// WriteEPD writes the records as lines of EPD.
+func WriteEPD(w io.Writer, records []*EPD) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range records {
+		if _, err := bw.WriteString(e.String() + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
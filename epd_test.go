@@ -0,0 +1,113 @@
+package chess
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEPD(t *testing.T) {
+	e, err := ParseEPD(`r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - bm Qxf7#; id "WAC.000"; c0 "mate; in one"; hmvc 4; fmvn 4;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "WAC.000" {
+		t.Fatalf("expected id WAC.000 but got %q", e.ID)
+	}
+	if len(e.BestMoves) != 1 || e.BestMoves[0].String() != "h5f7" {
+		t.Fatalf("expected best move h5f7 but got %v", e.BestMoves)
+	}
+	if got := e.Position.String(); got != "r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - 4 4" {
+		t.Fatalf("unexpected position %s", got)
+	}
+	if c := e.Ops["c0"]; !reflect.DeepEqual(c, []string{"mate; in one"}) {
+		t.Fatalf("expected quoted comment but got %q", c)
+	}
+
+	e, err = ParseEPD(`rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -  am f3 g4; bm e4 d4; ce 35; acd 20; pv e4 e5 Nf3; dm 0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.AvoidMoves) != 2 || len(e.BestMoves) != 2 {
+		t.Fatalf("expected two avoid and best moves but got %v and %v", e.AvoidMoves, e.BestMoves)
+	}
+	if e.CentipawnEval != 35 || e.Depth != 20 {
+		t.Fatalf("expected ce 35 and acd 20 but got %d and %d", e.CentipawnEval, e.Depth)
+	}
+	if len(e.PV) != 3 || e.PV[2].String() != "g1f3" {
+		t.Fatalf("expected pv e4 e5 Nf3 but got %v", e.PV)
+	}
+	if _, ok := e.Ops["dm"]; !ok {
+		t.Fatal("expected the last operation without a semicolon")
+	}
+
+	for _, s := range []string{
+		"8/8/8/8 w - -",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm Nf6;",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - id \"open;",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - ce high;",
+		"r3k3/8/8/8/8/8/8/4R2K b q - bm O-O-O;",
+		"4k3/8/8/8/8/8/8/4K3 w - - bm a1a8;",
+		"4k3/8/8/8/8/8/8/4K3 w - - pv e1e2 a3a4;",
+		"4k3/8/8/8/8/8/8/4K3 w - - bm 0000;",
+	} {
+		if _, err := ParseEPD(s); err == nil {
+			t.Fatalf("expected an error parsing %s", s)
+		}
+	}
+}
+
+func TestReadEPD(t *testing.T) {
+	suite := `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";
+
+8/7p/5k2/5p2/p1p2P2/Pr1pPK2/1P1R3P/8 b - - bm Rxb2; id "WAC.002";
+`
+	records, err := ReadEPD(strings.NewReader(suite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1].ID != "WAC.002" || records[1].BestMoves[0].String() != "b3b2" {
+		t.Fatalf("unexpected records %v", records)
+	}
+	if _, err := ReadEPD(strings.NewReader("8/8/8/8/8/8/8/8 w - - bm e4;")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected an error for line 1 but got %v", err)
+	}
+}
+
+func TestEPDString(t *testing.T) {
+	lines := []string{
+		`r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - bm Qxf7#; id "WAC.000"; c0 "mate; in one";`,
+		`rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm e4 d4; am f3 g4; ce 35; acd 20; pv e4 e5 Nf3; id "start"; hmvc 2; fmvn 9;`,
+	}
+	for _, line := range lines {
+		e, err := ParseEPD(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := e.String(); s != line {
+			t.Fatalf("expected\n%s\nbut got\n%s", line, s)
+		}
+	}
+
+	pos := unsafeFEN("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	m, err := AlgebraicNotation{}.Decode(pos, "Nf3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &EPD{Position: pos, BestMoves: []*Move{m}, Ops: map[string][]string{"c1": {"Reti"}, "acn": {"1000"}}}
+	want := `rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm Nf3; acn 1000; c1 "Reti";`
+	if s := e.String(); s != want {
+		t.Fatalf("expected\n%s\nbut got\n%s", want, s)
+	}
+	buf := &strings.Builder{}
+	if err := WriteEPD(buf, []*EPD{e, e}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ReadEPD(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1].String() != want {
+		t.Fatalf("expected the written records to be read back but got %v", records)
+	}
+}
@@ -0,0 +1,200 @@
+package chess
+
+// EvalParams are the constants of the package's static evaluation.  They
+// can be changed to tune material counts, static exchange evaluation and
+// the baseline evaluation for an application.
+type EvalParams struct {
+	// PieceValues are the values of the pieces in centipawns.  The king
+	// has no material value.
+	PieceValues map[PieceType]int
+	// PieceSquareTables are bonuses in centipawns for a piece standing
+	// on a square.  Each table is listed from A8 to H1 as the board is
+	// seen from White's side and is mirrored for Black.  Piece types
+	// without a table get no bonus.
+	PieceSquareTables map[PieceType][64]int
+}
+
+// DefaultEvalParams are the parameters used by the package, for example
+// for the material balance of MaterialHistory.  The piece-square tables
+// are those of Tomasz Michniewski's simplified evaluation function.
+var DefaultEvalParams = EvalParams{
+	PieceValues: map[PieceType]int{
+		Queen:  900,
+		Rook:   500,
+		Bishop: 300,
+		Knight: 300,
+		Pawn:   100,
+	},
+	PieceSquareTables: map[PieceType][64]int{
+		Pawn: {
+			0, 0, 0, 0, 0, 0, 0, 0,
+			50, 50, 50, 50, 50, 50, 50, 50,
+			10, 10, 20, 30, 30, 20, 10, 10,
+			5, 5, 10, 25, 25, 10, 5, 5,
+			0, 0, 0, 20, 20, 0, 0, 0,
+			5, -5, -10, 0, 0, -10, -5, 5,
+			5, 10, 10, -20, -20, 10, 10, 5,
+			0, 0, 0, 0, 0, 0, 0, 0,
+		},
+		Knight: {
+			-50, -40, -30, -30, -30, -30, -40, -50,
+			-40, -20, 0, 0, 0, 0, -20, -40,
+			-30, 0, 10, 15, 15, 10, 0, -30,
+			-30, 5, 15, 20, 20, 15, 5, -30,
+			-30, 0, 15, 20, 20, 15, 0, -30,
+			-30, 5, 10, 15, 15, 10, 5, -30,
+			-40, -20, 0, 5, 5, 0, -20, -40,
+			-50, -40, -30, -30, -30, -30, -40, -50,
+		},
+		Bishop: {
+			-20, -10, -10, -10, -10, -10, -10, -20,
+			-10, 0, 0, 0, 0, 0, 0, -10,
+			-10, 0, 5, 10, 10, 5, 0, -10,
+			-10, 5, 5, 10, 10, 5, 5, -10,
+			-10, 0, 10, 10, 10, 10, 0, -10,
+			-10, 10, 10, 10, 10, 10, 10, -10,
+			-10, 5, 0, 0, 0, 0, 5, -10,
+			-20, -10, -10, -10, -10, -10, -10, -20,
+		},
+		Rook: {
+			0, 0, 0, 0, 0, 0, 0, 0,
+			5, 10, 10, 10, 10, 10, 10, 5,
+			-5, 0, 0, 0, 0, 0, 0, -5,
+			-5, 0, 0, 0, 0, 0, 0, -5,
+			-5, 0, 0, 0, 0, 0, 0, -5,
+			-5, 0, 0, 0, 0, 0, 0, -5,
+			-5, 0, 0, 0, 0, 0, 0, -5,
+			0, 0, 0, 5, 5, 0, 0, 0,
+		},
+		Queen: {
+			-20, -10, -10, -5, -5, -10, -10, -20,
+			-10, 0, 0, 0, 0, 0, 0, -10,
+			-10, 0, 5, 5, 5, 5, 0, -10,
+			-5, 0, 5, 5, 5, 5, 0, -5,
+			0, 0, 5, 5, 5, 5, 0, -5,
+			-10, 5, 5, 5, 5, 5, 0, -10,
+			-10, 0, 5, 0, 0, 0, 0, -10,
+			-20, -10, -10, -5, -5, -10, -10, -20,
+		},
+		King: {
+			-30, -40, -40, -50, -50, -40, -40, -30,
+			-30, -40, -40, -50, -50, -40, -40, -30,
+			-30, -40, -40, -50, -50, -40, -40, -30,
+			-30, -40, -40, -50, -50, -40, -40, -30,
+			-20, -30, -30, -40, -40, -30, -30, -20,
+			-10, -20, -20, -20, -20, -20, -20, -10,
+			20, 20, 0, 0, 0, 0, 20, 20,
+			20, 30, 10, 0, 0, 10, 30, 20,
+		},
+	},
+}
+
+// seeKingValue is the king's value in static exchange evaluation so that
+// the king never captures onto a defended square.
+const seeKingValue = 100000
+
+// Material returns the value in centipawns of the color's pieces.
+func (p EvalParams) Material(b *Board, c Color) int {
+	v := 0
+	for _, piece := range b.SquareMap() {
+		if piece.Color() == c {
+			v += p.PieceValues[piece.Type()]
+		}
+	}
+	return v
+}
+
+// Evaluate returns the static evaluation of the position in centipawns
+// from White's perspective: White's material and piece-square bonuses
+// minus Black's.  It doesn't consider whose turn it is or whether the
+// game is over.
+func (p EvalParams) Evaluate(pos *Position) int {
+	score := 0
+	for sq, piece := range pos.board.SquareMap() {
+		v := p.PieceValues[piece.Type()]
+		if table, ok := p.PieceSquareTables[piece.Type()]; ok {
+			v += table[pieceSquareIndex(sq, piece.Color())]
+		}
+		if piece.Color() == White {
+			score += v
+		} else {
+			score -= v
+		}
+	}
+	return score
+}
+
+// SEE returns the static exchange evaluation of the move in centipawns:
+// the material the side to move gains if both sides keep recapturing on
+// the move's destination with their least valuable piece for as long as
+// it pays.  Attackers revealed by captures such as the rear piece of a
+// battery join the exchange.  Pins are ignored.
+func (p EvalParams) SEE(pos *Position, m *Move) int {
+	target := m.s2
+	captured := pos.board.Piece(target)
+	if m.HasTag(EnPassant) {
+		captured = getPiece(Pawn, pos.turn.Other())
+	}
+	occ := ^pos.board.emptySqs &^ bbForSquare(m.s1)
+	gains := []int{p.seeValue(captured.Type())}
+	onTarget := p.seeValue(pos.board.Piece(m.s1).Type())
+	if m.promo != NoPieceType {
+		gains[0] += p.seeValue(m.promo) - p.seeValue(Pawn)
+		onTarget = p.seeValue(m.promo)
+	}
+	side := pos.turn.Other()
+	for {
+		sq, ok := p.leastValuableAttacker(pos.board, target, side, occ)
+		if !ok {
+			break
+		}
+		gains = append(gains, onTarget-gains[len(gains)-1])
+		onTarget = p.seeValue(pos.board.Piece(sq).Type())
+		occ &^= bbForSquare(sq)
+		side = side.Other()
+	}
+	// each side may stop capturing when continuing loses material
+	for i := len(gains) - 1; i > 0; i-- {
+		if -gains[i] < gains[i-1] {
+			gains[i-1] = -gains[i]
+		}
+	}
+	return gains[0]
+}
+
+// leastValuableAttacker returns the square of the color's least valuable
+// piece among the occupied squares that attacks the target.
+func (p EvalParams) leastValuableAttacker(b *Board, target Square, c Color, occ bitboard) (Square, bool) {
+	best, found, bestValue := NoSquare, false, 0
+	for i := 0; i < numOfSquaresInBoard; i++ {
+		sq := Square(i)
+		piece := b.Piece(sq)
+		if piece == NoPiece || piece.Color() != c || !occ.Occupied(sq) {
+			continue
+		}
+		if !pieceAttacks(piece, sq, occ).Occupied(target) {
+			continue
+		}
+		if v := p.seeValue(piece.Type()); !found || v < bestValue {
+			best, found, bestValue = sq, true, v
+		}
+	}
+	return best, found
+}
+
+func (p EvalParams) seeValue(pt PieceType) int {
+	if pt == King {
+		return seeKingValue
+	}
+	return p.PieceValues[pt]
+}
+
+// pieceSquareIndex returns the index of the square in a piece-square
+// table for a piece of the color.
+func pieceSquareIndex(sq Square, c Color) int {
+	rank := 7 - int(sq.Rank())
+	if c == Black {
+		rank = int(sq.Rank())
+	}
+	return rank*8 + int(sq.File())
+}
@@ -0,0 +1,53 @@
+package chess
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	p := DefaultEvalParams
+	g := NewGame()
+	if v := p.Evaluate(g.Position()); v != 0 {
+		t.Fatalf("expected the starting position to be level but got %d", v)
+	}
+	if v := p.Material(g.Position().Board(), White); v != 3900 {
+		t.Fatalf("expected 3900 centipawns of material but got %d", v)
+	}
+	if err := g.MoveStr("e4"); err != nil {
+		t.Fatal(err)
+	}
+	if v := p.Evaluate(g.Position()); v != 40 {
+		t.Fatalf("expected e4 to gain 40 centipawns but got %d", v)
+	}
+	custom := EvalParams{PieceValues: map[PieceType]int{Knight: 1}}
+	if v := custom.Material(g.Position().Board(), Black); v != 2 {
+		t.Fatalf("expected custom knight values to be used but got %d", v)
+	}
+}
+
+func TestSEE(t *testing.T) {
+	tests := []struct {
+		fen  string
+		move string
+		see  int
+	}{
+		{"4k3/8/8/4p3/8/8/8/4R1K1 w - - 0 1", "Rxe5", 100},
+		{"4k3/8/3p4/4p3/8/8/8/4R1K1 w - - 0 1", "Rxe5", -400},
+		{"4k3/8/3p4/4p3/3P4/8/8/6K1 w - - 0 1", "dxe5", 0},
+		{"4r1k1/8/8/4p3/8/8/4R3/4R1K1 w - - 0 1", "Rxe5", 100},
+		{"4r1k1/8/8/4p3/8/8/8/4R1K1 w - - 0 1", "Rxe5", -400},
+		{"4k3/8/8/8/8/8/8/4R1K1 w - - 0 1", "Re2", 0},
+	}
+	for _, test := range tests {
+		fen, err := FEN(test.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pos := NewGame(fen).Position()
+		m, err := AlgebraicNotation{}.Decode(pos, test.move)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if see := DefaultEvalParams.SEE(pos, m); see != test.see {
+			t.Fatalf("expected SEE of %s in %s to be %d but got %d", test.move, test.fen, test.see, see)
+		}
+	}
+}
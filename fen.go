@@ -27,6 +27,11 @@ func decodeFEN(fen string) (*Position, error) {
 	if err != nil {
 		return nil, err
 	}
+	castleRights, err := parseCastleRights(b, rights)
+	if err != nil {
+		return nil, err
+	}
+	rights = formatCastleRights(b, castleRights)
 	sq, err := formEnPassant(parts[3])
 	if err != nil {
 		return nil, err
@@ -39,14 +44,17 @@ func decodeFEN(fen string) (*Position, error) {
 	if err != nil || moveCount < 1 {
 		return nil, fmt.Errorf("chess: fen invalid move count %s", parts[5])
 	}
-	return &Position{
+	pos := &Position{
 		board:           b,
 		turn:            turn,
 		castleRights:    rights,
 		enPassantSquare: sq,
 		halfMoveClock:   halfMoveClock,
 		moveCount:       moveCount,
-	}, nil
+	}
+	// check is set before moves are generated and cached for the position
+	pos.inCheck = isInCheck(pos)
+	return pos, nil
 }
 
 // generates board from fen format: rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR
@@ -93,17 +101,23 @@ func fenFormRank(rankStr string) (map[File]Piece, error) {
 	return m, nil
 }
 
+// formCastleRights checks the characters of the castling field, which
+// may include the rook file letters of X-FEN and Shredder-FEN.  The rights
+// are checked against the board by parseCastleRights.
 func formCastleRights(castleStr string) (CastleRights, error) {
 	// check for duplicates aka. KKkq right now is valid
-	for _, s := range []string{"K", "Q", "k", "q", "-"} {
-		if strings.Count(castleStr, s) > 1 {
+	for _, r := range castleStr {
+		if strings.Count(castleStr, string(r)) > 1 {
 			return "-", fmt.Errorf("chess: fen invalid castle rights %s", castleStr)
 		}
 	}
+	if castleStr == "-" {
+		return "-", nil
+	}
 	for _, r := range castleStr {
-		c := fmt.Sprintf("%c", r)
-		switch c {
-		case "K", "Q", "k", "q", "-":
+		switch {
+		case strings.ContainsRune("KQkq", r):
+		case r >= 'A' && r <= 'H', r >= 'a' && r <= 'h':
 		default:
 			return "-", fmt.Errorf("chess: fen invalid castle rights %s", castleStr)
 		}
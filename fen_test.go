@@ -52,3 +52,58 @@ func TestInvalidFENs(t *testing.T) {
 		}
 	}
 }
+
+func TestXFENCastleRights(t *testing.T) {
+	tests := []struct {
+		fen      string
+		expected string
+	}{
+		// Shredder-FEN rights with the outermost rooks are written as KQkq
+		{"r3k2r/8/8/8/8/8/8/R3K2R w HAha - 0 1", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"},
+		{"1r2k1r1/8/8/8/8/8/8/1R2K1R1 w GBgb - 0 1", "1r2k1r1/8/8/8/8/8/8/1R2K1R1 w KQkq - 0 1"},
+		// rights with an inner rook keep its file as in X-FEN
+		{"r3k3/8/8/8/8/8/8/RR2K2R w HBq - 0 1", "r3k3/8/8/8/8/8/8/RR2K2R w KBq - 0 1"},
+		{"4k1rr/8/8/8/8/8/8/4K3 b g - 0 1", "4k1rr/8/8/8/8/8/8/4K3 b g - 0 1"},
+	}
+	for _, test := range tests {
+		pos, err := decodeFEN(test.fen)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %s", test.fen, err)
+		}
+		if pos.String() != test.expected {
+			t.Fatalf("expected %s to be written as %s but got %s", test.fen, test.expected, pos.String())
+		}
+	}
+	for _, fen := range []string{
+		"4k3/8/8/8/8/8/8/4K3 w H - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w KH - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w Ii - 0 1",
+	} {
+		if _, err := decodeFEN(fen); err == nil {
+			t.Fatalf("expected an error from %s", fen)
+		}
+	}
+}
+
+func TestXFENCastleInnerRook(t *testing.T) {
+	pos := unsafeFEN("4k3/8/8/8/8/8/8/RR2K3 w B - 0 1")
+	m, err := AlgebraicNotation{}.Decode(pos, "O-O-O")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := (UCINotation{}).Encode(pos, m); s != "e1b1" {
+		t.Fatalf("expected castling with the inner rook to be e1b1 but got %s", s)
+	}
+	if s := pos.Update(m).String(); s != "4k3/8/8/8/8/8/8/R1KR4 b - - 0 1" {
+		t.Fatalf("unexpected position after castling %s", s)
+	}
+	// the right is written as a file once another rook is beyond its rook
+	pos = unsafeFEN("4k3/8/8/8/8/8/7R/4K1R1 w K - 0 1")
+	m, err = AlgebraicNotation{}.Decode(pos, "Rhh1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := pos.Update(m).String(); s != "4k3/8/8/8/8/8/8/4K1RR b G - 1 1" {
+		t.Fatalf("expected the right to be written as G but got %s", s)
+	}
+}
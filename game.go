@@ -1,10 +1,12 @@
 package chess
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
 )
 
 // A Outcome is the result of a game.
@@ -63,6 +65,19 @@ type TagPair struct {
 	Value string
 }
 
+// String returns the tag pair in the PGN format.  As required by the PGN
+// standard quotes and backslashes in the value are escaped with a
+// backslash and tabs and newlines, which a value can't contain, are
+// written as spaces.
+func (t *TagPair) String() string {
+	return fmt.Sprintf("[%s \"%s\"]", t.Key, tagValueEscaper.Replace(t.Value))
+}
+
+var (
+	tagValueEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	tagValueUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`)
+)
+
 // A Game represents a single chess game.
 type Game struct {
 	notation             Notation
@@ -73,19 +88,36 @@ type Game struct {
 	outcome              Outcome
 	method               Method
 	ignoreAutomaticDraws bool
+	// comments before the first move
+	comments []Comment
+	// escapes are the PGN escape lines without their %
+	escapes  []string
+	metadata map[string]string
+	// layout is the lexical form of a game decoded with ExactPGN
+	layout *pgnLayout
+	// lastIrreversible is the ply of the last irreversible move or zero
+	lastIrreversible int
+	// ctx is the context attached with GameContext or nil
+	ctx context.Context
 }
 
 // PGN takes a reader and returns a function that updates
 // the game to reflect the PGN data.  The PGN can use any
 // move notation supported by this package.  The returned
 // function is designed to be used in the NewGame constructor.
-// An error is returned if there is a problem parsing the PGN data.
-func PGN(r io.Reader) (func(*Game), error) {
-	b, err := ioutil.ReadAll(r)
+// Options such as PreservePGN configure decoding.  Compressed
+// input is decompressed as with NewScanner.  An error is
+// returned if there is a problem parsing the PGN data.
+func PGN(r io.Reader, opts ...func(*pgnDecoder)) (func(*Game), error) {
+	br, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(br)
 	if err != nil {
 		return nil, err
 	}
-	game, err := decodePGN(string(b))
+	game, err := newPGNDecoder(opts...).decode(string(b))
 	if err != nil {
 		return nil, err
 	}
@@ -98,16 +130,26 @@ func PGN(r io.Reader) (func(*Game), error) {
 // the game to reflect the FEN data.  Since FEN doesn't encode
 // prior moves, the move list will be empty.  The returned
 // function is designed to be used in the NewGame constructor.
-// An error is returned if there is a problem parsing the FEN data.
-func FEN(fen string) (func(*Game), error) {
+// Options such as NormalizeEnPassant configure decoding.  An error
+// is returned if there is a problem parsing the FEN data.
+func FEN(fen string, opts ...func(*fenDecoder)) (func(*Game), error) {
+	d := &fenDecoder{}
+	for _, f := range opts {
+		if f != nil {
+			f(d)
+		}
+	}
 	pos, err := decodeFEN(fen)
 	if err != nil {
 		return nil, err
 	}
+	if d.normalizeEnPassant {
+		pos = pos.normalizeEnPassant()
+	}
 	return func(g *Game) {
-		pos.inCheck = isInCheck(pos)
 		g.pos = pos
 		g.positions = []*Position{pos}
+		g.lastIrreversible = 0
 		g.updatePosition()
 	}, nil
 }
@@ -153,14 +195,35 @@ func NewGame(options ...func(*Game)) *Game {
 	return game
 }
 
-// Move updates the game with the given move.  An error is returned
-// if the move is invalid or the game has already been completed.
+// Move updates the game with the given move.  A *MoveError is returned
+// if the move is invalid or the game has already been completed.  A null
+// move is valid unless the side to move is in check.  The context's error
+// is returned if the game's context has been canceled.
 func (g *Game) Move(m *Move) error {
+	if err := g.Context().Err(); err != nil {
+		return err
+	}
+	if g.outcome != NoOutcome {
+		return &MoveError{Move: m, Reason: GameOver}
+	}
+	if m == nil {
+		return errors.New("chess: invalid move <nil>")
+	}
 	valid := moveSlice(g.ValidMoves()).find(m)
+	if m.IsNull() {
+		if g.pos.inCheck {
+			return &MoveError{Move: m, Reason: LeavesKingInCheck}
+		}
+		valid = m
+	}
 	if valid == nil {
-		return fmt.Errorf("chess: invalid move %s", m)
+		return &MoveError{Move: m, Reason: g.pos.illegalReason(m)}
+	}
+	if isIrreversible(g.pos, valid) {
+		g.lastIrreversible = len(g.moves) + 1
 	}
-	g.moves = append(g.moves, valid)
+	// store a copy so annotations aren't shared with the position's moves
+	g.moves = append(g.moves, valid.copy())
 	g.pos = g.pos.Update(valid)
 	g.positions = append(g.positions, g.pos)
 	g.updatePosition()
@@ -168,14 +231,55 @@ func (g *Game) Move(m *Move) error {
 }
 
 // MoveStr decodes the given string in game's notation
-// and calls the Move function.  An error is returned if
-// the move can't be decoded or the move is invalid.
+// and calls the Move function.  NAGs of the decoded move,
+// such as a suffix annotation like !?, are kept.  An error
+// is returned if the move can't be decoded or the move is
+// invalid.
 func (g *Game) MoveStr(s string) error {
-	m, err := g.notation.Decode(g.pos, s)
+	return g.MoveStrNotation(g.notation, s)
+}
+
+// MoveStrNotation is like MoveStr but decodes the string in the given
+// notation instead of the game's notation, such as for a move entered
+// by a user of another language with LocalizedNotation.
+func (g *Game) MoveStrNotation(n Notation, s string) error {
+	m, err := n.Decode(g.pos, s)
 	if err != nil {
 		return err
 	}
-	return g.Move(m)
+	if err := g.Move(m); err != nil {
+		return err
+	}
+	g.moves[len(g.moves)-1].nags = append([]string(nil), m.nags...)
+	return nil
+}
+
+// Notation returns the game's notation, which is set with UseNotation
+// and defaults to AlgebraicNotation.
+func (g *Game) Notation() Notation {
+	return g.notation
+}
+
+// checkPly returns an error if the ply isn't the ply of one of the game's
+// moves, where 1 is the first move of the game.
+func (g *Game) checkPly(ply int) error {
+	if ply < 1 || ply > len(g.moves) {
+		return fmt.Errorf("chess: ply %d is out of range for a game with %d moves", ply, len(g.moves))
+	}
+	return nil
+}
+
+// EncodeMove returns the move at the given ply, where 1 is the first
+// move of the game, encoded in the notation.  A nil notation uses the
+// game's notation.  An error is returned if the ply is out of range.
+func (g *Game) EncodeMove(ply int, n Notation) (string, error) {
+	if err := g.checkPly(ply); err != nil {
+		return "", err
+	}
+	if n == nil {
+		n = g.notation
+	}
+	return n.Encode(g.positions[ply-1], g.moves[ply-1]), nil
 }
 
 // ValidMoves returns a list of valid moves in the
@@ -222,13 +326,22 @@ func (g *Game) FEN() string {
 // String implements the fmt.Stringer interface and returns
 // the game's PGN.
 func (g *Game) String() string {
-	return encodePGN(g)
+	return g.pgn()
 }
 
 // MarshalText implements the encoding.TextMarshaler interface and
 // encodes the game's PGN.
 func (g *Game) MarshalText() (text []byte, err error) {
-	return []byte(encodePGN(g)), nil
+	return []byte(g.pgn()), nil
+}
+
+// pgn returns the game's PGN with the layout of its source if it was
+// decoded with ExactPGN.
+func (g *Game) pgn() string {
+	if g.layout != nil {
+		return encodeExactPGN(g)
+	}
+	return encodePGN(g)
 }
 
 // UnmarshalText implements the encoding.TextUnarshaler interface and
@@ -291,11 +404,14 @@ func (g *Game) EligibleDraws() []Method {
 }
 
 // AddTagPair adds or updates a tag pair with the given key and
-// value and returns true if the value is overwritten.
+// value and returns true if the value is overwritten.  Any key can
+// be used for custom tag pairs.  Updated tag pairs keep their place
+// and new ones are added last.
 func (g *Game) AddTagPair(k, v string) bool {
 	for i, tag := range g.tagPairs {
 		if tag.Key == k {
-			g.tagPairs[i].Value = v
+			// tag pairs are shared with clones so they're replaced
+			g.tagPairs[i] = &TagPair{Key: k, Value: v}
 			return true
 		}
 	}
@@ -314,6 +430,27 @@ func (g *Game) GetTagPair(k string) *TagPair {
 	return nil
 }
 
+// CustomTagPairs returns the game's tag pairs that aren't in the
+// SevenTagRoster in the order they were added.
+func (g *Game) CustomTagPairs() []*TagPair {
+	custom := []*TagPair{}
+	for _, tag := range g.tagPairs {
+		if !isSevenTagRoster(tag.Key) {
+			custom = append(custom, tag)
+		}
+	}
+	return custom
+}
+
+func isSevenTagRoster(key string) bool {
+	for _, k := range SevenTagRoster {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveTagPair removes the tag pair for the given key and
 // returns true if a tag pair was removed.
 func (g *Game) RemoveTagPair(k string) bool {
@@ -367,28 +504,40 @@ func (g *Game) updatePosition() {
 
 func (g *Game) copy(game *Game) {
 	g.tagPairs = game.TagPairs()
-	g.moves = game.Moves()
+	// moves are copied so that annotating one game doesn't change the other
+	g.moves = filterAnnotations(game.moves, func(string) bool { return true })
 	g.positions = game.Positions()
 	g.pos = game.pos
 	g.outcome = game.outcome
 	g.method = game.method
+	g.comments = append([]Comment(nil), game.comments...)
+	g.escapes = append([]string(nil), game.escapes...)
+	g.layout = game.layout
+	g.lastIrreversible = game.lastIrreversible
 }
 
 func (g *Game) Clone() *Game {
 	return &Game{
-		tagPairs:  g.TagPairs(),
-		notation:  g.notation,
-		moves:     g.Moves(),
-		positions: g.Positions(),
-		pos:       g.pos,
-		outcome:   g.outcome,
-		method:    g.method,
+		tagPairs:         g.TagPairs(),
+		notation:         g.notation,
+		moves:            filterAnnotations(g.moves, func(string) bool { return true }),
+		positions:        g.Positions(),
+		pos:              g.pos,
+		outcome:          g.outcome,
+		method:           g.method,
+		comments:         append([]Comment(nil), g.comments...),
+		escapes:          append([]string(nil), g.escapes...),
+		layout:           g.layout,
+		metadata:         g.Metadata(),
+		lastIrreversible: g.lastIrreversible,
+		ctx:              g.ctx,
 	}
 }
 
 func (g *Game) numOfRepitions() int {
 	count := 0
-	for _, pos := range g.Positions() {
+	// positions before an irreversible move can't repeat
+	for _, pos := range g.positions[g.lastIrreversible:] {
 		if g.pos.samePosition(pos) {
 			count++
 		}
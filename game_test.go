@@ -1,9 +1,11 @@
 package chess
 
 import (
+	"context"
 	"log"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCheckmate(t *testing.T) {
@@ -250,6 +252,43 @@ func TestTagPairs(t *testing.T) {
 	}
 }
 
+func TestCustomTagPairs(t *testing.T) {
+	g := NewGame()
+	g.AddTagPair("Source", "club \"open\" night")
+	g.AddTagPair("White", "A")
+	g.AddTagPair("Event", "Blitz")
+	g.AddTagPair("Annotator", `C:\games`)
+	g.AddTagPair("Source", "club")
+	custom := g.CustomTagPairs()
+	if len(custom) != 2 || custom[0].Key != "Source" || custom[1].Key != "Annotator" {
+		t.Fatalf("expected the custom tags in the order added but got %v", custom)
+	}
+
+	clone := g.Clone()
+	g.AddTagPair("Source", "online")
+	if v := clone.GetTagPair("Source").Value; v != "club" {
+		t.Fatalf("expected the clone's tag to be unchanged but got %s", v)
+	}
+
+	clone.AddTagPair("Source", `"quoted"`)
+	var sb strings.Builder
+	if err := clone.WritePGN(&sb); err != nil {
+		t.Fatal(err)
+	}
+	expected := "[Event \"Blitz\"]\n[White \"A\"]\n[Source \"\\\"quoted\\\"\"]\n[Annotator \"C:\\\\games\"]\n"
+	if !strings.HasPrefix(sb.String(), expected) {
+		t.Fatalf("expected tags\n%s\nbut got\n%s", expected, sb.String())
+	}
+	opt, err := PGN(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := NewGame(opt)
+	if decoded.GetTagPair("Source").Value != `"quoted"` || decoded.GetTagPair("Annotator").Value != `C:\games` {
+		t.Fatalf("expected escaped values to round trip but got %v", decoded.TagPairs())
+	}
+}
+
 func TestPositionHash(t *testing.T) {
 	g1 := NewGame()
 	for _, s := range []string{"Nc3", "e5", "Nf3"} {
@@ -308,3 +347,106 @@ func BenchmarkPositionHash(b *testing.B) {
 		g.Position().Hash()
 	}
 }
+
+func TestEncodeMoves(t *testing.T) {
+	opt, err := PGN(strings.NewReader("1. e4 {king's pawn} e5 (1... c5 2. Nf3) 2. Nf3 $1 Nc6 3. Bb5 a6 4. Ba4 Nf6 5. O-O 1-0"), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	expected := "1.e2e4 {king's pawn} 1...e7e5 (1...c7c5 2.g1f3) 2.g1f3 $1 2...b8c6 3.f1b5 a7a6 4.b5a4 g8f6 5.e1g1 1-0"
+	if s := g.EncodeMoves(UCINotation{}); s != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, s)
+	}
+	if s := g.EncodeMoves(AlgebraicNotation{}); !strings.HasPrefix(s, "1.e4 {king's pawn} 1...e5 (1...c5 2.Nf3) 2.Nf3") {
+		t.Fatalf("expected algebraic movetext but got %s", s)
+	}
+	if !strings.HasSuffix(g.String(), g.EncodeMoves(AlgebraicNotation{})) {
+		t.Fatalf("expected the game's PGN to end with its movetext")
+	}
+	if s := g.EncodeMoves(nil); s != g.EncodeMoves(g.Notation()) {
+		t.Fatalf("expected a nil notation to use the game's notation but got %s", s)
+	}
+}
+
+func TestGameContext(t *testing.T) {
+	if NewGame().Context() != context.Background() {
+		t.Fatal("expected the background context by default")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewGame(GameContext(ctx))
+	if err := g.MoveStr("e4"); err != nil {
+		t.Fatal(err)
+	}
+	clone := g.Clone()
+	cancel()
+	if err := g.MoveStr("e5"); err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+	if err := clone.MoveStr("e5"); err != context.Canceled {
+		t.Fatalf("expected the clone to keep the context but got %v", err)
+	}
+	if len(g.Moves()) != 1 {
+		t.Fatalf("expected 1 move but got %d", len(g.Moves()))
+	}
+}
+
+func TestCloneAnnotations(t *testing.T) {
+	g := NewGame()
+	if err := g.MoveStr("e4"); err != nil {
+		t.Fatal(err)
+	}
+	c := g.Clone()
+	if err := c.SetComment(1, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddNAG(1, GoodMove); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetClock(1, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if s := g.String(); s != "1.e4 *" {
+		t.Fatalf("expected annotating the clone not to change the game but got %q", s)
+	}
+}
+
+func TestGameNotationOverride(t *testing.T) {
+	g := NewGame(UseNotation(LocalizedNotation{Letters: GermanPieceLetters}))
+	if _, ok := g.Notation().(LocalizedNotation); !ok {
+		t.Fatalf("expected the game's notation to be localized but got %v", g.Notation())
+	}
+	if err := g.MoveStr("Sf3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.MoveStrNotation(UCINotation{}, "g8f6"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.MoveStrNotation(FigurineNotation{}, "♘c3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.MoveStrNotation(UCINotation{}, "Nc6"); err == nil {
+		t.Fatal("expected Nc6 not to decode in UCI notation")
+	}
+	tests := []struct {
+		n    Notation
+		text string
+	}{
+		{nil, "Sf6"},
+		{AlgebraicNotation{}, "Nf6"},
+		{UCINotation{}, "g8f6"},
+		{FigurineNotation{}, "♘f6"},
+	}
+	for _, test := range tests {
+		s, err := g.EncodeMove(2, test.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.text {
+			t.Fatalf("expected %s but got %s", test.text, s)
+		}
+	}
+	if _, err := g.EncodeMove(4, nil); err == nil {
+		t.Fatal("expected an error for a ply out of range")
+	}
+}
@@ -0,0 +1,260 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WhiteElo returns the WhiteElo tag pair's rating.  An error is returned
+// if the tag pair is missing, unknown or not a number.
+func (g *Game) WhiteElo() (int, error) {
+	return g.eloTag("WhiteElo")
+}
+
+// BlackElo returns the BlackElo tag pair's rating.  An error is returned
+// if the tag pair is missing, unknown or not a number.
+func (g *Game) BlackElo() (int, error) {
+	return g.eloTag("BlackElo")
+}
+
+func (g *Game) eloTag(key string) (int, error) {
+	v, err := g.knownTag(key)
+	if err != nil {
+		return 0, err
+	}
+	elo, err := strconv.Atoi(v)
+	if err != nil || elo < 0 {
+		return 0, fmt.Errorf("chess: invalid %s %q", key, v)
+	}
+	return elo, nil
+}
+
+// Date returns the Date tag pair's date in the YYYY.MM.DD format in UTC.
+// Unknown months and days written as ?? are returned as the first of the
+// year or month.  An error is returned if the tag pair is missing, the
+// year is unknown or the date is invalid.
+func (g *Game) Date() (time.Time, error) {
+	v, err := g.knownTag("Date")
+	if err != nil {
+		return time.Time{}, err
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 || len(parts[0]) != 4 || len(parts[1]) != 2 || len(parts[2]) != 2 {
+		return time.Time{}, fmt.Errorf("chess: invalid Date %q", v)
+	}
+	if parts[0] == "????" {
+		return time.Time{}, fmt.Errorf("chess: Date %q has an unknown year", v)
+	}
+	values := [3]int{0, 1, 1}
+	for i, part := range parts {
+		if i > 0 && part == "??" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("chess: invalid Date %q", v)
+		}
+		values[i] = n
+	}
+	t := time.Date(values[0], time.Month(values[1]), values[2], 0, 0, 0, 0, time.UTC)
+	if t.Month() != time.Month(values[1]) || t.Day() != values[2] {
+		return time.Time{}, fmt.Errorf("chess: invalid Date %q", v)
+	}
+	return t, nil
+}
+
+// Round returns the Round tag pair's round numbers.  Rounds such as 3.1
+// have a number for each level.  An error is returned if the tag pair is
+// missing, unknown or not made of numbers.
+func (g *Game) Round() ([]int, error) {
+	v, err := g.knownTag("Round")
+	if err != nil {
+		return nil, err
+	}
+	round := []int{}
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("chess: invalid Round %q", v)
+		}
+		round = append(round, n)
+	}
+	return round, nil
+}
+
+// TimeControl returns the TimeControl tag pair's time control.  An error
+// is returned if the tag pair is missing, unknown or invalid.
+func (g *Game) TimeControl() (TimeControl, error) {
+	v, err := g.knownTag("TimeControl")
+	if err != nil {
+		return TimeControl{}, err
+	}
+	return ParseTimeControl(v)
+}
+
+// knownTag returns the value of the tag pair unless it's missing or
+// unknown which is written as ? or left empty.
+func (g *Game) knownTag(key string) (string, error) {
+	tag := g.GetTagPair(key)
+	if tag == nil {
+		return "", fmt.Errorf("chess: game has no %s tag pair", key)
+	}
+	v := strings.TrimSpace(tag.Value)
+	if v == "" || v == "?" {
+		return "", fmt.Errorf("chess: game's %s is unknown", key)
+	}
+	return v, nil
+}
+
+// A TimeControl is the time control of a game from the TimeControl tag
+// pair.  Controls are played in order with the last one repeating.
+type TimeControl struct {
+	// Unlimited is true for games without a time control, written as -.
+	Unlimited bool
+	// Periods are the time controls of the game.
+	Periods []TimeControlPeriod
+}
+
+// A TimeControlPeriod is a time control such as 40 moves in 90 minutes or
+// 3 minutes with a 2 second increment.
+type TimeControlPeriod struct {
+	// Moves is the number of moves to be played in the period or zero for
+	// the rest of the game.
+	Moves int
+	// Time is the time for the period.
+	Time time.Duration
+	// Increment is the time added after each move.
+	Increment time.Duration
+	// Sandclock is true for sandclock or hourglass play, written as *,
+	// where time used by one side is added to the other.
+	Sandclock bool
+}
+
+// ParseTimeControl parses a TimeControl tag pair value in the PGN format
+// such as 40/5400+30:1800+30 or 180+2 with times in seconds.
+func ParseTimeControl(s string) (TimeControl, error) {
+	if s == "-" {
+		return TimeControl{Unlimited: true}, nil
+	}
+	tc := TimeControl{}
+	for _, field := range strings.Split(s, ":") {
+		p := TimeControlPeriod{}
+		if strings.HasPrefix(field, "*") {
+			p.Sandclock = true
+			field = field[1:]
+		} else if i := strings.IndexByte(field, '/'); i != -1 {
+			moves, err := strconv.Atoi(field[:i])
+			if err != nil || moves <= 0 {
+				return TimeControl{}, fmt.Errorf("chess: invalid time control %q", s)
+			}
+			p.Moves = moves
+			field = field[i+1:]
+		}
+		if i := strings.IndexByte(field, '+'); i != -1 && !p.Sandclock {
+			inc, err := parseSeconds(field[i+1:])
+			if err != nil {
+				return TimeControl{}, fmt.Errorf("chess: invalid time control %q", s)
+			}
+			p.Increment = inc
+			field = field[:i]
+		}
+		t, err := parseSeconds(field)
+		if err != nil {
+			return TimeControl{}, fmt.Errorf("chess: invalid time control %q", s)
+		}
+		p.Time = t
+		tc.Periods = append(tc.Periods, p)
+	}
+	return tc, nil
+}
+
+// String returns the time control in the PGN TimeControl format.
+func (tc TimeControl) String() string {
+	if tc.Unlimited {
+		return "-"
+	}
+	fields := []string{}
+	for _, p := range tc.Periods {
+		field := formatSeconds(p.Time)
+		switch {
+		case p.Sandclock:
+			field = "*" + field
+		case p.Moves > 0:
+			field = strconv.Itoa(p.Moves) + "/" + field
+		}
+		if p.Increment > 0 && !p.Sandclock {
+			field += "+" + formatSeconds(p.Increment)
+		}
+		fields = append(fields, field)
+	}
+	return strings.Join(fields, ":")
+}
+
+// A Speed is a category of time control by estimated game duration as
+// used by lichess.
+type Speed int
+
+const (
+	// UnknownSpeed is the speed of an unknown time control.
+	UnknownSpeed Speed = iota
+	// UltraBullet games are estimated to last under 30 seconds per side.
+	UltraBullet
+	// Bullet games are estimated to last under 3 minutes per side.
+	Bullet
+	// Blitz games are estimated to last under 8 minutes per side.
+	Blitz
+	// Rapid games are estimated to last under 25 minutes per side.
+	Rapid
+	// Classical games are estimated to last 25 minutes or more per side.
+	Classical
+	// Correspondence games don't have a time control.
+	Correspondence
+)
+
+var speedNames = []string{"Unknown", "UltraBullet", "Bullet", "Blitz", "Rapid", "Classical", "Correspondence"}
+
+func (s Speed) String() string {
+	if s < 0 || int(s) >= len(speedNames) {
+		return speedNames[UnknownSpeed]
+	}
+	return speedNames[s]
+}
+
+// Speed returns the time control's speed from the estimated duration of
+// its first period, which is its time plus 40 increments.
+func (tc TimeControl) Speed() Speed {
+	if tc.Unlimited {
+		return Correspondence
+	}
+	if len(tc.Periods) == 0 {
+		return UnknownSpeed
+	}
+	p := tc.Periods[0]
+	estimate := p.Time + 40*p.Increment
+	switch {
+	case estimate < 30*time.Second:
+		return UltraBullet
+	case estimate < 3*time.Minute:
+		return Bullet
+	case estimate < 8*time.Minute:
+		return Blitz
+	case estimate < 25*time.Minute:
+		return Rapid
+	}
+	return Classical
+}
+
+// parseSeconds parses a whole or fractional number of seconds.
+func parseSeconds(s string) (time.Duration, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || strings.Trim(s, "0123456789.") != "" {
+		return 0, fmt.Errorf("chess: invalid seconds %q", s)
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
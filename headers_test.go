@@ -0,0 +1,105 @@
+package chess
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHeaders(t *testing.T) {
+	g := NewGame()
+	g.AddTagPair("WhiteElo", "2450")
+	g.AddTagPair("BlackElo", "?")
+	g.AddTagPair("Date", "2021.03.??")
+	g.AddTagPair("Round", "3.1")
+	g.AddTagPair("TimeControl", "180+2")
+
+	if elo, err := g.WhiteElo(); err != nil || elo != 2450 {
+		t.Fatalf("expected white elo 2450 but got %d %v", elo, err)
+	}
+	if _, err := g.BlackElo(); err == nil {
+		t.Fatal("expected an error for an unknown black elo")
+	}
+	if date, err := g.Date(); err != nil || !date.Equal(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 2021-03-01 but got %s %v", date, err)
+	}
+	if round, err := g.Round(); err != nil || !reflect.DeepEqual(round, []int{3, 1}) {
+		t.Fatalf("expected round [3 1] but got %v %v", round, err)
+	}
+	tc, err := g.TimeControl()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tc.Periods) != 1 || tc.Periods[0].Time != 3*time.Minute || tc.Periods[0].Increment != 2*time.Second {
+		t.Fatalf("unexpected time control %+v", tc)
+	}
+
+	g.RemoveTagPair("Round")
+	if _, err := g.Round(); err == nil {
+		t.Fatal("expected an error for a missing round")
+	}
+	for _, date := range []string{"????.??.??", "2021.02.30", "2021-03-01", "2021.13.01"} {
+		g.AddTagPair("Date", date)
+		if _, err := g.Date(); err == nil {
+			t.Fatalf("expected an error for date %s", date)
+		}
+	}
+}
+
+func TestParseTimeControl(t *testing.T) {
+	tests := []struct {
+		s       string
+		periods []TimeControlPeriod
+	}{
+		{"300", []TimeControlPeriod{{Time: 5 * time.Minute}}},
+		{"40/5400+30:1800+30", []TimeControlPeriod{
+			{Moves: 40, Time: 90 * time.Minute, Increment: 30 * time.Second},
+			{Time: 30 * time.Minute, Increment: 30 * time.Second},
+		}},
+		{"*180", []TimeControlPeriod{{Time: 3 * time.Minute, Sandclock: true}}},
+		{"60+0.5", []TimeControlPeriod{{Time: time.Minute, Increment: time.Second / 2}}},
+	}
+	for _, test := range tests {
+		tc, err := ParseTimeControl(test.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tc.Periods, test.periods) {
+			t.Fatalf("expected %+v for %s but got %+v", test.periods, test.s, tc.Periods)
+		}
+		if tc.String() != test.s {
+			t.Fatalf("expected %s but got %s", test.s, tc)
+		}
+	}
+	if tc, err := ParseTimeControl("-"); err != nil || !tc.Unlimited || tc.String() != "-" {
+		t.Fatalf("expected an unlimited time control but got %+v %v", tc, err)
+	}
+	for _, s := range []string{"", "abc", "0/300", "300+x", "NaN"} {
+		if _, err := ParseTimeControl(s); err == nil {
+			t.Fatalf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestTimeControlSpeed(t *testing.T) {
+	tests := map[string]Speed{
+		"15":      UltraBullet,
+		"60+1":    Bullet,
+		"180+2":   Blitz,
+		"600+5":   Rapid,
+		"40/5400": Classical,
+		"-":       Correspondence,
+	}
+	for s, speed := range tests {
+		tc, err := ParseTimeControl(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.Speed() != speed {
+			t.Fatalf("expected %s to be %s but got %s", s, speed, tc.Speed())
+		}
+	}
+	if (TimeControl{}).Speed() != UnknownSpeed {
+		t.Fatal("expected an empty time control to have an unknown speed")
+	}
+}
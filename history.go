@@ -0,0 +1,48 @@
+package chess
+
+// A PlyMove is a move of a game and its ply starting at one.
+type PlyMove struct {
+	Ply  int
+	Move *Move
+}
+
+// FilterMoves returns the moves of the game that satisfy keep which is
+// given each move and the position it was played from.
+func (g *Game) FilterMoves(keep func(pos *Position, m *Move) bool) []PlyMove {
+	moves := []PlyMove{}
+	for i, m := range g.moves {
+		if keep(g.positions[i], m) {
+			moves = append(moves, PlyMove{Ply: i + 1, Move: m})
+		}
+	}
+	return moves
+}
+
+// Checks returns the moves of the game that gave check.
+func (g *Game) Checks() []PlyMove {
+	return g.FilterMoves(func(pos *Position, m *Move) bool {
+		return m.HasTag(Check)
+	})
+}
+
+// Captures returns the captures made by the color or by either color if
+// the color is NoColor.
+func (g *Game) Captures(c Color) []PlyMove {
+	return g.FilterMoves(func(pos *Position, m *Move) bool {
+		return (m.HasTag(Capture) || m.HasTag(EnPassant)) && (c == NoColor || pos.turn == c)
+	})
+}
+
+// Promotions returns the moves of the game that promoted a pawn.
+func (g *Game) Promotions() []PlyMove {
+	return g.FilterMoves(func(pos *Position, m *Move) bool {
+		return m.promo != NoPieceType
+	})
+}
+
+// CastlingMoves returns the moves of the game that castled.
+func (g *Game) CastlingMoves() []PlyMove {
+	return g.FilterMoves(func(pos *Position, m *Move) bool {
+		return m.HasTag(KingSideCastle) || m.HasTag(QueenSideCastle)
+	})
+}
@@ -0,0 +1,50 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMoveFilters(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("1. e4 d5 2. exd5 Qxd5 3. Nc3 Qe5+ 4. Be2 Bg4 5. Nf3 Bxf3 6. gxf3 Nc6 7. d4 O-O-O 8. dxe5 Rxd1+ 9. Kxd1 Nxe5 *"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	tests := []struct {
+		name  string
+		moves []PlyMove
+		plies []int
+	}{
+		{"checks", g.Checks(), []int{6, 16}},
+		{"white captures", g.Captures(White), []int{3, 11, 15, 17}},
+		{"black captures", g.Captures(Black), []int{4, 10, 16, 18}},
+		{"all captures", g.Captures(NoColor), []int{3, 4, 10, 11, 15, 16, 17, 18}},
+		{"castling", g.CastlingMoves(), []int{14}},
+		{"promotions", g.Promotions(), []int{}},
+	}
+	for _, test := range tests {
+		if len(test.moves) != len(test.plies) {
+			t.Fatalf("%s: expected plies %v but got %v", test.name, test.plies, test.moves)
+		}
+		for i, ply := range test.plies {
+			if test.moves[i].Ply != ply || test.moves[i].Move != g.Moves()[ply-1] {
+				t.Fatalf("%s: expected plies %v but got %v", test.name, test.plies, test.moves)
+			}
+		}
+	}
+}
+
+func TestPromotionFilter(t *testing.T) {
+	fen, err := FEN("8/P6k/8/8/8/8/8/K7 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(fen)
+	if err := g.MoveStr("a8=N"); err != nil {
+		t.Fatal(err)
+	}
+	if p := g.Promotions(); len(p) != 1 || p[0].Ply != 1 || p[0].Move.Promo() != Knight {
+		t.Fatalf("expected a knight promotion but got %v", p)
+	}
+}
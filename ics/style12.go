@@ -0,0 +1,207 @@
+// Package ics provides parsing for the formats used by internet chess
+// servers such as FICS (freechess.org) so that clients can be built with
+// this package's game model.
+package ics
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+// Relation is the relation of the client to the game in a style 12 line.
+type Relation int
+
+const (
+	// IsolatedPosition indicates an isolated position such as from "ref 3" or "sposition".
+	IsolatedPosition Relation = -3
+	// ObservingExamined indicates the client is observing an examined game.
+	ObservingExamined Relation = -2
+	// OpponentsMove indicates the client is playing and it is the opponent's move.
+	OpponentsMove Relation = -1
+	// Observing indicates the client is observing a game being played.
+	Observing Relation = 0
+	// MyMove indicates the client is playing and it is the client's move.
+	MyMove Relation = 1
+	// Examiner indicates the client is the examiner of the game.
+	Examiner Relation = 2
+)
+
+// Style12 is a board update in the FICS "style 12" format:
+// <12> rnbqkbnr pppppppp -------- -------- ----P--- -------- PPPP-PPP RNBQKBNR B 4 1 1 1 1 0 7 Newton Einstein 1 2 12 39 39 119 122 1 P/e2-e4 (0:06) e4 0
+type Style12 struct {
+	Position       *chess.Position
+	GameNumber     int
+	White          string
+	Black          string
+	Relation       Relation
+	InitialTime    time.Duration
+	Increment      time.Duration
+	WhiteStrength  int
+	BlackStrength  int
+	WhiteRemaining time.Duration
+	BlackRemaining time.Duration
+	// MoveNumber is the number of the move about to be made.
+	MoveNumber int
+	// VerboseMove is the previous move in verbose coordinate notation
+	// (ex. P/e2-e4) or "none".
+	VerboseMove string
+	// MoveTime is the time taken for the previous move.
+	MoveTime time.Duration
+	// PrettyMove is the previous move in algebraic notation or "none".
+	PrettyMove string
+	// Flip is true if black should be displayed at the bottom.
+	Flip bool
+}
+
+// ParseStyle12 parses a style 12 line.  The line may include the "<12>"
+// prefix.  An error is returned if the line is malformed.
+func ParseStyle12(line string) (*Style12, error) {
+	fields := strings.Fields(strings.TrimSpace(line))
+	if len(fields) > 0 && fields[0] == "<12>" {
+		fields = fields[1:]
+	}
+	// servers may append extra fields (ex. clock ticking and lag)
+	if len(fields) < 30 {
+		return nil, fmt.Errorf("ics: style 12 line has %d fields but requires at least 30", len(fields))
+	}
+	pos, err := style12Position(fields)
+	if err != nil {
+		return nil, err
+	}
+	ints := map[int]int{}
+	for _, i := range []int{15, 18, 19, 20, 21, 22, 23, 24, 25} {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("ics: style 12 invalid integer field %s", fields[i])
+		}
+		ints[i] = n
+	}
+	moveTime, err := parseMoveTime(fields[27])
+	if err != nil {
+		return nil, err
+	}
+	return &Style12{
+		Position:       pos,
+		GameNumber:     ints[15],
+		White:          fields[16],
+		Black:          fields[17],
+		Relation:       Relation(ints[18]),
+		InitialTime:    time.Duration(ints[19]) * time.Minute,
+		Increment:      time.Duration(ints[20]) * time.Second,
+		WhiteStrength:  ints[21],
+		BlackStrength:  ints[22],
+		WhiteRemaining: time.Duration(ints[23]) * time.Second,
+		BlackRemaining: time.Duration(ints[24]) * time.Second,
+		MoveNumber:     ints[25],
+		VerboseMove:    fields[26],
+		MoveTime:       moveTime,
+		PrettyMove:     fields[28],
+		Flip:           fields[29] == "1",
+	}, nil
+}
+
+func style12Position(fields []string) (*chess.Position, error) {
+	for _, r := range fields[:8] {
+		if len(r) != 8 {
+			return nil, fmt.Errorf("ics: style 12 invalid rank %s", r)
+		}
+	}
+	board, err := chess.ParseCompactBoard(strings.Join(fields[:8], ""))
+	if err != nil {
+		return nil, fmt.Errorf("ics: style 12 invalid board: %s", err)
+	}
+	turn := "w"
+	switch fields[8] {
+	case "W":
+	case "B":
+		turn = "b"
+	default:
+		return nil, fmt.Errorf("ics: style 12 invalid color to move %s", fields[8])
+	}
+	ep := "-"
+	file, err := strconv.Atoi(fields[9])
+	if err != nil || file < -1 || file > 7 {
+		return nil, fmt.Errorf("ics: style 12 invalid double pawn push file %s", fields[9])
+	}
+	if file != -1 {
+		ep = string("abcdefgh"[file]) + "6"
+		if turn == "b" {
+			ep = string("abcdefgh"[file]) + "3"
+		}
+	}
+	castle := ""
+	for i, c := range "KQkq" {
+		if fields[10+i] == "1" {
+			castle += string(c)
+		}
+	}
+	if castle == "" {
+		castle = "-"
+	}
+	moveNum, err := strconv.Atoi(fields[25])
+	if err != nil || moveNum < 1 {
+		return nil, fmt.Errorf("ics: style 12 invalid move number %s", fields[25])
+	}
+	fen := fmt.Sprintf("%s %s %s %s %s %d", board, turn, castle, ep, fields[14], moveNum)
+	pos := &chess.Position{}
+	if err := pos.UnmarshalText([]byte(fen)); err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// parseMoveTime parses the "(min:sec)" or "(min:sec.ms)" move time field.
+func parseMoveTime(s string) (time.Duration, error) {
+	err := fmt.Errorf("ics: style 12 invalid move time %s", s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, err
+	}
+	mins, e1 := strconv.Atoi(parts[0])
+	sec, e2 := strconv.ParseFloat(parts[1], 64)
+	if e1 != nil || e2 != nil {
+		return 0, err
+	}
+	return time.Duration(mins)*time.Minute + time.Duration(sec*float64(time.Second)), nil
+}
+
+// VerboseNotation is the verbose coordinate notation used by internet chess
+// servers.  Examples: P/e2-e4, N/g1-f3, P/e7-e8=Q, o-o, o-o-o
+type VerboseNotation struct{}
+
+// String implements the fmt.Stringer interface and returns
+// the notation's name.
+func (VerboseNotation) String() string {
+	return "Verbose Notation"
+}
+
+// Encode implements the chess.Encoder interface.
+func (VerboseNotation) Encode(pos *chess.Position, m *chess.Move) string {
+	if m.HasTag(chess.KingSideCastle) {
+		return "o-o"
+	} else if m.HasTag(chess.QueenSideCastle) {
+		return "o-o-o"
+	}
+	p := pos.Board().Piece(m.S1())
+	s := strings.ToUpper(p.Type().String()) + "/" + m.S1().String() + "-" + m.S2().String()
+	if m.Promo() != chess.NoPieceType {
+		s += "=" + strings.ToUpper(m.Promo().String())
+	}
+	return s
+}
+
+// Decode implements the chess.Decoder interface.
+func (VerboseNotation) Decode(pos *chess.Position, s string) (*chess.Move, error) {
+	for _, m := range pos.ValidMoves() {
+		if strings.EqualFold((VerboseNotation{}).Encode(pos, m), s) {
+			return m, nil
+		}
+	}
+	return nil, errors.New("ics: could not decode verbose notation " + s + " for position " + pos.String())
+}
@@ -0,0 +1,52 @@
+package ics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/ics"
+)
+
+func TestParseStyle12(t *testing.T) {
+	line := "<12> rnbqkbnr pppppppp -------- -------- ----P--- -------- PPPP-PPP RNBQKBNR B 4 1 1 1 1 0 7 Newton Einstein 1 2 12 39 39 119 122 1 P/e2-e4 (0:06) e4 0"
+	s, err := ics.ParseStyle12(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"
+	if s.Position.String() != expected {
+		t.Fatalf("expected position %s but got %s", expected, s.Position)
+	}
+	if s.White != "Newton" || s.Black != "Einstein" || s.Relation != ics.MyMove {
+		t.Fatalf("unexpected players or relation %+v", s)
+	}
+	if s.InitialTime != 2*time.Minute || s.Increment != 12*time.Second || s.BlackRemaining != 122*time.Second {
+		t.Fatalf("unexpected clock fields %+v", s)
+	}
+	if s.MoveTime != 6*time.Second || s.PrettyMove != "e4" || s.Flip {
+		t.Fatalf("unexpected move fields %+v", s)
+	}
+	if _, err := ics.ParseStyle12("<12> rnbqkbnr pppppppp W"); err == nil {
+		t.Fatal("expected error for truncated line")
+	}
+}
+
+func TestVerboseNotation(t *testing.T) {
+	pos := &chess.Position{}
+	if err := pos.UnmarshalText([]byte("r3k2r/1P6/8/8/8/8/8/R3K2R w KQkq - 0 1")); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"o-o", "o-o-o", "R/a1-a8", "P/b7-b8=N", "K/e1-d2"} {
+		m, err := ics.VerboseNotation{}.Decode(pos, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if enc := (ics.VerboseNotation{}).Encode(pos, m); enc != s {
+			t.Fatalf("expected %s to encode as itself but got %s", s, enc)
+		}
+	}
+	if _, err := (ics.VerboseNotation{}).Decode(pos, "P/e2-e4"); err == nil {
+		t.Fatal("expected error for invalid move")
+	}
+}
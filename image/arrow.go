@@ -0,0 +1,103 @@
+package image
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	svg "github.com/ajstarks/svgo"
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/uci"
+)
+
+// An Arrow is an arrow drawn on the board from the center of one square
+// to another.
+type Arrow struct {
+	From  chess.Square
+	To    chess.Square
+	Color color.Color
+}
+
+// Arrows is designed to be used as an optional argument to the SVG
+// function.  It draws the arrows over the pieces in the order given.
+func Arrows(arrows ...Arrow) func(*encoder) {
+	return func(e *encoder) {
+		e.arrows = append(e.arrows, arrows...)
+	}
+}
+
+// PVArrows is designed to be used as an optional argument to the SVG
+// function.  It draws an arrow for the first move of each principal
+// variation, such as the lines of an engine searching with the MultiPV
+// option.  The best line's arrow is green and the others shade towards
+// red as their expected score (using chess.DefaultWDLModel) falls behind
+// the best line's.  Lines without moves are skipped.
+func PVArrows(infos ...uci.Info) func(*encoder) {
+	return func(e *encoder) {
+		best := -1.0
+		expected := make([]float64, len(infos))
+		for i, info := range infos {
+			expected[i] = chess.DefaultWDLModel.WDL(scoreCentipawns(info.Score)).Expected()
+			best = math.Max(best, expected[i])
+		}
+		// weaker moves are drawn first so the best move is on top
+		arrows := []Arrow{}
+		for i, info := range infos {
+			if len(info.PV) == 0 {
+				continue
+			}
+			arrows = append(arrows, Arrow{
+				From:  info.PV[0].S1(),
+				To:    info.PV[0].S2(),
+				Color: strengthColor(best - expected[i]),
+			})
+		}
+		for i := len(arrows) - 1; i >= 0; i-- {
+			e.arrows = append(e.arrows, arrows[i])
+		}
+	}
+}
+
+// scoreCentipawns returns the score in centipawns treating mates as
+// decisive.
+func scoreCentipawns(s uci.Score) int {
+	switch {
+	case s.Mate > 0:
+		return 10000
+	case s.Mate < 0:
+		return -10000
+	}
+	return s.CP
+}
+
+// strengthColor returns green for no loss of expected score fading to red
+// for a loss of 0.2 or more.
+func strengthColor(loss float64) color.Color {
+	t := math.Max(0, math.Min(1, loss/0.2))
+	return color.RGBA{
+		R: uint8(21 + t*(200-21)),
+		G: uint8(120 + t*(40-120)),
+		B: uint8(27 + t*(40-27)),
+		A: 1,
+	}
+}
+
+const arrowHead = 14
+
+func drawArrow(canvas *svg.SVG, a Arrow) {
+	x1, y1 := xyForSquare(a.From)
+	x2, y2 := xyForSquare(a.To)
+	fx, fy := float64(x1+sqWidth/2), float64(y1+sqHeight/2)
+	tx, ty := float64(x2+sqWidth/2), float64(y2+sqHeight/2)
+	angle := math.Atan2(ty-fy, tx-fx)
+	// the shaft stops at the base of the head
+	bx, by := tx-arrowHead*math.Cos(angle), ty-arrowHead*math.Sin(angle)
+	c := colorToHex(a.Color)
+	canvas.Line(int(fx), int(fy), int(bx), int(by), fmt.Sprintf("stroke: %s;stroke-width:8;stroke-opacity:0.8;stroke-linecap:round", c))
+	px, py := math.Cos(angle+math.Pi/2)*arrowHead*0.7, math.Sin(angle+math.Pi/2)*arrowHead*0.7
+	canvas.Polygon(
+		[]int{int(tx), int(bx + px), int(bx - px)},
+		[]int{int(ty), int(by + py), int(by - py)},
+		fmt.Sprintf("fill: %s;fill-opacity:0.8", c),
+	)
+}
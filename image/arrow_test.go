@@ -0,0 +1,53 @@
+package image_test
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/image"
+	"github.com/notnil/chess/uci"
+)
+
+func TestArrows(t *testing.T) {
+	buf := &bytes.Buffer{}
+	arrow := image.Arrow{From: chess.E2, To: chess.E4, Color: color.RGBA{0, 0, 255, 1}}
+	if err := image.SVG(buf, chess.StartingPosition().Board(), image.Arrows(arrow)); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); !strings.Contains(s, `<line x1="202" y1="292" x2="202" y2="216" style="stroke: #0000ff`) || !strings.Contains(s, "<polygon") {
+		t.Fatalf("expected an arrow from e2 to e4 but got\n%s", s)
+	}
+}
+
+func TestPVArrows(t *testing.T) {
+	pos := chess.StartingPosition()
+	e4, _ := chess.UCINotation{}.Decode(pos, "e2e4")
+	d4, _ := chess.UCINotation{}.Decode(pos, "d2d4")
+	a3, _ := chess.UCINotation{}.Decode(pos, "a2a3")
+	infos := []uci.Info{
+		{PV: []*chess.Move{e4}, Score: uci.Score{CP: 30}},
+		{PV: []*chess.Move{d4}, Score: uci.Score{CP: 25}},
+		{PV: []*chess.Move{a3}, Score: uci.Score{CP: -300}},
+		{Score: uci.Score{CP: 0}},
+	}
+	buf := &bytes.Buffer{}
+	if err := image.SVG(buf, pos.Board(), image.PVArrows(infos...)); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	if strings.Count(s, "<line") != 3 {
+		t.Fatalf("expected three arrows but got\n%s", s)
+	}
+	// the weakest arrow is drawn first and the best last
+	a3Index := strings.Index(s, `x1="22" y1="292"`)
+	e4Index := strings.Index(s, `x1="202" y1="292"`)
+	if a3Index == -1 || e4Index == -1 || a3Index > e4Index {
+		t.Fatalf("expected a3 to be drawn before e4 but got\n%s", s)
+	}
+	if !strings.Contains(s[e4Index:], "stroke: #15781b") {
+		t.Fatalf("expected the best move to be green but got\n%s", s[e4Index:])
+	}
+}
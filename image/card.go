@@ -0,0 +1,111 @@
+package image
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"time"
+
+	svg "github.com/ajstarks/svgo"
+	"github.com/notnil/chess"
+)
+
+const (
+	cardBarHeight    = 30
+	cardFooterHeight = 24
+)
+
+// CardClocks is designed to be used as an optional argument to the
+// GameCardSVG function.  It shows the remaining time of each player
+// beside their name.
+func CardClocks(white, black time.Duration) func(*encoder) {
+	return func(e *encoder) {
+		e.clocks = []time.Duration{white, black}
+	}
+}
+
+// GameCardSVG writes an SVG "game card" of the game's current position:
+// the board with the player names and ratings from the White, Black,
+// WhiteElo and BlackElo tag pairs above and below it and the last move
+// and result beneath.  The squares of the last move are marked unless
+// MarkSquares is given.  GameCardSVG takes the same options as SVG as
+// well as CardClocks.  An error is returned if there is an error writing
+// data.
+func GameCardSVG(w io.Writer, g *chess.Game, opts ...func(*encoder)) error {
+	e := new(w, opts)
+	moves := g.Moves()
+	if len(e.marks) == 0 && len(moves) > 0 {
+		last := moves[len(moves)-1]
+		MarkSquares(color.RGBA{255, 255, 0, 1}, last.S1(), last.S2())(e)
+	}
+	width := boardWidth
+	if e.evalBar != nil {
+		width += evalBarWidth
+	}
+	canvas := svg.New(e.w)
+	canvas.Start(width, 2*cardBarHeight+boardHeight+cardFooterHeight)
+	canvas.Rect(0, 0, width, 2*cardBarHeight+boardHeight+cardFooterHeight, "fill: #302e2b")
+	e.drawPlayer(canvas, g, chess.Black, 0, width)
+	canvas.Translate(0, cardBarHeight)
+	if err := e.drawBoard(canvas, g.Position().Board()); err != nil {
+		return err
+	}
+	canvas.Gend()
+	e.drawPlayer(canvas, g, chess.White, cardBarHeight+boardHeight, width)
+	footer := lastMoveText(g)
+	if g.Outcome() != chess.NoOutcome {
+		footer += "  " + g.Outcome().String()
+	}
+	canvas.Text(8, 2*cardBarHeight+boardHeight+cardFooterHeight-8, footer, "font-size:13px;fill: #bababa")
+	canvas.End()
+	return nil
+}
+
+// drawPlayer draws the name, rating and clock of the player in a bar
+// starting at y.
+func (e *encoder) drawPlayer(canvas *svg.SVG, g *chess.Game, c chess.Color, y, width int) {
+	name := c.Name()
+	if tp := g.GetTagPair(c.Name()); tp != nil && tp.Value != "" && tp.Value != "?" {
+		name = tp.Value
+	}
+	if tp := g.GetTagPair(c.Name() + "Elo"); tp != nil && tp.Value != "" && tp.Value != "-" && tp.Value != "?" {
+		name += " (" + tp.Value + ")"
+	}
+	style := "font-size:15px;font-weight:bold;fill: #ffffff"
+	canvas.Text(8, y+cardBarHeight-10, name, style)
+	if e.clocks == nil {
+		return
+	}
+	clock := e.clocks[0]
+	if c == chess.Black {
+		clock = e.clocks[1]
+	}
+	canvas.Text(width-8, y+cardBarHeight-10, formatClock(clock), "text-anchor:end;"+style)
+}
+
+// lastMoveText returns the last move in algebraic notation with its move
+// number such as 23...Qxe4+ or an empty string if no moves were played.
+func lastMoveText(g *chess.Game) string {
+	moves := g.Moves()
+	if len(moves) == 0 {
+		return ""
+	}
+	pos := g.Positions()[len(moves)-1]
+	san := chess.AlgebraicNotation{}.Encode(pos, moves[len(moves)-1])
+	if pos.Turn() == chess.Black {
+		return fmt.Sprintf("%d...%s", pos.FullMoveNumber(), san)
+	}
+	return fmt.Sprintf("%d.%s", pos.FullMoveNumber(), san)
+}
+
+// formatClock formats the duration as h:mm:ss or m:ss.
+func formatClock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	s := int(d / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
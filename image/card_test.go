@@ -0,0 +1,42 @@
+package image_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/image"
+)
+
+func TestGameCardSVG(t *testing.T) {
+	pgn, err := chess.PGN(strings.NewReader(`[White "Carlsen & Co"]
+[Black "Nepomniachtchi"]
+[WhiteElo "2855"]
+[BlackElo "?"]
+
+1. e4 e5 2. Nf3 Nc6 *`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := chess.NewGame(pgn)
+	buf := &bytes.Buffer{}
+	if err := image.GameCardSVG(buf, g, image.CardClocks(3*time.Minute+5*time.Second, time.Hour+time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, expected := range []string{
+		">Carlsen &amp; Co (2855)</text>",
+		">Nepomniachtchi</text>",
+		">3:05</text>",
+		">1:00:01</text>",
+		">2...Nc6</text>",
+		`transform="translate(0,30)"`,
+		`height="444"`,
+	} {
+		if !strings.Contains(s, expected) {
+			t.Fatalf("expected game card to contain %s but got\n%s", expected, s)
+		}
+	}
+}
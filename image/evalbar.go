@@ -0,0 +1,80 @@
+package image
+
+import (
+	"fmt"
+	"io"
+
+	svg "github.com/ajstarks/svgo"
+	"github.com/notnil/chess"
+)
+
+const evalBarWidth = 20
+
+// EvalBar is designed to be used as an optional argument to the SVG
+// function.  It draws an evaluation bar left of the board for the score
+// from white's perspective given in centipawns or, if mate isn't zero,
+// as mate in that many moves (negative if black is mating).  The white
+// portion grows from the bottom using chess.DefaultWDLModel so it
+// matches the evaluation bar of the Board's Draw method.
+func EvalBar(centipawns, mate int) func(*encoder) {
+	return func(e *encoder) {
+		e.evalBar = &evalBar{centipawns: centipawns, mate: mate}
+	}
+}
+
+// EvalBarSVG writes an evaluation bar on its own as a vertical strip the
+// height of the board.  See EvalBar for the meaning of the arguments.
+// An error is returned if there is an error writing data.
+func EvalBarSVG(w io.Writer, centipawns, mate int) error {
+	canvas := svg.New(w)
+	canvas.Start(evalBarWidth, boardHeight)
+	(&evalBar{centipawns: centipawns, mate: mate}).draw(canvas)
+	canvas.End()
+	return nil
+}
+
+type evalBar struct {
+	centipawns int
+	mate       int
+}
+
+// white returns the height of the white portion of the bar.
+func (b *evalBar) white() int {
+	switch {
+	case b.mate > 0:
+		return boardHeight
+	case b.mate < 0:
+		return 0
+	}
+	expected := chess.DefaultWDLModel.WDL(b.centipawns).Expected()
+	return int(expected*boardHeight + 0.5)
+}
+
+// label returns the score shown in the bar such as 1.3 or M4.
+func (b *evalBar) label() string {
+	if b.mate != 0 {
+		return fmt.Sprintf("M%d", abs(b.mate))
+	}
+	return fmt.Sprintf("%.1f", float64(abs(b.centipawns))/100)
+}
+
+func (b *evalBar) draw(canvas *svg.SVG) {
+	white := b.white()
+	canvas.Rect(0, 0, evalBarWidth, boardHeight-white, "fill: #403d39")
+	canvas.Rect(0, boardHeight-white, evalBarWidth, white, "fill: #f0f0f0")
+	// the label is drawn at the end of the side that is ahead
+	style := "text-anchor:middle;font-size:8px;fill: #403d39"
+	y := boardHeight - 4
+	if b.mate < 0 || (b.mate == 0 && b.centipawns < 0) {
+		style = "text-anchor:middle;font-size:8px;fill: #f0f0f0"
+		y = 10
+	}
+	canvas.Text(evalBarWidth/2, y, b.label(), style)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
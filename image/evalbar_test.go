@@ -0,0 +1,33 @@
+package image_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/image"
+)
+
+func TestEvalBar(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	if err := image.SVG(buf, chess.StartingPosition().Board(), image.EvalBar(130, 0)); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	if !strings.Contains(s, `width="380"`) || !strings.Contains(s, `transform="translate(20,0)"`) {
+		t.Fatalf("expected the board to be offset by the eval bar but got\n%s", s)
+	}
+	if !strings.Contains(s, ">1.3</text>") {
+		t.Fatalf("expected the score label in\n%s", s)
+	}
+
+	buf.Reset()
+	if err := image.EvalBarSVG(buf, 0, -3); err != nil {
+		t.Fatal(err)
+	}
+	s = buf.String()
+	if !strings.Contains(s, ">M3</text>") || !strings.Contains(s, `<rect x="0" y="0" width="20" height="360"`) {
+		t.Fatalf("expected a black mate bar but got\n%s", s)
+	}
+}
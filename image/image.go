@@ -6,6 +6,7 @@ import (
 	"image/color"
 	"io"
 	"strings"
+	"time"
 
 	svg "github.com/ajstarks/svgo"
 	"github.com/notnil/chess"
@@ -48,6 +49,11 @@ type encoder struct {
 	light color.Color
 	dark  color.Color
 	marks map[chess.Square]color.Color
+	// evalBar is the eval bar drawn left of the board or nil
+	evalBar *evalBar
+	// clocks are White's and Black's clocks for game cards or nil
+	clocks []time.Duration
+	arrows []Arrow
 }
 
 // New returns an encoder that writes to the given writer.
@@ -82,9 +88,27 @@ var (
 // the Encoder's writer.  An error is returned if there
 // is there is an error writing data.
 func (e *encoder) EncodeSVG(b *chess.Board) error {
-	boardMap := b.SquareMap()
 	canvas := svg.New(e.w)
-	canvas.Start(boardWidth, boardHeight)
+	width := boardWidth
+	if e.evalBar != nil {
+		width += evalBarWidth
+	}
+	canvas.Start(width, boardHeight)
+	if err := e.drawBoard(canvas, b); err != nil {
+		return err
+	}
+	canvas.End()
+	return nil
+}
+
+// drawBoard draws the board and the eval bar if there is one at the
+// canvas's origin.
+func (e *encoder) drawBoard(canvas *svg.SVG, b *chess.Board) error {
+	boardMap := b.SquareMap()
+	if e.evalBar != nil {
+		e.evalBar.draw(canvas)
+		canvas.Translate(evalBarWidth, 0)
+	}
 	canvas.Rect(0, 0, boardWidth, boardHeight)
 
 	for i := 0; i < 64; i++ {
@@ -117,7 +141,12 @@ func (e *encoder) EncodeSVG(b *chess.Board) error {
 			canvas.Text(x+(sqWidth*19/20), y+sqHeight-(sqHeight*1/15), sq.File().String(), style)
 		}
 	}
-	canvas.End()
+	for _, a := range e.arrows {
+		drawArrow(canvas, a)
+	}
+	if e.evalBar != nil {
+		canvas.Gend()
+	}
 	return nil
 }
 
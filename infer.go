@@ -0,0 +1,47 @@
+package chess
+
+// Diff returns the squares whose pieces differ between the board and
+// the given board.  The squares are returned in ascending order.
+func (b *Board) Diff(other *Board) []Square {
+	sqs := []Square{}
+	for sq := 0; sq < numOfSquaresInBoard; sq++ {
+		if b.Piece(Square(sq)) != other.Piece(Square(sq)) {
+			sqs = append(sqs, Square(sq))
+		}
+	}
+	return sqs
+}
+
+// InferMove returns the valid move that transforms the position's board
+// into the given board or nil if there is no such move.  It is designed for
+// physical boards and other inputs that report piece placement instead of
+// moves.  Promotions are distinguished by the piece placed on the promotion
+// square.
+func (pos *Position) InferMove(b *Board) *Move {
+	diff := pos.board.Diff(b)
+	// a move changes two squares, or three / four for en passant and castling
+	if len(diff) < 2 || len(diff) > 4 {
+		return nil
+	}
+	target := b.String()
+	for _, m := range pos.ValidMoves() {
+		if !containsSquare(diff, m.s1) || !containsSquare(diff, m.s2) {
+			continue
+		}
+		cp := pos.board.copy()
+		cp.update(m)
+		if cp.String() == target {
+			return m
+		}
+	}
+	return nil
+}
+
+func containsSquare(sqs []Square, sq Square) bool {
+	for _, s := range sqs {
+		if s == sq {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package chess
+
+import "testing"
+
+func TestInferMove(t *testing.T) {
+	tests := []struct {
+		fen   string
+		board string
+		move  string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR", "e2e4"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "r3k2r/8/8/8/8/8/8/R4RK1", "e1g1"},
+		{"8/8/8/3pP3/8/8/8/4K2k w - d6 0 1", "8/8/3P4/8/8/8/8/4K2k", "e5d6"},
+		{"8/P7/8/8/8/8/8/4K2k w - - 0 1", "N7/8/8/8/8/8/8/4K2k", "a7a8n"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "rnbqkbnr/pppppppp/8/8/8/4P3/PPPP1PP1/RNBQKBNR", ""},
+	}
+	for _, test := range tests {
+		pos := unsafeFEN(test.fen)
+		b := &Board{}
+		if err := b.UnmarshalText([]byte(test.board)); err != nil {
+			t.Fatal(err)
+		}
+		m := pos.InferMove(b)
+		s := ""
+		if m != nil {
+			s = m.String()
+		}
+		if s != test.move {
+			t.Fatalf("expected to infer move %q from %s to %s but got %q", test.move, test.fen, test.board, s)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package weighted chooses random indexes in proportion to their weights.
+package weighted
+
+import "math/rand"
+
+// Index returns a random index of the weights chosen with
+// probability proportional to its weight, or -1 if no weight is positive.
+// Negative weights count as zero.  If r is nil the math/rand default
+// source is used.
+func Index(weights []float64, r *rand.Rand) int {
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+	float64n := rand.Float64
+	if r != nil {
+		float64n = r.Float64
+	}
+	x := float64n() * total
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	// rounding can leave x just above the last weight
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return i
+		}
+	}
+	return -1
+}
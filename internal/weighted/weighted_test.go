@@ -0,0 +1,20 @@
+package weighted
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, 4)
+	for i := 0; i < 1000; i++ {
+		counts[Index([]float64{0, 3, -1, 1}, r)]++
+	}
+	if counts[0] != 0 || counts[2] != 0 || counts[1] < 2*counts[3] {
+		t.Fatalf("expected indexes in proportion to their positive weights but got %v", counts)
+	}
+	if i := Index([]float64{0, -1}, nil); i != -1 {
+		t.Fatalf("expected -1 without a positive weight but got %d", i)
+	}
+}
@@ -0,0 +1,133 @@
+package chess
+
+import (
+	"strings"
+)
+
+// A MarkupColor is the color of an arrow or highlighted square drawn on
+// the board by study tools such as those of lichess and chess.com.
+type MarkupColor byte
+
+const (
+	// MarkupGreen is green.
+	MarkupGreen MarkupColor = 'G'
+	// MarkupRed is red.
+	MarkupRed MarkupColor = 'R'
+	// MarkupYellow is yellow.
+	MarkupYellow MarkupColor = 'Y'
+	// MarkupBlue is blue.
+	MarkupBlue MarkupColor = 'B'
+)
+
+// An Arrow is an arrow drawn from one square to another with the [%cal]
+// comment command such as [%cal Gd2d4,Re2e4].
+type Arrow struct {
+	From  Square
+	To    Square
+	Color MarkupColor
+}
+
+func (a Arrow) String() string {
+	return string(a.Color) + a.From.String() + a.To.String()
+}
+
+// A HighlightedSquare is a square highlighted with the [%csl] comment
+// command such as [%csl Gd4,Re5].
+type HighlightedSquare struct {
+	Square Square
+	Color  MarkupColor
+}
+
+func (h HighlightedSquare) String() string {
+	return string(h.Color) + h.Square.String()
+}
+
+// Arrows returns the arrows of the [%cal] commands in the move's comments.
+// Invalid entries are skipped.  Comments are only kept by decoding with
+// PreservePGN.
+func (m *Move) Arrows() []Arrow {
+	arrows := []Arrow{}
+	for _, entry := range markupEntries(m.comments, "cal") {
+		if len(entry) != 5 {
+			continue
+		}
+		from, ok1 := strToSquareMap[entry[1:3]]
+		to, ok2 := strToSquareMap[entry[3:5]]
+		if !ok1 || !ok2 || !isMarkupColor(entry[0]) {
+			continue
+		}
+		arrows = append(arrows, Arrow{From: from, To: to, Color: MarkupColor(entry[0])})
+	}
+	return arrows
+}
+
+// HighlightedSquares returns the squares of the [%csl] commands in the
+// move's comments.  Invalid entries are skipped.  Comments are only kept
+// by decoding with PreservePGN.
+func (m *Move) HighlightedSquares() []HighlightedSquare {
+	squares := []HighlightedSquare{}
+	for _, entry := range markupEntries(m.comments, "csl") {
+		if len(entry) != 3 {
+			continue
+		}
+		sq, ok := strToSquareMap[entry[1:]]
+		if !ok || !isMarkupColor(entry[0]) {
+			continue
+		}
+		squares = append(squares, HighlightedSquare{Square: sq, Color: MarkupColor(entry[0])})
+	}
+	return squares
+}
+
+// SetArrows replaces the arrows of the move at the given ply where 1 is
+// the first move of the game.  No arrows removes the [%cal] command.  An
+// error is returned if the ply is out of range.
+func (g *Game) SetArrows(ply int, arrows ...Arrow) error {
+	entries := []string{}
+	for _, a := range arrows {
+		entries = append(entries, a.String())
+	}
+	return g.setMarkup(ply, "cal", entries)
+}
+
+// SetHighlightedSquares replaces the highlighted squares of the move at
+// the given ply where 1 is the first move of the game.  No squares removes
+// the [%csl] command.  An error is returned if the ply is out of range.
+func (g *Game) SetHighlightedSquares(ply int, squares ...HighlightedSquare) error {
+	entries := []string{}
+	for _, h := range squares {
+		entries = append(entries, h.String())
+	}
+	return g.setMarkup(ply, "csl", entries)
+}
+
+func (g *Game) setMarkup(ply int, name string, entries []string) error {
+	if err := g.checkPly(ply); err != nil {
+		return err
+	}
+	m := g.moves[ply-1]
+	// a move may have several commands which are merged into one
+	if len(commentCommands(m.comments, name)) > 1 {
+		m.comments = removeCommentCommand(m.comments, name)
+	}
+	m.comments = setCommentCommand(m.comments, name, strings.Join(entries, ","), g.Annotator())
+	return nil
+}
+
+// markupEntries returns the comma separated entries of every command with
+// the name in the comments.
+func markupEntries(comments []Comment, name string) []string {
+	entries := []string{}
+	for _, value := range commentCommands(comments, name) {
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+func isMarkupColor(c byte) bool {
+	return strings.IndexByte("GRYB", c) != -1
+}
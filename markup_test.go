@@ -0,0 +1,44 @@
+package chess
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarkup(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("1. e4 { [%csl Gd4,Re5] [%cal Gd2d4,Rg1f3] Center. } 1... e5 { [%cal Bb8c6] } { [%cal Yg8f6,Xa1a2] } *"), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	moves := g.Moves()
+	arrows := []Arrow{{D2, D4, MarkupGreen}, {G1, F3, MarkupRed}}
+	if a := moves[0].Arrows(); !reflect.DeepEqual(a, arrows) {
+		t.Fatalf("expected arrows %v but got %v", arrows, a)
+	}
+	squares := []HighlightedSquare{{D4, MarkupGreen}, {E5, MarkupRed}}
+	if s := moves[0].HighlightedSquares(); !reflect.DeepEqual(s, squares) {
+		t.Fatalf("expected squares %v but got %v", squares, s)
+	}
+	arrows = []Arrow{{B8, C6, MarkupBlue}, {G8, F6, MarkupYellow}}
+	if a := moves[1].Arrows(); !reflect.DeepEqual(a, arrows) {
+		t.Fatalf("expected arrows %v but got %v", arrows, a)
+	}
+	if err := g.SetArrows(1, Arrow{E2, E4, MarkupYellow}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetHighlightedSquares(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetArrows(2, Arrow{B8, C6, MarkupGreen}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetArrows(3); err == nil {
+		t.Fatal("expected an error setting arrows out of range")
+	}
+	expected := "1.e4 {[%cal Ye2e4] Center.} 1...e5 {[%cal Gb8c6]} *"
+	if s := g.String(); !strings.HasSuffix(s, expected) {
+		t.Fatalf("expected pgn ending with %s but got %s", expected, s)
+	}
+}
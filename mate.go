@@ -0,0 +1,319 @@
+package chess
+
+import "strconv"
+
+// A MatePattern is a named checkmate pattern.
+type MatePattern int
+
+const (
+	// BackRankMate is a mate by a rook or queen on the king's back rank
+	// where the king is hemmed in by its own pieces.
+	BackRankMate MatePattern = iota
+	// SmotheredMate is a mate by a knight where every square around the
+	// king is occupied by its own pieces.
+	SmotheredMate
+	// ArabianMate is a mate of a king in the corner by an adjacent rook
+	// protected by a knight.
+	ArabianMate
+	// AnastasiaMate is a mate of a king on an edge file by a rook or
+	// queen on the file with a knight covering the escape squares and the
+	// king blocked by its own piece.
+	AnastasiaMate
+	// HookMate is a mate by an adjacent rook protected by a knight which
+	// is protected by a pawn.
+	HookMate
+	// BodenMate is a mate by two bishops on crossing diagonals.
+	BodenMate
+	// DoubleBishopMate is a mate by two bishops on parallel diagonals.
+	DoubleBishopMate
+	// DovetailMate is a mate by a protected queen diagonally adjacent to
+	// the king where the two squares behind the king are occupied by its
+	// own pieces.
+	DovetailMate
+	// EpauletteMate is a mate by a queen of a king on an edge whose
+	// neighbors along the edge are its own pieces.
+	EpauletteMate
+)
+
+var matePatternNames = []string{
+	"BackRankMate",
+	"SmotheredMate",
+	"ArabianMate",
+	"AnastasiaMate",
+	"HookMate",
+	"BodenMate",
+	"DoubleBishopMate",
+	"DovetailMate",
+	"EpauletteMate",
+}
+
+func (p MatePattern) String() string {
+	if p < 0 || int(p) >= len(matePatternNames) {
+		return "MatePattern(" + strconv.Itoa(int(p)) + ")"
+	}
+	return matePatternNames[p]
+}
+
+// MatePatterns returns the named mate patterns of a checkmated position in
+// the order of the MatePattern constants.  More than one pattern may
+// match and none are returned for positions that aren't checkmate or
+// checkmates that don't match a known pattern.  To tag a mating line use
+// the final position of the line.
+func (pos *Position) MatePatterns() []MatePattern {
+	patterns := []MatePattern{}
+	if pos.Status() != Checkmate {
+		return patterns
+	}
+	m := newMateScan(pos)
+	checks := []struct {
+		pattern MatePattern
+		match   func() bool
+	}{
+		{BackRankMate, m.backRank},
+		{SmotheredMate, m.smothered},
+		{ArabianMate, m.arabian},
+		{AnastasiaMate, m.anastasia},
+		{HookMate, m.hook},
+		{BodenMate, func() bool { return m.bishops(true) }},
+		{DoubleBishopMate, func() bool { return m.bishops(false) }},
+		{DovetailMate, m.dovetail},
+		{EpauletteMate, m.epaulette},
+	}
+	for _, check := range checks {
+		if check.match() {
+			patterns = append(patterns, check.pattern)
+		}
+	}
+	return patterns
+}
+
+// mateScan holds the details of a checkmate shared by the pattern checks.
+type mateScan struct {
+	b        *Board
+	occ      bitboard
+	king     Square
+	mated    Color
+	checkers []Square
+}
+
+func newMateScan(pos *Position) *mateScan {
+	m := &mateScan{b: pos.board, occ: ^pos.board.emptySqs, mated: pos.turn}
+	m.king = pos.board.whiteKingSq
+	if m.mated == Black {
+		m.king = pos.board.blackKingSq
+	}
+	m.checkers = m.attackers(m.king, m.mated.Other())
+	return m
+}
+
+// attackers returns the squares of the color's pieces that attack sq.
+func (m *mateScan) attackers(sq Square, c Color) []Square {
+	return attackersOf(m.b, sq, c, m.occ)
+}
+
+// defended returns true if a piece of type pt (or any type if pt is
+// NoPieceType) of the mating side attacks sq.
+func (m *mateScan) defended(sq Square, pt PieceType) bool {
+	for _, a := range m.attackers(sq, m.mated.Other()) {
+		if pt == NoPieceType || m.b.Piece(a).Type() == pt {
+			return true
+		}
+	}
+	return false
+}
+
+// checker returns the type and square of the only checking piece.
+func (m *mateScan) checker() (PieceType, Square) {
+	if len(m.checkers) != 1 {
+		return NoPieceType, NoSquare
+	}
+	return m.b.Piece(m.checkers[0]).Type(), m.checkers[0]
+}
+
+// own returns true if sq is occupied by a piece of the mated side.
+func (m *mateScan) own(sq Square) bool {
+	p := m.b.Piece(sq)
+	return p != NoPiece && p.Color() == m.mated
+}
+
+// forward is the direction away from the mated side's back rank.
+func (m *mateScan) forward() int {
+	if m.mated == Black {
+		return -1
+	}
+	return 1
+}
+
+func (m *mateScan) backRank() bool {
+	pt, sq := m.checker()
+	backRank := Rank1
+	if m.mated == Black {
+		backRank = Rank8
+	}
+	if (pt != Rook && pt != Queen) || m.king.Rank() != backRank || sq.Rank() != backRank {
+		return false
+	}
+	for df := -1; df <= 1; df++ {
+		if s, ok := offsetSquare(m.king, df, m.forward()); ok && !m.own(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mateScan) smothered() bool {
+	if pt, _ := m.checker(); pt != Knight {
+		return false
+	}
+	for _, s := range kingNeighbors(m.king) {
+		if !m.own(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mateScan) arabian() bool {
+	f, r := m.king.File(), m.king.Rank()
+	if (f != FileA && f != FileH) || (r != Rank1 && r != Rank8) {
+		return false
+	}
+	pt, sq := m.checker()
+	return pt == Rook && squareDistance(m.king, sq) == 1 && m.defended(sq, Knight)
+}
+
+func (m *mateScan) anastasia() bool {
+	f := m.king.File()
+	if f != FileA && f != FileH {
+		return false
+	}
+	pt, sq := m.checker()
+	if (pt != Rook && pt != Queen) || sq.File() != f {
+		return false
+	}
+	inward := 1
+	if f == FileH {
+		inward = -1
+	}
+	blocker, ok := offsetSquare(m.king, inward, 0)
+	if !ok || !m.own(blocker) {
+		return false
+	}
+	for _, s := range kingNeighbors(m.king) {
+		if s.File() != f && !m.own(s) && m.defended(s, Knight) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mateScan) hook() bool {
+	pt, sq := m.checker()
+	if pt != Rook || squareDistance(m.king, sq) != 1 {
+		return false
+	}
+	for _, n := range m.attackers(sq, m.mated.Other()) {
+		if m.b.Piece(n).Type() == Knight && m.defended(n, Pawn) {
+			return true
+		}
+	}
+	return false
+}
+
+// bishops returns true if a bishop mates with the help of another bishop
+// covering the king's escape squares along a crossing diagonal if
+// crossing is true and along a parallel diagonal otherwise.
+func (m *mateScan) bishops(crossing bool) bool {
+	pt, sq := m.checker()
+	if pt != Bishop {
+		return false
+	}
+	checkDir := diagonalDirection(sq, m.king)
+	for _, s := range kingNeighbors(m.king) {
+		if m.own(s) {
+			continue
+		}
+		for _, a := range m.attackers(s, m.mated.Other()) {
+			if a == sq || m.b.Piece(a).Type() != Bishop {
+				continue
+			}
+			if (diagonalDirection(a, s) != checkDir) == crossing {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *mateScan) dovetail() bool {
+	pt, sq := m.checker()
+	if pt != Queen || squareDistance(m.king, sq) != 1 || sq.File() == m.king.File() || sq.Rank() == m.king.Rank() {
+		return false
+	}
+	df := int(sq.File()) - int(m.king.File())
+	dr := int(sq.Rank()) - int(m.king.Rank())
+	s1, ok1 := offsetSquare(m.king, -df, 0)
+	s2, ok2 := offsetSquare(m.king, 0, -dr)
+	return ok1 && ok2 && m.own(s1) && m.own(s2) && m.defended(sq, NoPieceType)
+}
+
+func (m *mateScan) epaulette() bool {
+	pt, sq := m.checker()
+	if pt != Queen {
+		return false
+	}
+	f, r := int(m.king.File()), int(m.king.Rank())
+	switch {
+	case (r == 0 || r == 7) && int(sq.File()) == f && abs(int(sq.Rank())-r) == 2:
+		s1, ok1 := offsetSquare(m.king, -1, 0)
+		s2, ok2 := offsetSquare(m.king, 1, 0)
+		return ok1 && ok2 && m.own(s1) && m.own(s2)
+	case (f == 0 || f == 7) && int(sq.Rank()) == r && abs(int(sq.File())-f) == 2:
+		s1, ok1 := offsetSquare(m.king, 0, -1)
+		s2, ok2 := offsetSquare(m.king, 0, 1)
+		return ok1 && ok2 && m.own(s1) && m.own(s2)
+	}
+	return false
+}
+
+// offsetSquare returns the square df files and dr ranks from sq and false
+// if it is off the board.
+func offsetSquare(sq Square, df, dr int) (Square, bool) {
+	f := int(sq.File()) + df
+	r := int(sq.Rank()) + dr
+	if f < 0 || f > 7 || r < 0 || r > 7 {
+		return NoSquare, false
+	}
+	return getSquare(File(f), Rank(r)), true
+}
+
+// kingNeighbors returns the squares adjacent to sq.
+func kingNeighbors(sq Square) []Square {
+	squares := []Square{}
+	for df := -1; df <= 1; df++ {
+		for dr := -1; dr <= 1; dr++ {
+			if s, ok := offsetSquare(sq, df, dr); ok && (df != 0 || dr != 0) {
+				squares = append(squares, s)
+			}
+		}
+	}
+	return squares
+}
+
+// squareDistance returns the number of king moves between the squares.
+func squareDistance(s1, s2 Square) int {
+	df := abs(int(s1.File()) - int(s2.File()))
+	dr := abs(int(s1.Rank()) - int(s2.Rank()))
+	if df > dr {
+		return df
+	}
+	return dr
+}
+
+// diagonalDirection returns true if the diagonal from s1 to s2 runs in the
+// direction of A1-H8 and false if it runs in the direction of A8-H1.
+func diagonalDirection(s1, s2 Square) bool {
+	df := int(s2.File()) - int(s1.File())
+	dr := int(s2.Rank()) - int(s1.Rank())
+	return (df > 0) == (dr > 0)
+}
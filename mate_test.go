@@ -0,0 +1,34 @@
+package chess
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatePatterns(t *testing.T) {
+	tests := []struct {
+		fen      string
+		patterns []MatePattern
+	}{
+		{"R5k1/5ppp/8/8/8/8/8/6K1 b - - 0 1", []MatePattern{BackRankMate}},
+		{"6rk/5Npp/8/8/8/8/8/6K1 b - - 0 1", []MatePattern{SmotheredMate}},
+		{"7k/7R/5N2/8/8/8/8/6K1 b - - 0 1", []MatePattern{ArabianMate}},
+		{"8/4N1pk/8/7R/8/8/8/6K1 b - - 0 1", []MatePattern{AnastasiaMate}},
+		{"4Rk2/5pp1/5N2/4P3/8/8/8/6K1 b - - 0 1", []MatePattern{HookMate}},
+		{"2kr4/3p4/B7/8/5B2/8/8/7K b - - 0 1", []MatePattern{BodenMate}},
+		{"6bk/8/8/8/8/3B4/1B6/6K1 b - - 0 1", []MatePattern{DoubleBishopMate}},
+		{"8/4pp2/4kp2/3Q4/2P5/8/8/7K b - - 0 1", []MatePattern{DovetailMate}},
+		{"3rkr2/8/4Q3/8/8/8/8/7K b - - 0 1", []MatePattern{EpauletteMate}},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", []MatePattern{}},
+	}
+	for _, test := range tests {
+		fen, err := FEN(test.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pos := NewGame(fen).Position()
+		if patterns := pos.MatePatterns(); !reflect.DeepEqual(patterns, test.patterns) {
+			t.Fatalf("expected %v for %s but got %v", test.patterns, test.fen, patterns)
+		}
+	}
+}
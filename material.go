@@ -0,0 +1,70 @@
+package chess
+
+// A CaptureRecord is a capture made in a game.
+type CaptureRecord struct {
+	// Ply is the ply of the capturing move starting at one.
+	Ply int
+	// Piece is the captured piece.
+	Piece Piece
+	// By is the capturing piece.
+	By Piece
+	// Square is the square the captured piece was on which differs from
+	// the capturing move's destination for en passant.
+	Square Square
+}
+
+// MaterialHistory is the material of a game over time.
+type MaterialHistory struct {
+	// Balance is White's material minus Black's in pawns after each
+	// ply.  Balance[0] is the balance of the starting position.
+	Balance []int
+	// Captures are the game's captures in order.
+	Captures []CaptureRecord
+}
+
+// CapturedPieces returns the pieces of the color that were captured in
+// order, for example to fill a captured piece tray.
+func (h *MaterialHistory) CapturedPieces(c Color) []Piece {
+	pieces := []Piece{}
+	for _, capture := range h.Captures {
+		if capture.Piece.Color() == c {
+			pieces = append(pieces, capture.Piece)
+		}
+	}
+	return pieces
+}
+
+// MaterialHistory returns the game's material balance after each ply and
+// its captures.
+func (g *Game) MaterialHistory() *MaterialHistory {
+	h := &MaterialHistory{
+		Balance:  make([]int, len(g.positions)),
+		Captures: []CaptureRecord{},
+	}
+	for i, pos := range g.positions {
+		h.Balance[i] = materialBalance(pos.board)
+	}
+	for i, m := range g.moves {
+		pos := g.positions[i]
+		sq := m.s2
+		if m.HasTag(EnPassant) {
+			sq = getSquare(m.s2.File(), m.s1.Rank())
+		} else if !m.HasTag(Capture) {
+			continue
+		}
+		h.Captures = append(h.Captures, CaptureRecord{
+			Ply:    i + 1,
+			Piece:  pos.board.Piece(sq),
+			By:     pos.board.Piece(m.s1),
+			Square: sq,
+		})
+	}
+	return h
+}
+
+// materialBalance returns White's material minus Black's in pawns using
+// DefaultEvalParams.
+func materialBalance(b *Board) int {
+	p := DefaultEvalParams
+	return (p.Material(b, White) - p.Material(b, Black)) / 100
+}
@@ -0,0 +1,51 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaterialHistory(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("1. e4 d5 2. exd5 Qxd5 3. Nc3 Qe5+ 4. Be2 Qxe2+ 5. Qxe2 *"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewGame(pgn).MaterialHistory()
+	expected := []int{0, 0, 0, 1, 0, 0, 0, 0, -3, 6}
+	if len(h.Balance) != len(expected) {
+		t.Fatalf("expected %d balances but got %v", len(expected), h.Balance)
+	}
+	for i, b := range expected {
+		if h.Balance[i] != b {
+			t.Fatalf("expected balance %v but got %v", expected, h.Balance)
+		}
+	}
+	captures := []CaptureRecord{
+		{Ply: 3, Piece: BlackPawn, By: WhitePawn, Square: D5},
+		{Ply: 4, Piece: WhitePawn, By: BlackQueen, Square: D5},
+		{Ply: 8, Piece: WhiteBishop, By: BlackQueen, Square: E2},
+		{Ply: 9, Piece: BlackQueen, By: WhiteQueen, Square: E2},
+	}
+	if len(h.Captures) != len(captures) {
+		t.Fatalf("expected captures %v but got %v", captures, h.Captures)
+	}
+	for i, c := range captures {
+		if h.Captures[i] != c {
+			t.Fatalf("expected capture %v but got %v", c, h.Captures[i])
+		}
+	}
+	if pieces := h.CapturedPieces(White); len(pieces) != 2 || pieces[0] != WhitePawn || pieces[1] != WhiteBishop {
+		t.Fatalf("unexpected captured white pieces %v", pieces)
+	}
+}
+
+func TestMaterialHistoryEnPassant(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("1. e4 a6 2. e5 d5 3. exd6 *"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewGame(pgn).MaterialHistory()
+	if len(h.Captures) != 1 || h.Captures[0].Square != D5 || h.Captures[0].Piece != BlackPawn {
+		t.Fatalf("expected en passant capture on d5 but got %v", h.Captures)
+	}
+}
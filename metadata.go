@@ -0,0 +1,71 @@
+package chess
+
+import "sort"
+
+// Metadata returns a function that sets the game's metadata to the given
+// key value pairs.  Metadata is application data such as database IDs or
+// analysis status.  Unlike tag pairs it isn't included in the game's PGN.
+// The returned function is designed to be used in the NewGame constructor.
+func Metadata(m map[string]string) func(*Game) {
+	return func(g *Game) {
+		g.metadata = map[string]string{}
+		for k, v := range m {
+			g.metadata[k] = v
+		}
+	}
+}
+
+// Metadata returns a copy of the game's metadata.
+func (g *Game) Metadata() map[string]string {
+	m := map[string]string{}
+	for k, v := range g.metadata {
+		m[k] = v
+	}
+	return m
+}
+
+// SetMetadata adds or updates the metadata value for the given key and
+// returns true if the value is overwritten.
+func (g *Game) SetMetadata(k, v string) bool {
+	if g.metadata == nil {
+		g.metadata = map[string]string{}
+	}
+	_, ok := g.metadata[k]
+	g.metadata[k] = v
+	return ok
+}
+
+// GetMetadata returns the metadata value for the given key and whether
+// it is present.
+func (g *Game) GetMetadata(k string) (string, bool) {
+	v, ok := g.metadata[k]
+	return v, ok
+}
+
+// RemoveMetadata removes the metadata value for the given key and
+// returns true if a value was removed.
+func (g *Game) RemoveMetadata(k string) bool {
+	_, ok := g.metadata[k]
+	delete(g.metadata, k)
+	return ok
+}
+
+// MetadataTagPairs returns the game's metadata as tag pairs sorted by key
+// with each key prefixed by the given prefix.  They can be used to export
+// metadata as custom tags:
+//
+//	for _, tp := range g.MetadataTagPairs("X") {
+//		g.AddTagPair(tp.Key, tp.Value)
+//	}
+func (g *Game) MetadataTagPairs(prefix string) []*TagPair {
+	keys := []string{}
+	for k := range g.metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tagPairs := []*TagPair{}
+	for _, k := range keys {
+		tagPairs = append(tagPairs, &TagPair{Key: prefix + k, Value: g.metadata[k]})
+	}
+	return tagPairs
+}
@@ -0,0 +1,34 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	g := NewGame(Metadata(map[string]string{"id": "42"}))
+	if g.SetMetadata("status", "queued") {
+		t.Fatal("expected new metadata not to overwrite a value")
+	}
+	if !g.SetMetadata("status", "analyzed") {
+		t.Fatal("expected existing metadata to be overwritten")
+	}
+	if v, ok := g.GetMetadata("status"); !ok || v != "analyzed" {
+		t.Fatalf("expected status analyzed but got %s", v)
+	}
+	if strings.Contains(g.String(), "analyzed") {
+		t.Fatal("expected metadata to be excluded from the pgn")
+	}
+	tagPairs := g.MetadataTagPairs("X")
+	if len(tagPairs) != 2 || tagPairs[0].Key != "Xid" || tagPairs[1].Key != "Xstatus" {
+		t.Fatalf("unexpected metadata tag pairs %v", tagPairs)
+	}
+	clone := g.Clone()
+	clone.SetMetadata("id", "43")
+	if v, _ := g.GetMetadata("id"); v != "42" {
+		t.Fatal("expected clone metadata to be independent")
+	}
+	if !g.RemoveMetadata("id") || g.RemoveMetadata("id") {
+		t.Fatal("expected metadata to be removed once")
+	}
+}
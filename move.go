@@ -25,11 +25,35 @@ type Move struct {
 	s2    Square
 	promo PieceType
 	tags  MoveTag
+	// annotations from PGN movetext
+	preComments []Comment
+	comments    []Comment
+	nags        []string
+	variations  [][]*Move
+	// annotator of the variation this move begins
+	annotator string
+	// layout is the lexical form of a move decoded with ExactPGN
+	layout *moveLayout
+}
+
+// NullMove returns a null move, which passes the turn to the other side
+// without moving a piece.  Annotators and engines use null moves to show
+// the threat in a position.
+func NullMove() *Move {
+	return &Move{s1: NoSquare, s2: NoSquare}
+}
+
+// IsNull returns true if the move is a null move.
+func (m *Move) IsNull() bool {
+	return m.s1 == NoSquare && m.s2 == NoSquare
 }
 
 // String returns a string useful for debugging.  String doesn't return
-// algebraic notation.
+// algebraic notation.  A null move is returned as 0000 as in UCI.
 func (m *Move) String() string {
+	if m.IsNull() {
+		return "0000"
+	}
 	return m.s1.String() + m.s2.String() + m.promo.String()
 }
 
@@ -57,6 +81,11 @@ func (m *Move) addTag(tag MoveTag) {
 	m.tags = m.tags | tag
 }
 
+// copy returns a copy of the move without annotations.
+func (m *Move) copy() *Move {
+	return &Move{s1: m.s1, s2: m.s2, promo: m.promo, tags: m.tags}
+}
+
 type moveSlice []*Move
 
 func (a moveSlice) find(m *Move) *Move {
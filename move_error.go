@@ -0,0 +1,205 @@
+package chess
+
+import "fmt"
+
+// MoveErrorReason is the reason a move was rejected.
+type MoveErrorReason int
+
+const (
+	// IllegalPieceMove indicates that the piece can't move that way, for
+	// example a knight moving like a bishop, a capture of a piece of the
+	// same color or a missing or misplaced promotion.
+	IllegalPieceMove MoveErrorReason = iota
+	// NoPieceOnSquare indicates that there is no piece on the move's
+	// origin square.
+	NoPieceOnSquare
+	// WrongTurn indicates that the piece belongs to the color not to move.
+	WrongTurn
+	// PathBlocked indicates that a piece stands between the origin and
+	// destination squares.
+	PathBlocked
+	// LeavesKingInCheck indicates that the move would leave or put the
+	// king in check, including castling out of or through check.
+	LeavesKingInCheck
+	// GameOver indicates that the game has already been completed.
+	GameOver
+	// WrongPiece indicates that the notation names a different piece
+	// than the one on the origin square.
+	WrongPiece
+)
+
+// String implements the fmt.Stringer interface.
+func (r MoveErrorReason) String() string {
+	switch r {
+	case NoPieceOnSquare:
+		return "no piece on the origin square"
+	case WrongTurn:
+		return "piece belongs to the color not to move"
+	case PathBlocked:
+		return "path is blocked"
+	case LeavesKingInCheck:
+		return "king would be in check"
+	case GameOver:
+		return "game is over"
+	case WrongPiece:
+		return "notation names a different piece"
+	}
+	return "piece can't move that way"
+}
+
+// MoveError is the error returned by Game.Move when a move is rejected.
+type MoveError struct {
+	Move   *Move
+	Reason MoveErrorReason
+}
+
+// Error implements the error interface.
+func (e *MoveError) Error() string {
+	return fmt.Sprintf("chess: invalid move %s: %s", e.Move, e.Reason)
+}
+
+// illegalReason returns the reason the move isn't one of the position's
+// valid moves.
+func (pos *Position) illegalReason(m *Move) MoveErrorReason {
+	b := pos.board
+	p := b.Piece(m.s1)
+	if p == NoPiece {
+		return NoPieceOnSquare
+	}
+	if p.Color() != pos.turn {
+		return WrongTurn
+	}
+	df := int(m.s2.File()) - int(m.s1.File())
+	dr := int(m.s2.Rank()) - int(m.s1.Rank())
+	adf, adr := abs(df), abs(dr)
+	target := b.Piece(m.s2)
+	castling := p.Type() == King && adf == 2
+	if target != NoPiece && target.Color() == p.Color() && !castling {
+		return IllegalPieceMove
+	}
+	lastRank := (p.Color() == White && m.s2.Rank() == Rank8) || (p.Color() == Black && m.s2.Rank() == Rank1)
+	if p.Type() != Pawn || !lastRank {
+		if m.promo != NoPieceType {
+			return IllegalPieceMove
+		}
+	} else if !m.promo.promotableTo() {
+		return IllegalPieceMove
+	}
+	switch p.Type() {
+	case Knight:
+		if !(adf == 1 && adr == 2) && !(adf == 2 && adr == 1) {
+			return IllegalPieceMove
+		}
+		return LeavesKingInCheck
+	case King:
+		if adf <= 1 && adr <= 1 {
+			return LeavesKingInCheck
+		}
+		return pos.castleReason(p.Color(), m, df, dr)
+	case Pawn:
+		forward := 1
+		if p.Color() == Black {
+			forward = -1
+		}
+		switch {
+		case df == 0 && dr == forward:
+			if target != NoPiece {
+				return PathBlocked
+			}
+		case df == 0 && dr == 2*forward && m.s1.Rank() == pawnStartRank(p.Color()):
+			if target != NoPiece || b.isOccupied(Square(int(m.s1)+8*forward)) {
+				return PathBlocked
+			}
+		case adf == 1 && dr == forward:
+			if target == NoPiece && m.s2 != pos.enPassantSquare {
+				return IllegalPieceMove
+			}
+		default:
+			return IllegalPieceMove
+		}
+		return LeavesKingInCheck
+	}
+	straight := df == 0 || dr == 0
+	diagonal := adf == adr
+	switch {
+	case p.Type() == Rook && !straight,
+		p.Type() == Bishop && !diagonal,
+		p.Type() == Queen && !straight && !diagonal:
+		return IllegalPieceMove
+	}
+	if pos.blocked(m.s1, m.s2) {
+		return PathBlocked
+	}
+	return LeavesKingInCheck
+}
+
+func (pos *Position) castleReason(c Color, m *Move, df, dr int) MoveErrorReason {
+	backRank := Rank1
+	if c == Black {
+		backRank = Rank8
+	}
+	if m.s1.Rank() != backRank || dr != 0 {
+		return IllegalPieceMove
+	}
+	side := KingSide
+	if df < 0 {
+		side = QueenSide
+	}
+	rook := pos.castleRook(c, side)
+	kingTo, _ := castleTargets(backRank, side)
+	if rook == NoSquare || (m.s2 != kingTo && m.s2 != rook) {
+		return IllegalPieceMove
+	}
+	return pos.castlePathReason(m.s1, rook, side)
+}
+
+// castlePathReason returns why castling with the king and rook on the
+// side isn't legal once the rook is known to have the castling right.
+func (pos *Position) castlePathReason(king, rook Square, side Side) MoveErrorReason {
+	kingTo, rookTo := castleTargets(king.Rank(), side)
+	for _, sq := range append(squaresBetween(king, kingTo), squaresBetween(rook, rookTo)...) {
+		if sq != king && sq != rook && pos.board.isOccupied(sq) {
+			return PathBlocked
+		}
+	}
+	return LeavesKingInCheck
+}
+
+// blocked returns true if a piece is on a square strictly between the
+// squares which must share a rank, file or diagonal.
+func (pos *Position) blocked(s1, s2 Square) bool {
+	df := sign(int(s2.File()) - int(s1.File()))
+	dr := sign(int(s2.Rank()) - int(s1.Rank()))
+	sq := Square(int(s1) + df + 8*dr)
+	for sq != s2 {
+		if pos.board.isOccupied(sq) {
+			return true
+		}
+		sq = Square(int(sq) + df + 8*dr)
+	}
+	return false
+}
+
+func pawnStartRank(c Color) Rank {
+	if c == White {
+		return Rank2
+	}
+	return Rank7
+}
+
+func sign(i int) int {
+	switch {
+	case i > 0:
+		return 1
+	case i < 0:
+		return -1
+	}
+	return 0
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
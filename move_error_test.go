@@ -0,0 +1,60 @@
+package chess
+
+import (
+	"testing"
+)
+
+func TestMoveErrorReasons(t *testing.T) {
+	tests := []struct {
+		fen    string
+		move   *Move
+		reason MoveErrorReason
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &Move{s1: E3, s2: E4}, NoPieceOnSquare},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &Move{s1: E7, s2: E5}, WrongTurn},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &Move{s1: G1, s2: G3}, IllegalPieceMove},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &Move{s1: F1, s2: C4}, PathBlocked},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &Move{s1: E1, s2: G1}, PathBlocked},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &Move{s1: E2, s2: E5}, IllegalPieceMove},
+		{"rnbqkbnr/pppppppp/8/8/8/4n3/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &Move{s1: E2, s2: E4}, PathBlocked},
+		{"4k3/8/8/8/8/8/4r3/4K2R w K - 0 1", &Move{s1: H1, s2: H8}, LeavesKingInCheck},
+		{"4k3/8/8/8/8/8/5r2/4K2R w K - 0 1", &Move{s1: E1, s2: G1}, LeavesKingInCheck},
+		{"4k3/8/8/8/8/8/8/4K2R w - - 0 1", &Move{s1: E1, s2: G1}, IllegalPieceMove},
+		{"4k3/P7/8/8/8/8/8/4K3 w - - 0 1", &Move{s1: A7, s2: A8}, IllegalPieceMove},
+		{"4k3/8/8/8/8/8/8/R3K3 w - - 0 1", &Move{s1: A1, s2: E1}, IllegalPieceMove},
+	}
+	for i, test := range tests {
+		opt, err := FEN(test.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := NewGame(opt)
+		err = g.Move(test.move)
+		moveErr, ok := err.(*MoveError)
+		if !ok {
+			t.Fatalf("test %d expected a *MoveError but got %v", i, err)
+		}
+		if moveErr.Reason != test.reason {
+			t.Fatalf("test %d expected reason %s but got %s", i, test.reason, moveErr.Reason)
+		}
+	}
+}
+
+func TestMoveAfterGameOver(t *testing.T) {
+	g := NewGame()
+	g.Resign(White)
+	err := g.Move(&Move{s1: E2, s2: E4})
+	if moveErr, ok := err.(*MoveError); !ok || moveErr.Reason != GameOver {
+		t.Fatalf("expected game over error but got %v", err)
+	}
+}
+
+func TestMoveNil(t *testing.T) {
+	g := NewGame()
+	if err := g.Move(nil); err == nil {
+		t.Fatal("expected an error moving a nil move")
+	}
+	if len(g.Moves()) != 0 {
+		t.Fatal("expected the game to be unchanged")
+	}
+}
@@ -1,6 +1,7 @@
 package chess
 
 import (
+	"errors"
 	"log"
 	"testing"
 )
@@ -300,3 +301,40 @@ func moveIsValid(pos *Position, m *Move, useTags bool) bool {
 	}
 	return false
 }
+
+func TestNullMove(t *testing.T) {
+	pos := unsafeFEN("rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2")
+	next := pos.Update(NullMove())
+	if s := next.String(); s != "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 1 2" {
+		t.Fatalf("expected the turn to pass and the en passant square to clear but got %s", s)
+	}
+	if s := next.Update(NullMove()).String(); s != "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 2 3" {
+		t.Fatalf("expected the move count to advance but got %s", s)
+	}
+	if m := NullMove(); !m.IsNull() || m.String() != "0000" || (&Move{}).IsNull() {
+		t.Fatalf("expected only the null move to be null")
+	}
+
+	g := NewGame()
+	for _, s := range []string{"e4", "--", "d4", "Z0"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s := g.Position().String(); s != "rnbqkbnr/pppppppp/8/8/3PP3/8/PPP2PPP/RNBQKBNR w KQkq - 1 3" {
+		t.Fatalf("expected black's null moves to pass but got %s", s)
+	}
+	if !g.Moves()[1].IsNull() {
+		t.Fatalf("expected the second move to be null")
+	}
+	g = NewGame()
+	for _, s := range []string{"e4", "f5", "Qh5+"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var moveErr *MoveError
+	if err := g.Move(NullMove()); !errors.As(err, &moveErr) || moveErr.Reason != LeavesKingInCheck {
+		t.Fatalf("expected a null move in check to be rejected but got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package chess
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MoveLogEventType is the type of an event in a move log.
+type MoveLogEventType string
+
+const (
+	// MoveLogStart starts a game from the event's FEN.  It may only be the
+	// first event and games without it start from the standard position.
+	MoveLogStart MoveLogEventType = "start"
+	// MoveLogMove is a move by the event's color.
+	MoveLogMove MoveLogEventType = "move"
+	// MoveLogClock is the time left on the event's color's clock after
+	// its most recent move.
+	MoveLogClock MoveLogEventType = "clock"
+	// MoveLogOffer is a draw offer by the event's color.
+	MoveLogOffer MoveLogEventType = "offer"
+	// MoveLogAccept is the acceptance of the opponent's draw offer by the
+	// event's color.
+	MoveLogAccept MoveLogEventType = "accept"
+	// MoveLogResign is a resignation by the event's color.
+	MoveLogResign MoveLogEventType = "resign"
+)
+
+// MoveLogEvent is an event in a move log.  Move is set for move events,
+// Clock for clock events and FEN for start events.
+type MoveLogEvent struct {
+	Type  MoveLogEventType
+	Time  time.Time
+	Color Color
+	Move  *Move
+	Clock time.Duration
+	FEN   string
+}
+
+// String returns the event's line in a move log without the newline, such
+// as "2024-05-01T12:00:00Z move w e2e4".  Moves are written in UCI
+// notation and clocks in the format of the [%clk] command.
+func (e MoveLogEvent) String() string {
+	s := e.Time.UTC().Format(time.RFC3339Nano) + " " + string(e.Type) + " " + e.Color.String()
+	switch e.Type {
+	case MoveLogStart:
+		s += " " + e.FEN
+	case MoveLogMove:
+		s += " " + UCINotation{}.Encode(nil, e.Move)
+	case MoveLogClock:
+		s += " " + formatClock(e.Clock)
+	}
+	return s
+}
+
+// MoveLog is an append-only log of a game's events with a line for each
+// event, written as the events happen so that a hosted game can be
+// recovered after a crash with ReplayMoveLog and audited later.  MoveLog
+// is safe for concurrent use.
+type MoveLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewMoveLog returns a move log that appends to the writer, such as a file
+// opened with os.O_APPEND.  Syncing the file after each event is left to
+// the caller.
+func NewMoveLog(w io.Writer) *MoveLog {
+	return &MoveLog{w: w}
+}
+
+// Write appends the event's line to the log in a single write so that a
+// crash leaves at most the last line incomplete.
+func (l *MoveLog) Write(e MoveLogEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := io.WriteString(l.w, e.String()+"\n")
+	return err
+}
+
+// ReadMoveLog reads the events of a move log.  An incomplete last line
+// without a newline, as left by a crash while it was written, is ignored.
+// An error is returned if a line is malformed.
+func ReadMoveLog(r io.Reader) ([]MoveLogEvent, error) {
+	br := bufio.NewReader(r)
+	events := []MoveLogEvent{}
+	for n := 1; ; n++ {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		e, err := parseMoveLogEvent(line)
+		if err != nil {
+			return nil, fmt.Errorf("chess: move log line %d: %w", n, err)
+		}
+		events = append(events, e)
+	}
+}
+
+func parseMoveLogEvent(line string) (MoveLogEvent, error) {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 3 {
+		return MoveLogEvent{}, fmt.Errorf("invalid event %q", line)
+	}
+	t, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return MoveLogEvent{}, err
+	}
+	e := MoveLogEvent{Type: MoveLogEventType(fields[1]), Time: t}
+	switch fields[2] {
+	case "w":
+		e.Color = White
+	case "b":
+		e.Color = Black
+	case "-":
+	default:
+		return MoveLogEvent{}, fmt.Errorf("invalid color %q", fields[2])
+	}
+	arg := ""
+	if len(fields) == 4 {
+		arg = fields[3]
+	}
+	switch e.Type {
+	case MoveLogStart:
+		e.FEN = arg
+	case MoveLogMove:
+		if e.Move, err = (UCINotation{}).Decode(nil, arg); err != nil {
+			return MoveLogEvent{}, err
+		}
+	case MoveLogClock:
+		if e.Clock, err = ParseClock(arg); err != nil {
+			return MoveLogEvent{}, err
+		}
+	case MoveLogOffer, MoveLogAccept, MoveLogResign:
+	default:
+		return MoveLogEvent{}, fmt.Errorf("unknown event type %q", e.Type)
+	}
+	return e, nil
+}
+
+// ReplayMoveLog returns the game the events lead to.  Clock events set the
+// [%clk] command of the color's most recent move and an accepted draw offer
+// draws the game.  A draw offer lapses when the offering side's opponent
+// moves.  An error is returned if an event can't be applied, such as an
+// invalid move or accepting a draw that wasn't offered.
+func ReplayMoveLog(events []MoveLogEvent) (*Game, error) {
+	g := NewGame()
+	offer := NoColor
+	for i, e := range events {
+		var err error
+		switch e.Type {
+		case MoveLogStart:
+			if i != 0 {
+				return nil, fmt.Errorf("chess: move log start event %d isn't the first event", i+1)
+			}
+			fen, fenErr := FEN(e.FEN)
+			if fenErr != nil {
+				return nil, fenErr
+			}
+			g = NewGame(fen)
+		case MoveLogMove:
+			if g.Position().Turn() != e.Color {
+				return nil, fmt.Errorf("chess: move log move %s by %s out of turn", e.Move, e.Color.Name())
+			}
+			err = g.Move(e.Move)
+			if offer == e.Color.Other() {
+				offer = NoColor
+			}
+		case MoveLogClock:
+			for ply := len(g.moves); ply > 0; ply-- {
+				if g.positions[ply-1].Turn() == e.Color {
+					err = g.SetClock(ply, e.Clock)
+					break
+				}
+			}
+		case MoveLogOffer:
+			offer = e.Color
+		case MoveLogAccept:
+			if offer != e.Color.Other() {
+				return nil, fmt.Errorf("chess: move log draw accepted by %s without an offer", e.Color.Name())
+			}
+			err = g.Draw(DrawOffer)
+		case MoveLogResign:
+			g.Resign(e.Color)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,93 @@
+package chess
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMoveLog(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pos := StartingPosition()
+	e4 := moveFromUCI(t, pos, "e2e4")
+	e5 := moveFromUCI(t, pos.Update(e4), "e7e5")
+	buf := &bytes.Buffer{}
+	log := NewMoveLog(buf)
+	events := []MoveLogEvent{
+		{Type: MoveLogMove, Time: start, Color: White, Move: e4},
+		{Type: MoveLogClock, Time: start, Color: White, Clock: 179500 * time.Millisecond},
+		{Type: MoveLogOffer, Time: start.Add(time.Second), Color: White},
+		{Type: MoveLogMove, Time: start.Add(2 * time.Second), Color: Black, Move: e5},
+		{Type: MoveLogClock, Time: start.Add(2 * time.Second), Color: Black, Clock: 178 * time.Second},
+		{Type: MoveLogOffer, Time: start.Add(3 * time.Second), Color: Black},
+		{Type: MoveLogAccept, Time: start.Add(4 * time.Second), Color: White},
+	}
+	for _, e := range events {
+		if err := log.Write(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.HasPrefix(buf.String(), "2024-05-01T12:00:00Z move w e2e4\n2024-05-01T12:00:00Z clock w 0:02:59.5\n") {
+		t.Fatalf("unexpected log\n%s", buf)
+	}
+	// a crash while writing leaves an incomplete line
+	buf.WriteString("2024-05-01T12:00:05Z resi")
+	read, err := ReadMoveLog(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(events) {
+		t.Fatalf("expected %d events but got %d", len(events), len(read))
+	}
+	g, err := ReplayMoveLog(read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Outcome() != Draw || g.Method() != DrawOffer {
+		t.Fatalf("expected a draw by offer but got %s %s", g.Outcome(), g.Method())
+	}
+	if d, ok := g.Moves()[0].Clock(); !ok || d != 179500*time.Millisecond {
+		t.Fatalf("expected white's clock 2:59.5 but got %s", d)
+	}
+	if d, ok := g.Moves()[1].Clock(); !ok || d != 178*time.Second {
+		t.Fatalf("expected black's clock 2:58 but got %s", d)
+	}
+}
+
+func TestReplayMoveLog(t *testing.T) {
+	tests := []struct {
+		log     string
+		moves   int
+		outcome Outcome
+		valid   bool
+	}{
+		{"2024-05-01T12:00:00Z start - 7k/8/8/8/8/8/8/K6R w - - 0 1\n2024-05-01T12:00:01Z move w h1h7\n2024-05-01T12:00:02Z resign b\n", 1, WhiteWon, true},
+		{"2024-05-01T12:00:00Z move w e2e5\n", 0, NoOutcome, false},
+		{"2024-05-01T12:00:00Z move b e7e5\n", 0, NoOutcome, false},
+		{"2024-05-01T12:00:00Z offer w\n2024-05-01T12:00:01Z move w e2e4\n2024-05-01T12:00:02Z move b e7e5\n2024-05-01T12:00:03Z accept b\n", 2, NoOutcome, false},
+		{"2024-05-01T12:00:00Z move w e2e4\n2024-05-01T12:00:01Z start - 8/8/8/8/8/8/8/K6k w - - 0 1\n", 0, NoOutcome, false},
+	}
+	for _, test := range tests {
+		events, err := ReadMoveLog(strings.NewReader(test.log))
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, err := ReplayMoveLog(events)
+		if !test.valid {
+			if err == nil {
+				t.Fatalf("expected an error replaying\n%s", test.log)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(g.Moves()) != test.moves || g.Outcome() != test.outcome {
+			t.Fatalf("expected %d moves and %s but got %d and %s", test.moves, test.outcome, len(g.Moves()), g.Outcome())
+		}
+	}
+	if _, err := ReadMoveLog(strings.NewReader("2024-05-01T12:00:00Z castle w e1g1\n")); err == nil {
+		t.Fatal("expected an error for an unknown event type")
+	}
+}
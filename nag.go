@@ -0,0 +1,205 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A NAG is a Numeric Annotation Glyph which annotates a move or position
+// in PGN movetext as $ followed by its number, such as $1 for a good
+// move.
+type NAG uint8
+
+// The standard NAGs.  Numbers above 19 are less common and can be used
+// as NAG values directly.
+const (
+	NullNAG NAG = iota
+	GoodMove
+	Mistake
+	BrilliantMove
+	Blunder
+	SpeculativeMove
+	DubiousMove
+	ForcedMove
+	SingularMove
+	WorstMove
+	DrawishPosition
+	QuietPosition
+	ActivePosition
+	UnclearPosition
+	WhiteSlightAdvantage
+	BlackSlightAdvantage
+	WhiteModerateAdvantage
+	BlackModerateAdvantage
+	WhiteDecisiveAdvantage
+	BlackDecisiveAdvantage
+)
+
+// The NAGs ChessBase uses for its own symbols, which are written in its
+// PGN exports.  Diagram marks a move after which a diagram of the position
+// is printed and DiagramFromBlack prints it from Black's side.
+const (
+	WithTheIdea NAG = 140 + iota
+	AimedAgainst
+	BetterIs
+	WorseIs
+	EquivalentIs
+	EditorialComment
+	Novelty
+)
+
+const (
+	Diagram NAG = 220 + iota
+	DiagramFromBlack
+)
+
+// nagSymbols are the human readable symbols of the NAGs.  The first is
+// the symbol returned by Symbol and the rest are accepted by ParseNAG.
+var nagSymbols = map[NAG][]string{
+	GoodMove:               {"!"},
+	Mistake:                {"?"},
+	BrilliantMove:          {"!!"},
+	Blunder:                {"??"},
+	SpeculativeMove:        {"!?"},
+	DubiousMove:            {"?!"},
+	ForcedMove:             {"□"},
+	DrawishPosition:        {"=", "=="},
+	UnclearPosition:        {"∞"},
+	WhiteSlightAdvantage:   {"⩲", "+=", "+/="},
+	BlackSlightAdvantage:   {"⩱", "=+", "=/+"},
+	WhiteModerateAdvantage: {"±", "+/-"},
+	BlackModerateAdvantage: {"∓", "-/+"},
+	WhiteDecisiveAdvantage: {"+-", "+--"},
+	BlackDecisiveAdvantage: {"-+", "--+"},
+	WithTheIdea:            {"∆"},
+	AimedAgainst:           {"∇"},
+	BetterIs:               {"⌓"},
+	WorseIs:                {"≤", "<="},
+	EditorialComment:       {"RR"},
+	Novelty:                {"N"},
+}
+
+// String implements the fmt.Stringer interface and returns the NAG in
+// PGN format such as $14.
+func (n NAG) String() string {
+	return "$" + strconv.Itoa(int(n))
+}
+
+// Symbol returns the human readable symbol of the NAG such as !? or ±.
+// The PGN format such as $22 is returned for NAGs without a symbol.
+func (n NAG) Symbol() string {
+	if symbols, ok := nagSymbols[n]; ok {
+		return symbols[0]
+	}
+	return n.String()
+}
+
+// ParseNAG parses a NAG in PGN format such as $14 or one of the symbols
+// commonly used in its place such as !?, += or ±.
+func ParseNAG(s string) (NAG, error) {
+	if strings.HasPrefix(s, "$") {
+		n, err := strconv.ParseUint(s[1:], 10, 8)
+		if err != nil {
+			return 0, fmt.Errorf("chess: invalid NAG %s", s)
+		}
+		return NAG(n), nil
+	}
+	for n, symbols := range nagSymbols {
+		for _, symbol := range symbols {
+			if s == symbol {
+				return n, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("chess: invalid NAG %s", s)
+}
+
+// NAGs returns the move's NAGs including those written as symbols such
+// as ! and ?.
+func (m *Move) NAGs() []NAG {
+	nags := []NAG{}
+	for _, s := range m.nags {
+		if n, err := ParseNAG(s); err == nil {
+			nags = append(nags, n)
+		}
+	}
+	return nags
+}
+
+// Diagram returns true if the move has a Diagram or DiagramFromBlack NAG.
+func (m *Move) Diagram() bool {
+	for _, n := range m.NAGs() {
+		if n == Diagram || n == DiagramFromBlack {
+			return true
+		}
+	}
+	return false
+}
+
+// AddNAG adds the NAG to the move at the given ply where 1 is the first
+// move of the game.  An error is returned if the ply is out of range.
+func (g *Game) AddNAG(ply int, n NAG) error {
+	if err := g.checkPly(ply); err != nil {
+		return err
+	}
+	m := g.moves[ply-1]
+	m.nags = append(m.nags, n.String())
+	return nil
+}
+
+// isMoveSuffix returns true if the text is a move suffix annotation such
+// as !? which is written directly after the move.
+func isMoveSuffix(s string) bool {
+	return s != "" && strings.Trim(s, "!?") == ""
+}
+
+// splitMoveSuffix returns the move text without its suffix annotation and
+// the suffix if it is one of the suffixes with a NAG: !, ?, !!, ??, !? or
+// ?!.
+func splitMoveSuffix(s string) (string, string) {
+	text := strings.TrimRight(s, "!?")
+	suffix := s[len(text):]
+	if n, err := ParseNAG(suffix); err != nil || n < GoodMove || n > DubiousMove {
+		return text, ""
+	}
+	return text, suffix
+}
+
+// moveSuffix returns the symbol of the move's first suffix NAG such as !?
+// or an empty string if it doesn't have one.
+func moveSuffix(m *Move) string {
+	for _, n := range m.NAGs() {
+		if n >= GoodMove && n <= DubiousMove {
+			return n.Symbol()
+		}
+	}
+	return ""
+}
+
+// notationNAGs returns the move's NAGs other than the suffix that the
+// notation writes with the move, which AlgebraicNotation does with
+// Suffixes, so the suffix isn't written twice.
+func notationNAGs(n Notation, m *Move) []string {
+	if a, ok := n.(AlgebraicNotation); !ok || !a.Suffixes {
+		return m.nags
+	}
+	for i, s := range m.nags {
+		if nag, err := ParseNAG(s); err == nil && nag >= GoodMove && nag <= DubiousMove {
+			return append(append([]string(nil), m.nags[:i]...), m.nags[i+1:]...)
+		}
+	}
+	return m.nags
+}
+
+// withSuffix returns a copy of the move with the suffix as a NAG, or the
+// move itself if there's no suffix, so that the moves of a position aren't
+// annotated.
+func withSuffix(m *Move, suffix string) *Move {
+	if suffix == "" {
+		return m
+	}
+	cp := m.copy()
+	cp.nags = []string{suffix}
+	return cp
+}
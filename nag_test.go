@@ -0,0 +1,77 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNAG(t *testing.T) {
+	tests := []struct {
+		s   string
+		nag NAG
+	}{
+		{"$1", GoodMove},
+		{"!", GoodMove},
+		{"??", Blunder},
+		{"!?", SpeculativeMove},
+		{"+=", WhiteSlightAdvantage},
+		{"±", WhiteModerateAdvantage},
+		{"-+", BlackDecisiveAdvantage},
+		{"$146", Novelty},
+		{"∆", WithTheIdea},
+		{"<=", WorseIs},
+		{"$220", Diagram},
+	}
+	for _, test := range tests {
+		n, err := ParseNAG(test.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != test.nag {
+			t.Fatalf("expected %s to parse as %s but got %s", test.s, test.nag, n)
+		}
+	}
+	for _, s := range []string{"$", "$256", "!!!", "x"} {
+		if _, err := ParseNAG(s); err == nil {
+			t.Fatalf("expected an error parsing %s", s)
+		}
+	}
+	if s := WhiteSlightAdvantage.Symbol(); s != "⩲" {
+		t.Fatalf("expected ⩲ but got %s", s)
+	}
+	if s := NAG(22).Symbol(); s != "$22" {
+		t.Fatalf("expected $22 but got %s", s)
+	}
+}
+
+func TestNAGsRoundTrip(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("1. e4! e5 $6 2. Nf3 += 2... Nc6 ± 3. Bb5 *"), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	expected := [][]NAG{{GoodMove}, {DubiousMove}, {WhiteSlightAdvantage}, {WhiteModerateAdvantage}, {}}
+	for i, m := range g.Moves() {
+		nags := m.NAGs()
+		if len(nags) != len(expected[i]) || (len(nags) > 0 && nags[0] != expected[i][0]) {
+			t.Fatalf("expected move %d to have NAGs %v but got %v", i+1, expected[i], nags)
+		}
+	}
+	if err := g.AddNAG(5, BrilliantMove); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddNAG(6, BrilliantMove); err == nil {
+		t.Fatal("expected an error adding a NAG out of range")
+	}
+	s := g.String()
+	if !strings.HasSuffix(s, "1.e4! 1...e5 $6 2.Nf3 $14 2...Nc6 $16 3.Bb5 $3 *") {
+		t.Fatalf("unexpected pgn %s", s)
+	}
+	pgn, err = PGN(strings.NewReader(s), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := NewGame(pgn).String(); again != s {
+		t.Fatalf("expected %s but got %s", s, again)
+	}
+}
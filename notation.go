@@ -2,6 +2,7 @@ package chess
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -30,6 +31,8 @@ type Notation interface {
 // UCINotation is a more computer friendly alternative to algebraic
 // notation.  This notation uses the same format as the UCI (Universal Chess
 // Interface).  Examples: e2e4, e7e5, e1g1 (white short castling), e7e8q (for promotion)
+// Chess960 castling is also decoded as the king capturing its own rook,
+// as in UCI_Chess960, unless the king and rook are on the standard squares.
 type UCINotation struct{}
 
 // String implements the fmt.Stringer interface and returns
@@ -40,11 +43,17 @@ func (UCINotation) String() string {
 
 // Encode implements the Encoder interface.
 func (UCINotation) Encode(pos *Position, m *Move) string {
+	if m.IsNull() {
+		return uciNullMove
+	}
 	return m.S1().String() + m.S2().String() + m.Promo().String()
 }
 
 // Decode implements the Decoder interface.
 func (UCINotation) Decode(pos *Position, s string) (*Move, error) {
+	if s == uciNullMove {
+		return NullMove(), nil
+	}
 	l := len(s)
 	err := fmt.Errorf(`chess: failed to decode long algebraic notation text "%s" for position %s`, s, pos)
 	if l < 4 || l > 5 {
@@ -71,10 +80,20 @@ func (UCINotation) Decode(pos *Position, s string) (*Move, error) {
 	}
 	p := pos.Board().Piece(s1)
 	if p.Type() == King {
-		if (s1 == E1 && s2 == G1) || (s1 == E8 && s2 == G8) {
-			m.addTag(KingSideCastle)
-		} else if (s1 == E1 && s2 == C1) || (s1 == E8 && s2 == C8) {
-			m.addTag(QueenSideCastle)
+		// castling is determined by the position's castling moves rather
+		// than squares so that king moves and Chess960 castling (where
+		// the king captures its own rook) decode correctly.  Castling from
+		// the standard squares is only written as the king's move.
+		for _, c := range castleMoves(pos) {
+			side := KingSide
+			if c.HasTag(QueenSideCastle) {
+				side = QueenSide
+			}
+			rook := pos.castleRook(p.Color(), side)
+			standard := s1.File() == FileE && (rook.File() == FileA || rook.File() == FileH)
+			if c.s1 == s1 && (c.s2 == s2 || (rook == s2 && !standard)) {
+				return c, nil
+			}
 		}
 	} else if p.Type() == Pawn && s2 == pos.enPassantSquare {
 		m.addTag(EnPassant)
@@ -88,10 +107,134 @@ func (UCINotation) Decode(pos *Position, s string) (*Move, error) {
 	return m, nil
 }
 
+// ICCFNotation is the numeric notation used in correspondence chess by
+// the ICCF (International Correspondence Chess Federation).  Files and
+// ranks are numbered from 1 to 8 and a fifth digit gives the piece of a
+// promotion: 1 for a queen, 2 for a rook, 3 for a bishop and 4 for a
+// knight.  Castling is written as the king's move.  Examples: 5254 (e2e4),
+// 5171 (white short castling), 17181 (a7a8q)
+type ICCFNotation struct{}
+
+// String implements the fmt.Stringer interface and returns
+// the notation's name.
+func (ICCFNotation) String() string {
+	return "ICCF Notation"
+}
+
+// Encode implements the Encoder interface.
+func (ICCFNotation) Encode(pos *Position, m *Move) string {
+	if m.IsNull() {
+		return uciNullMove
+	}
+	s := iccfSquare(m.S1()) + iccfSquare(m.S2())
+	for i, pt := range iccfPromos {
+		if m.Promo() == pt {
+			s += strconv.Itoa(i + 1)
+		}
+	}
+	return s
+}
+
+// Decode implements the Decoder interface.
+func (ICCFNotation) Decode(pos *Position, s string) (*Move, error) {
+	if s == uciNullMove {
+		return NullMove(), nil
+	}
+	err := fmt.Errorf(`chess: failed to decode ICCF notation text "%s" for position %s`, s, pos)
+	if len(s) < 4 || len(s) > 5 {
+		return nil, err
+	}
+	uci := ""
+	for i := 0; i < 4; i++ {
+		if s[i] < '1' || s[i] > '8' {
+			return nil, err
+		}
+		if i%2 == 0 {
+			uci += string('a' + s[i] - '1')
+		} else {
+			uci += string(s[i])
+		}
+	}
+	if len(s) == 5 {
+		if s[4] < '1' || s[4] > '4' {
+			return nil, err
+		}
+		uci += iccfPromos[s[4]-'1'].String()
+	}
+	m, decodeErr := UCINotation{}.Decode(pos, uci)
+	if decodeErr != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// iccfPromos are the promotion pieces in the order of their digits.
+var iccfPromos = []PieceType{Queen, Rook, Bishop, Knight}
+
+func iccfSquare(sq Square) string {
+	return strconv.Itoa(int(sq.File())+1) + strconv.Itoa(int(sq.Rank())+1)
+}
+
+// MultiNotation decodes moves in whichever notation they are written so
+// that Game.MoveStr can accept whatever a user types.  Algebraic notation,
+// long algebraic notation, UCI notation and figurine notation are tried
+// in that order and then sloppy algebraic notation as accepted by
+// NormalizeSAN such as Ngf3 or N-f3.  Moves are encoded in algebraic
+// notation.
+type MultiNotation struct{}
+
+// String implements the fmt.Stringer interface and returns
+// the notation's name.
+func (MultiNotation) String() string {
+	return "Multi Notation"
+}
+
+// Encode implements the Encoder interface.
+func (MultiNotation) Encode(pos *Position, m *Move) string {
+	return AlgebraicNotation{}.Encode(pos, m)
+}
+
+var multiNotationDecoder = multiDecoder([]Decoder{AlgebraicNotation{}, LongAlgebraicNotation{}, UCINotation{}, FigurineNotation{}, decoderFunc(decodeLenientSAN)})
+
+// Decode implements the Decoder interface.  Unlike UCINotation, which
+// decodes any pair of squares, only legal moves are returned.
+func (MultiNotation) Decode(pos *Position, s string) (*Move, error) {
+	s = strings.TrimSpace(s)
+	m, err := multiNotationDecoder.Decode(pos, s)
+	if err != nil || m.IsNull() {
+		return m, err
+	}
+	valid := moveSlice(pos.ValidMoves()).find(m)
+	if valid == nil {
+		return nil, fmt.Errorf(`chess: illegal move "%s" for position %s`, s, pos)
+	}
+	return valid, nil
+}
+
+// decoderFunc is an adapter to allow the use of ordinary functions as
+// Decoders.
+type decoderFunc func(pos *Position, s string) (*Move, error)
+
+// Decode implements the Decoder interface.
+func (f decoderFunc) Decode(pos *Position, s string) (*Move, error) {
+	return f(pos, s)
+}
+
 // AlgebraicNotation (or Standard Algebraic Notation) is the
 // official chess notation used by FIDE. Examples: e4, e5,
-// O-O (short castling), e8=Q (promotion)
-type AlgebraicNotation struct{}
+// O-O (short castling), e8=Q (promotion).  A suffix annotation
+// such as ! or ?! is kept as the decoded move's NAG.
+type AlgebraicNotation struct {
+	// Strict only decodes text written exactly as the move is encoded,
+	// apart from trailing annotations such as ! or ?!, as arbiters and
+	// validators require.  Text with a missing x or =, unnecessary or
+	// missing disambiguation, a wrong check symbol, castling written with
+	// zeros or e.p. is rejected with an error giving the correct text.
+	Strict bool
+	// Suffixes encodes the move's suffix annotation, such as ! or ?!,
+	// from its first NAG between $1 and $6.
+	Suffixes bool
+}
 
 // String implements the fmt.Stringer interface and returns
 // the notation's name.
@@ -100,8 +243,14 @@ func (AlgebraicNotation) String() string {
 }
 
 // Encode implements the Encoder interface.
-func (AlgebraicNotation) Encode(pos *Position, m *Move) string {
+func (n AlgebraicNotation) Encode(pos *Position, m *Move) string {
+	if m.IsNull() {
+		return pgnNullMove
+	}
 	checkChar := getCheckChar(pos, m)
+	if n.Suffixes {
+		checkChar += moveSuffix(m)
+	}
 	if m.HasTag(KingSideCastle) {
 		return "O-O" + checkChar
 	} else if m.HasTag(QueenSideCastle) {
@@ -122,23 +271,151 @@ func (AlgebraicNotation) Encode(pos *Position, m *Move) string {
 }
 
 // Decode implements the Decoder interface.
-func (AlgebraicNotation) Decode(pos *Position, s string) (*Move, error) {
-	s = removeSubstrings(s, "?", "!", "+", "#", "e.p.")
-	for _, m := range pos.ValidMoves() {
+func (n AlgebraicNotation) Decode(pos *Position, s string) (*Move, error) {
+	if isNullMoveText(s) {
+		return NullMove(), nil
+	}
+	if n.Strict {
+		return decodeStrictAlgebraic(pos, s)
+	}
+	// castling is often written with zeros, which decodeSAN accepts
+	return decodeSAN(pos, s)
+}
+
+// decodeStrictAlgebraic returns the valid move that is encoded as the text
+// without its trailing annotation.
+func decodeStrictAlgebraic(pos *Position, s string) (*Move, error) {
+	text, suffix := splitMoveSuffix(s)
+	if m, err := decodeLenientSAN(pos, strings.TrimRight(text, "!?")); err == nil {
 		str := AlgebraicNotation{}.Encode(pos, m)
-		str = removeSubstrings(str, "?", "!", "+", "#", "e.p.")
-		if str == s {
-			return m, nil
+		if str == text {
+			return withSuffix(m, suffix), nil
 		}
+		return nil, fmt.Errorf("chess: algebraic notation %s should be written %s for position %s", s, str, pos)
 	}
-	return nil, fmt.Errorf("chess: could not decode algebraic notation %s for position %s", s, pos.String())
+	return nil, fmt.Errorf("chess: could not decode algebraic notation %s for position %s", s, pos)
+}
+
+// FigurineNotation is algebraic notation with the letters of the pieces
+// replaced by Unicode chess symbols as used in print.  The white symbols
+// are used for both sides.  Moves are decoded with symbols of either color
+// or with letters.  Examples: e4, ♘f3, ♗xc6, O-O (short castling), e8=♕
+// (promotion)
+type FigurineNotation struct{}
+
+// String implements the fmt.Stringer interface and returns
+// the notation's name.
+func (FigurineNotation) String() string {
+	return "Figurine Notation"
+}
+
+// Encode implements the Encoder interface.
+func (FigurineNotation) Encode(pos *Position, m *Move) string {
+	return figurineEncoder.Replace(AlgebraicNotation{}.Encode(pos, m))
+}
+
+// Decode implements the Decoder interface.
+func (FigurineNotation) Decode(pos *Position, s string) (*Move, error) {
+	m, err := AlgebraicNotation{}.Decode(pos, figurineDecoder.Replace(s))
+	if err != nil {
+		return nil, fmt.Errorf("chess: could not decode figurine notation %s for position %s", s, pos.String())
+	}
+	return m, nil
+}
+
+var (
+	figurineEncoder = strings.NewReplacer("K", "♔", "Q", "♕", "R", "♖", "B", "♗", "N", "♘")
+	figurineDecoder = strings.NewReplacer(
+		"♔", "K", "♕", "Q", "♖", "R", "♗", "B", "♘", "N",
+		"♚", "K", "♛", "Q", "♜", "R", "♝", "B", "♞", "N")
+)
+
+// LocalizedNotation is algebraic notation with the letters of the pieces
+// in another language, such as Sf3 for Nf3 in German or Cf3 in Spanish.
+// Letters are the letters of the king, queen, rook, bishop and knight in
+// that order, such as GermanPieceLetters.  Moves are only decoded with the
+// localized letters since they can mean other pieces in English, for
+// example R is the king in Spanish.  Letters that aren't five letters are
+// treated as English.
+type LocalizedNotation struct {
+	Letters string
+}
+
+// The piece letters of languages commonly found in PGN files.
+const (
+	EnglishPieceLetters    = "KQRBN"
+	GermanPieceLetters     = "KDTLS"
+	SpanishPieceLetters    = "RDTAC"
+	FrenchPieceLetters     = "RDTFC"
+	ItalianPieceLetters    = "RDTAC"
+	DutchPieceLetters      = "KDTLP"
+	PortuguesePieceLetters = "RDTBC"
+	SwedishPieceLetters    = "KDTLS"
+	PolishPieceLetters     = "KHWGS"
+)
+
+// String implements the fmt.Stringer interface and returns
+// the notation's name.
+func (n LocalizedNotation) String() string {
+	return "Localized Notation (" + n.letters() + ")"
+}
+
+// Encode implements the Encoder interface.
+func (n LocalizedNotation) Encode(pos *Position, m *Move) string {
+	return n.replacer(true).Replace(AlgebraicNotation{}.Encode(pos, m))
+}
+
+// Decode implements the Decoder interface.
+func (n LocalizedNotation) Decode(pos *Position, s string) (*Move, error) {
+	err := fmt.Errorf("chess: could not decode %s %s for position %s", n, s, pos.String())
+	for _, c := range s {
+		// English letters that aren't localized letters aren't pieces
+		if strings.ContainsRune(EnglishPieceLetters, c) && !strings.ContainsRune(n.letters(), c) {
+			return nil, err
+		}
+	}
+	m, decodeErr := AlgebraicNotation{}.Decode(pos, n.replacer(false).Replace(s))
+	if decodeErr != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (n LocalizedNotation) letters() string {
+	if len([]rune(n.Letters)) != 5 {
+		return EnglishPieceLetters
+	}
+	return n.Letters
+}
+
+// replacer returns a replacer from English to the localized letters if
+// encode is true and from the localized letters to English otherwise.
+func (n LocalizedNotation) replacer(encode bool) *strings.Replacer {
+	localized := []rune(n.letters())
+	pairs := []string{}
+	for i, c := range EnglishPieceLetters {
+		if encode {
+			pairs = append(pairs, string(c), string(localized[i]))
+		} else {
+			pairs = append(pairs, string(localized[i]), string(c))
+		}
+	}
+	return strings.NewReplacer(pairs...)
 }
 
 // LongAlgebraicNotation is a fully expanded version of
 // algebraic notation in which the starting and ending
-// squares are specified.
-// Examples: e2e4, Rd3xd7, O-O (short castling), e7e8=Q (promotion)
-type LongAlgebraicNotation struct{}
+// squares are specified and separated by - or by x for
+// captures.  Moves are decoded with or without the - so
+// pawn moves such as e2e4 are accepted, but piece moves
+// and promotions in UCI text such as g1f3 or e7e8q aren't.
+// Examples: e2-e4, Rd3xd7, O-O (short castling), e7-e8=Q (promotion)
+type LongAlgebraicNotation struct {
+	// Strict validates the text against the position when decoding
+	// and returns a *MoveError explaining why an illegal move was
+	// rejected instead of a generic error.
+	Strict bool
+}
 
 // String implements the fmt.Stringer interface and returns
 // the notation's name.
@@ -148,6 +425,9 @@ func (LongAlgebraicNotation) String() string {
 
 // Encode implements the Encoder interface.
 func (LongAlgebraicNotation) Encode(pos *Position, m *Move) string {
+	if m.IsNull() {
+		return pgnNullMove
+	}
 	checkChar := getCheckChar(pos, m)
 	if m.HasTag(KingSideCastle) {
 		return "O-O" + checkChar
@@ -156,31 +436,130 @@ func (LongAlgebraicNotation) Encode(pos *Position, m *Move) string {
 	}
 	p := pos.Board().Piece(m.S1())
 	pChar := charFromPieceType(p.Type())
-	s1Str := m.s1.String()
-	capChar := ""
+	capChar := "-"
 	if m.HasTag(Capture) || m.HasTag(EnPassant) {
 		capChar = "x"
-		if p.Type() == Pawn && s1Str == "" {
-			capChar = m.s1.File().String() + "x"
-		}
 	}
 	promoText := charForPromo(m.promo)
-	return pChar + s1Str + capChar + m.s2.String() + promoText + checkChar
+	return pChar + m.s1.String() + capChar + m.s2.String() + promoText + checkChar
 }
 
 // Decode implements the Decoder interface.
-func (LongAlgebraicNotation) Decode(pos *Position, s string) (*Move, error) {
-	s = removeSubstrings(s, "?", "!", "+", "#", "e.p.")
+func (n LongAlgebraicNotation) Decode(pos *Position, s string) (*Move, error) {
+	if isNullMoveText(s) {
+		return NullMove(), nil
+	}
+	if n.Strict {
+		return decodeStrictLongAlgebraic(pos, s)
+	}
+	s = longAlgebraicKey(s)
 	for _, m := range pos.ValidMoves() {
-		str := LongAlgebraicNotation{}.Encode(pos, m)
-		str = removeSubstrings(str, "?", "!", "+", "#", "e.p.")
-		if str == s {
+		if longAlgebraicKey(LongAlgebraicNotation{}.Encode(pos, m)) == s {
 			return m, nil
 		}
 	}
 	return nil, fmt.Errorf("chess: could not decode long algebraic notation %s for position %s", s, pos.String())
 }
 
+// longAlgebraicKey returns the text without suffixes and the separator
+// between the squares so that e2-e4 and e2e4 compare equal.
+func longAlgebraicKey(s string) string {
+	s = removeSubstrings(s, "?", "!", "+", "#", "e.p.")
+	if strings.HasPrefix(s, "O-O") {
+		return s
+	}
+	return strings.Replace(s, "-", "", 1)
+}
+
+func decodeStrictLongAlgebraic(pos *Position, s string) (*Move, error) {
+	text := removeSubstrings(s, "?", "!", "+", "#", "e.p.")
+	m := &Move{}
+	pieceType := Pawn
+	switch strings.Replace(text, "0", "O", -1) {
+	case "O-O", "O-O-O":
+		return decodeStrictCastle(pos, len(text) == 5)
+	default:
+		if len(text) > 0 && strings.ContainsRune("KQRBN", rune(text[0])) {
+			pieceType = pieceTypeFromChar(strings.ToLower(text[:1]))
+			if text[0] == 'K' {
+				pieceType = King
+			}
+			text = text[1:]
+		}
+		if len(text) < 4 {
+			return nil, fmt.Errorf("chess: invalid long algebraic notation %s", s)
+		}
+		s1, ok1 := strToSquareMap[text[:2]]
+		text = strings.TrimLeft(text[2:], "x-")
+		if len(text) < 2 {
+			return nil, fmt.Errorf("chess: invalid long algebraic notation %s", s)
+		}
+		s2, ok2 := strToSquareMap[text[:2]]
+		promo := strings.ToLower(strings.TrimPrefix(text[2:], "="))
+		if !ok1 || !ok2 || len(promo) > 1 || (promo != "" && pieceTypeFromChar(promo) == NoPieceType) {
+			return nil, fmt.Errorf("chess: invalid long algebraic notation %s", s)
+		}
+		m.s1, m.s2, m.promo = s1, s2, pieceTypeFromChar(promo)
+	}
+	if p := pos.board.Piece(m.s1); p != NoPiece && p.Color() == pos.turn && p.Type() != pieceType {
+		return nil, &MoveError{Move: m, Reason: WrongPiece}
+	}
+	if valid := moveSlice(pos.ValidMoves()).find(m); valid != nil {
+		return valid, nil
+	}
+	return nil, &MoveError{Move: m, Reason: pos.illegalReason(m)}
+}
+
+// decodeStrictCastle returns the castling move on the side from the
+// position's castling moves so that Chess960 castling is decoded with the
+// king and rook on their starting squares.
+func decodeStrictCastle(pos *Position, queenSide bool) (*Move, error) {
+	tag, side := KingSideCastle, KingSide
+	if queenSide {
+		tag, side = QueenSideCastle, QueenSide
+	}
+	for _, m := range castleMoves(pos) {
+		if m.HasTag(tag) {
+			return m, nil
+		}
+	}
+	king := pos.board.whiteKingSq
+	if pos.turn == Black {
+		king = pos.board.blackKingSq
+	}
+	if king == NoSquare {
+		m := &Move{s1: E1}
+		if pos.turn == Black {
+			m.s1 = E8
+		}
+		m.s2, _ = castleTargets(m.s1.Rank(), side)
+		return nil, &MoveError{Move: m, Reason: NoPieceOnSquare}
+	}
+	rook := pos.castleRook(pos.turn, side)
+	m := &Move{s1: king}
+	m.s2, _ = castleTargets(king.Rank(), side)
+	if m.s2 == king && rook != NoSquare {
+		m.s2 = rook
+	}
+	if rook == NoSquare || rook.Rank() != king.Rank() {
+		return nil, &MoveError{Move: m, Reason: IllegalPieceMove}
+	}
+	return nil, &MoveError{Move: m, Reason: pos.castlePathReason(king, rook, side)}
+}
+
+const (
+	// pgnNullMove is the null move in PGN as written by most programs
+	pgnNullMove = "--"
+	// uciNullMove is the null move in UCI
+	uciNullMove = "0000"
+)
+
+// isNullMoveText returns true if s is a null move in algebraic notation,
+// which is written as -- or as Z0 by some programs.
+func isNullMoveText(s string) bool {
+	return s == pgnNullMove || s == "Z0"
+}
+
 func getCheckChar(pos *Position, move *Move) string {
 	if !move.HasTag(Check) {
 		return ""
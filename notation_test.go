@@ -3,6 +3,7 @@ package chess
 import (
 	"encoding/json"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -110,3 +111,402 @@ func TestInvalidDecoding(t *testing.T) {
 		}
 	}
 }
+
+func TestStrictLongAlgebraicDecode(t *testing.T) {
+	n := LongAlgebraicNotation{Strict: true}
+	pos := unsafeFEN("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	if m, err := n.Decode(pos, "Ng1-f3"); err != nil || m.S2() != F3 {
+		t.Fatalf("expected Ng1-f3 to decode but got %v", err)
+	}
+	tests := []struct {
+		s      string
+		reason MoveErrorReason
+	}{
+		{"e3e4", NoPieceOnSquare},
+		{"e7e5", WrongTurn},
+		{"Bg1f3", WrongPiece},
+		{"g1f3", WrongPiece},
+		{"Bf1c4", PathBlocked},
+		{"O-O", PathBlocked},
+	}
+	for _, test := range tests {
+		_, err := n.Decode(pos, test.s)
+		moveErr, ok := err.(*MoveError)
+		if !ok || moveErr.Reason != test.reason {
+			t.Fatalf("expected %s to fail with %s but got %v", test.s, test.reason, err)
+		}
+	}
+	if _, err := n.Decode(pos, "e2"); err == nil {
+		t.Fatal("expected malformed notation to return an error")
+	}
+	castles := []struct {
+		fen string
+		s   string
+		s1  Square
+		s2  Square
+	}{
+		{"b1q1rrkb/pppppppp/3nn3/8/P7/1PPP4/4PPPP/BQNNRKRB w GE - 1 9", "O-O", F1, G1},
+		{"1r2k1r1/8/8/8/8/8/8/1R1K2R1 b kq - 0 1", "O-O-O+", E8, B8},
+	}
+	for _, test := range castles {
+		pos := unsafeFEN(test.fen)
+		m, err := n.Decode(pos, test.s)
+		if err != nil {
+			t.Fatalf("expected %s to decode in %s but got %v", test.s, test.fen, err)
+		}
+		if m.S1() != test.s1 || m.S2() != test.s2 {
+			t.Fatalf("expected %s to decode to %s%s in %s but got %s", test.s, test.s1, test.s2, test.fen, m)
+		}
+		if txt := n.Encode(pos, m); strings.TrimRight(txt, "+#") != strings.TrimRight(test.s, "+#") {
+			t.Fatalf("expected %s to encode as %s but got %s", m, test.s, txt)
+		}
+	}
+	pos = unsafeFEN("rk5r/8/8/8/8/8/8/RK3B1R w KQkq - 0 1")
+	if _, err := n.Decode(pos, "O-O"); err == nil {
+		t.Fatal("expected blocked Chess960 castling to fail")
+	} else if moveErr, ok := err.(*MoveError); !ok || moveErr.Reason != PathBlocked {
+		t.Fatalf("expected blocked Chess960 castling to fail with %s but got %v", PathBlocked, err)
+	}
+}
+
+func TestStrictAlgebraicDecode(t *testing.T) {
+	n := AlgebraicNotation{Strict: true}
+	tests := []struct {
+		fen   string
+		valid []string
+		wrong []string
+	}{
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", []string{"exd5", "exd5!?", "Nf3", "Bb5+", "Bb5+?"}, []string{"ed5", "e4d5", "exd5+", "Ngf3", "Nf3+", "Bb5", "Bb5#"}},
+		{"8/P7/8/8/8/8/8/2k4K w - - 0 1", []string{"a8=Q", "a8=N"}, []string{"a8Q", "a8", "a7a8=Q"}},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", []string{"O-O", "O-O-O"}, []string{"0-0", "0-0-0"}},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", []string{"exd6"}, []string{"exd6e.p.", "exd6 e.p."}},
+		{"7k/8/6K1/8/8/8/8/R7 w - - 0 1", []string{"Ra8#"}, []string{"Ra8", "Ra8+"}},
+	}
+	for _, test := range tests {
+		pos := unsafeFEN(test.fen)
+		for _, s := range test.valid {
+			if _, err := n.Decode(pos, s); err != nil {
+				t.Fatalf("expected %s to decode but got %v", s, err)
+			}
+		}
+		for _, s := range test.wrong {
+			if _, err := n.Decode(pos, s); err == nil {
+				t.Fatalf("expected %s to be rejected in position %s", s, pos)
+			}
+		}
+	}
+	pos := unsafeFEN("rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2")
+	if _, err := n.Decode(pos, "Bb5"); err == nil || !strings.Contains(err.Error(), "should be written Bb5+") {
+		t.Fatalf("expected the error to give the correct text but got %v", err)
+	}
+}
+
+func TestChess960Castling(t *testing.T) {
+	tests := []struct {
+		fen      string
+		notation Notation
+		move     string
+		postFEN  string
+	}{
+		{"r3k2r/8/8/8/8/8/8/1R2K1R1 w KQkq - 0 1", AlgebraicNotation{}, "O-O", "r3k2r/8/8/8/8/8/8/1R3RK1 b kq - 0 1"},
+		{"4k3/8/8/8/8/8/8/RK6 w Q - 0 1", AlgebraicNotation{}, "O-O-O", "4k3/8/8/8/8/8/8/2KR4 b - - 0 1"},
+		{"4k3/8/8/8/8/8/8/RK6 w Q - 0 1", UCINotation{}, "b1a1", "4k3/8/8/8/8/8/8/2KR4 b - - 0 1"},
+		{"4k3/8/8/8/8/8/8/RK6 w Q - 0 1", AlgebraicNotation{}, "Kc1", "4k3/8/8/8/8/8/8/R1K5 b - - 0 1"},
+		{"4k3/8/8/8/8/8/8/6KR w K - 0 1", AlgebraicNotation{}, "O-O", "4k3/8/8/8/8/8/8/5RK1 b - - 0 1"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", UCINotation{}, "e1g1", "r3k2r/8/8/8/8/8/8/R4RK1 b kq - 0 1"},
+	}
+	for _, test := range tests {
+		pos := unsafeFEN(test.fen)
+		m, err := test.notation.Decode(pos, test.move)
+		if err != nil {
+			t.Fatalf("failed to decode %s for %s: %s", test.move, test.fen, err)
+		}
+		if m = moveSlice(pos.ValidMoves()).find(m); m == nil {
+			t.Fatalf("expected %s to be valid for %s", test.move, test.fen)
+		}
+		if s := pos.Update(m).String(); s != test.postFEN {
+			t.Fatalf("expected %s after %s but got %s", test.postFEN, test.move, s)
+		}
+		if _, ok := test.notation.(AlgebraicNotation); ok && test.notation.Encode(pos, m) != test.move {
+			t.Fatalf("expected %s to encode as %s but got %s", test.fen, test.move, test.notation.Encode(pos, m))
+		}
+	}
+	// standard castling is only written as the king's move in UCI
+	pos := unsafeFEN("r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1")
+	if m, err := (UCINotation{}).Decode(pos, "e1h1"); err == nil && moveSlice(pos.ValidMoves()).find(m) != nil {
+		t.Fatalf("expected e1h1 not to be a valid move but got %s", m)
+	}
+}
+
+func TestLongAlgebraicAndUCIText(t *testing.T) {
+	pos := unsafeFEN("r3k2r/1P6/8/8/8/8/8/R3K2R w KQkq - 0 1")
+	lan := LongAlgebraicNotation{}
+	tests := []struct {
+		lan string
+		uci string
+	}{
+		{"Ra1-a7", "a1a7"},
+		{"Ra1xa8+", "a1a8"},
+		{"b7-b8=Q+", "b7b8q"},
+		{"b7xa8=N", "b7a8n"},
+		{"O-O", "e1g1"},
+		{"O-O-O", "e1c1"},
+	}
+	for _, test := range tests {
+		m, err := UCINotation{}.Decode(pos, test.uci)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the valid move has the tags such as check needed to encode it
+		m = moveSlice(pos.ValidMoves()).find(m)
+		if s := lan.Encode(pos, m); s != test.lan {
+			t.Fatalf("expected %s to be encoded as %s but got %s", test.uci, test.lan, s)
+		}
+		if s := (UCINotation{}).Encode(pos, m); s != test.uci {
+			t.Fatalf("expected %s to be encoded as %s but got %s", test.lan, test.uci, s)
+		}
+		if decoded, err := lan.Decode(pos, test.lan); err != nil || decoded.String() != m.String() {
+			t.Fatalf("expected %s to decode but got %v", test.lan, err)
+		}
+	}
+	if m, err := lan.Decode(pos, "Ra1a7"); err != nil || m.S2() != A7 {
+		t.Fatalf("expected the separator to be optional but got %v", err)
+	}
+	for _, s := range []string{"b7b8q", "e1g1", "a1a7"} {
+		if _, err := lan.Decode(pos, s); err == nil {
+			t.Fatalf("expected UCI text %s to be rejected as long algebraic notation", s)
+		}
+	}
+	for _, s := range []string{"a1-a7", "b7b8Q", "b7b8=q", "O-O"} {
+		if _, err := (UCINotation{}).Decode(pos, s); err == nil {
+			t.Fatalf("expected %s to be rejected as UCI text", s)
+		}
+	}
+}
+
+func TestFigurineNotation(t *testing.T) {
+	pos := unsafeFEN("r3k2r/1P6/2n5/8/4B3/5N2/8/R3K2R w KQkq - 0 1")
+	tests := []struct {
+		figurine string
+		san      string
+	}{
+		{"♘d4", "Nd4"},
+		{"♗xc6+", "Bxc6+"},
+		{"bxa8=♕+", "bxa8=Q+"},
+		{"O-O", "O-O"},
+		{"♔f1", "Kf1"},
+	}
+	for _, test := range tests {
+		m, err := AlgebraicNotation{}.Decode(pos, test.san)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := (FigurineNotation{}).Encode(pos, m); s != test.figurine {
+			t.Fatalf("expected %s to be encoded as %s but got %s", test.san, test.figurine, s)
+		}
+		for _, s := range []string{test.figurine, test.san, figurineBlack.Replace(test.figurine)} {
+			if decoded, err := (FigurineNotation{}).Decode(pos, s); err != nil || decoded != m {
+				t.Fatalf("expected %s to decode to %s but got %v", s, m, err)
+			}
+		}
+	}
+	if _, err := (FigurineNotation{}).Decode(pos, "♕d4"); err == nil {
+		t.Fatal("expected an illegal move to be rejected")
+	}
+}
+
+var figurineBlack = strings.NewReplacer("♔", "♚", "♕", "♛", "♖", "♜", "♗", "♝", "♘", "♞")
+
+func TestICCFNotation(t *testing.T) {
+	tests := []struct {
+		fen  string
+		uci  string
+		iccf string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e2e4", "5254"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "g1f3", "7163"},
+		{"r3k2r/1P6/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", "5171"},
+		{"r3k2r/1P6/8/8/8/8/8/R3K2R b KQkq - 0 1", "e8c8", "5838"},
+		{"r3k2r/1P6/8/8/8/8/8/R3K2R w KQkq - 0 1", "b7b8q", "27281"},
+		{"r3k2r/1P6/8/8/8/8/8/R3K2R w KQkq - 0 1", "b7a8n", "27184"},
+	}
+	for _, test := range tests {
+		pos := unsafeFEN(test.fen)
+		m, err := UCINotation{}.Decode(pos, test.uci)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := (ICCFNotation{}).Encode(pos, m); s != test.iccf {
+			t.Fatalf("expected %s to be encoded as %s but got %s", test.uci, test.iccf, s)
+		}
+		decoded, err := ICCFNotation{}.Decode(pos, test.iccf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.String() != m.String() || decoded.HasTag(KingSideCastle) != m.HasTag(KingSideCastle) {
+			t.Fatalf("expected %s to decode to %s but got %s", test.iccf, m, decoded)
+		}
+	}
+	pos := StartingPosition()
+	for _, s := range []string{"525", "5294", "52545", "e2e4", "525401"} {
+		if _, err := (ICCFNotation{}).Decode(pos, s); err == nil {
+			t.Fatalf("expected %s to be rejected", s)
+		}
+	}
+	g := NewGame(UseNotation(ICCFNotation{}))
+	for _, s := range []string{"5254", "5755", "7163"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s := g.EncodeMoves(ICCFNotation{}); s != "1.5254 5755 2.7163 *" {
+		t.Fatalf("expected ICCF movetext but got %s", s)
+	}
+}
+
+func TestICCFPGN(t *testing.T) {
+	opt, err := PGN(strings.NewReader("[Event \"ICCF\"]\n\n1. 5254 5755 2. 7163 2836 1/2-1/2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	if s := g.EncodeMoves(AlgebraicNotation{}); s != "1.e4 e5 2.Nf3 Nc6  1/2-1/2" {
+		t.Fatalf("expected the ICCF moves to be decoded but got %s", s)
+	}
+	// 2838 isn't legal and without a period isn't a move number
+	_, err = PGN(strings.NewReader("1. 5254 5755 2. 7163 2838 *"))
+	if err == nil || !strings.Contains(err.Error(), "illegal move 2838") {
+		t.Fatalf("expected an illegal move error but got %v", err)
+	}
+}
+
+func TestLocalizedNotation(t *testing.T) {
+	pos := unsafeFEN("r3k2r/1P6/2n5/8/4B3/5N2/8/R3K2R w KQkq - 0 1")
+	tests := []struct {
+		letters   string
+		localized string
+		san       string
+	}{
+		{GermanPieceLetters, "Sd4", "Nd4"},
+		{GermanPieceLetters, "Lxc6+", "Bxc6+"},
+		{GermanPieceLetters, "bxa8=D+", "bxa8=Q+"},
+		{SpanishPieceLetters, "Rf1", "Kf1"},
+		{SpanishPieceLetters, "Ta7", "Ra7"},
+		{SpanishPieceLetters, "Cg5", "Ng5"},
+		{DutchPieceLetters, "Pd4", "Nd4"},
+		{"", "Kf1", "Kf1"},
+	}
+	for _, test := range tests {
+		n := LocalizedNotation{Letters: test.letters}
+		m, err := AlgebraicNotation{}.Decode(pos, test.san)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := n.Encode(pos, m); s != test.localized {
+			t.Fatalf("expected %s to be encoded as %s in %s but got %s", test.san, test.localized, test.letters, s)
+		}
+		if decoded, err := n.Decode(pos, test.localized); err != nil || decoded != m {
+			t.Fatalf("expected %s to decode to %s in %s but got %v", test.localized, test.san, test.letters, err)
+		}
+	}
+	// R is the king in Spanish so Ra7 isn't a move
+	if _, err := (LocalizedNotation{Letters: SpanishPieceLetters}).Decode(pos, "Ra7"); err == nil {
+		t.Fatal("expected the English letter to be rejected")
+	}
+}
+
+func TestLocalizedPGN(t *testing.T) {
+	opt, err := PGN(strings.NewReader("1. e4 e5 2. Sf3 Sc6 3. Lb5 a6 4. O-O *"), LocalizedPGN(GermanPieceLetters))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := NewGame(opt).EncodeMoves(AlgebraicNotation{}); s != "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.O-O *" {
+		t.Fatalf("expected the German moves to be decoded but got %s", s)
+	}
+	if _, err := PGN(strings.NewReader("1. e4 e5 2. Nf3 *"), LocalizedPGN(GermanPieceLetters)); err == nil {
+		t.Fatal("expected English letters to be rejected")
+	}
+}
+
+func TestMultiNotation(t *testing.T) {
+	g := NewGame(UseNotation(MultiNotation{}))
+	for _, s := range []string{"e4", "e7-e5", "g1f3", "♞c6", "Bf1-c4", "N-f6", " d3 "} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatalf("expected %q to be decoded but got %v", s, err)
+		}
+	}
+	if s := g.EncodeMoves(MultiNotation{}); s != "1.e4 e5 2.Nf3 Nc6 3.Bc4 Nf6 4.d3 *" {
+		t.Fatalf("expected the moves in algebraic notation but got %s", s)
+	}
+	if err := g.MoveStr("Qz9"); err == nil {
+		t.Fatal("expected invalid text to be rejected")
+	}
+	if m, err := (MultiNotation{}).Decode(g.Position(), "a1a8"); err == nil {
+		t.Fatalf("expected the illegal move a1a8 to be rejected but got %s", m)
+	}
+}
+
+func BenchmarkAlgebraicDecode(b *testing.B) {
+	pos := unsafeFEN("r1bqk2r/pp2bppp/2nppn2/8/3NP3/2N1B3/PPPQ1PPP/R3KB1R w KQkq - 0 8")
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := (AlgebraicNotation{}).Decode(pos, "Ndb5"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAlgebraicSuffixes(t *testing.T) {
+	pos := unsafeFEN("rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2")
+	tests := []struct {
+		text   string
+		nags   []NAG
+		encode string
+	}{
+		{"exd5", []NAG{}, "exd5"},
+		{"exd5!", []NAG{GoodMove}, "exd5!"},
+		{"Bb5+?!", []NAG{DubiousMove}, "Bb5+?!"},
+		{"Nf3!!", []NAG{BrilliantMove}, "Nf3!!"},
+		{"Nc3??", []NAG{Blunder}, "Nc3??"},
+	}
+	n := AlgebraicNotation{Suffixes: true}
+	for _, test := range tests {
+		m, err := AlgebraicNotation{}.Decode(pos, test.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m.NAGs(), test.nags) {
+			t.Fatalf("expected %s to have NAGs %v but got %v", test.text, test.nags, m.NAGs())
+		}
+		if s := n.Encode(pos, m); s != test.encode {
+			t.Fatalf("expected %s to encode as %s but got %s", test.text, test.encode, s)
+		}
+	}
+	for _, m := range pos.ValidMoves() {
+		if len(m.NAGs()) != 0 {
+			t.Fatalf("expected the position's move %s to have no NAGs", m)
+		}
+	}
+	g := NewGame()
+	if err := g.MoveStr("e4!?"); err != nil {
+		t.Fatal(err)
+	}
+	if s := g.String(); s != "1.e4!? *" {
+		t.Fatalf("expected the suffix to be kept but got %q", s)
+	}
+	opt, err := PGN(strings.NewReader("1. e4!! e5?! $14 *"), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewGame(opt).EncodeMoves(n)
+	if s != "1.e4!! e5?! $14 *" {
+		t.Fatalf("expected each suffix to be written once but got %q", s)
+	}
+	opt, err = PGN(strings.NewReader(s), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moves := NewGame(opt).Moves(); !reflect.DeepEqual(moves[1].NAGs(), []NAG{DubiousMove, WhiteSlightAdvantage}) {
+		t.Fatalf("expected the NAGs to round trip but got %v", moves[1].NAGs())
+	}
+}
@@ -0,0 +1,154 @@
+package opening
+
+import (
+	"github.com/notnil/chess"
+)
+
+// BookExit is the move with which a side left the opening book.
+type BookExit struct {
+	// Ply is the ply of the move where 1 is the first move of the game or
+	// zero if the side never left book.
+	Ply int
+	// Move is the move that left book.
+	Move *chess.Move
+	// Deviated is true if the position was in book but the move wasn't and
+	// false if the opponent had already left book.
+	Deviated bool
+	// Games is the number of explorer games that played the move.
+	Games int
+	// Score is the side's score from 0 to 1 in the explorer games that
+	// played the move.
+	Score float64
+	// KnownBad is true if the side deviated with a move that scored at
+	// most the bad score in the explorer's games.
+	KnownBad bool
+}
+
+// BookExits are the moves with which each side left the opening book.
+type BookExits struct {
+	White BookExit
+	Black BookExit
+}
+
+// FindBookExits returns where each side of the game left the book.  A move
+// is in book if an opening of the book continues with it.
+func FindBookExits(g *chess.Game, b Book) BookExits {
+	moves := g.Moves()
+	return findBookExits(g, func(i int) (bool, bool, BookExit) {
+		return inBookECO(b, moves[:i]), inBookECO(b, moves[:i+1]), BookExit{}
+	})
+}
+
+type exitOptions struct {
+	minGames int
+	badScore float64
+}
+
+// ExitMinGames is an option for Explorer.BookExits that sets the fewest
+// games a position or move needs to be in book.  The default is 1.
+func ExitMinGames(n int) func(*exitOptions) {
+	return func(o *exitOptions) {
+		o.minGames = n
+	}
+}
+
+// ExitBadScore is an option for Explorer.BookExits that sets the score at
+// or below which a deviation the explorer has games for is known to be
+// bad.  The default is 0.35.
+func ExitBadScore(score float64) func(*exitOptions) {
+	return func(o *exitOptions) {
+		o.badScore = score
+	}
+}
+
+// BookExits returns where each side of the game left the explorer's book.
+// A position or move is in book if it was reached or played in at least
+// the minimum number of games set by ExitMinGames.  Deviations with fewer
+// games are reported with their statistics so with a minimum above one
+// rare moves that scored badly are flagged as known to be bad.
+func (e *Explorer) BookExits(g *chess.Game, opts ...func(*exitOptions)) BookExits {
+	o := &exitOptions{minGames: 1, badScore: 0.35}
+	for _, f := range opts {
+		if f != nil {
+			f(o)
+		}
+	}
+	positions := g.Positions()
+	moves := g.Moves()
+	return findBookExits(g, func(i int) (bool, bool, BookExit) {
+		stats := e.Position(positions[i])
+		if stats == nil || stats.Games() < o.minGames {
+			return false, false, BookExit{}
+		}
+		uci := chess.UCINotation{}.Encode(positions[i], moves[i])
+		exit := BookExit{}
+		for _, s := range stats.Moves {
+			if s.Move != uci {
+				continue
+			}
+			if s.Games() >= o.minGames {
+				return true, true, exit
+			}
+			wins := s.White
+			if positions[i].Turn() == chess.Black {
+				wins = s.Black
+			}
+			exit.Games = s.Games()
+			exit.Score = (float64(wins) + float64(s.Draws)/2) / float64(s.Games())
+			exit.KnownBad = exit.Score <= o.badScore
+		}
+		return true, false, exit
+	})
+}
+
+// findBookExits walks the game's moves until both sides leave book.  The
+// book function returns whether the position before the move at index i
+// is in book, whether the move is and details of the exit otherwise.
+func findBookExits(g *chess.Game, book func(i int) (bool, bool, BookExit)) BookExits {
+	exits := BookExits{}
+	positions := g.Positions()
+	for i, m := range g.Moves() {
+		if exits.White.Ply != 0 && exits.Black.Ply != 0 {
+			break
+		}
+		exit := &exits.White
+		if positions[i].Turn() == chess.Black {
+			exit = &exits.Black
+		}
+		if exit.Ply != 0 {
+			continue
+		}
+		posInBook, moveInBook, details := book(i)
+		if moveInBook {
+			continue
+		}
+		*exit = details
+		exit.Ply = i + 1
+		exit.Move = m
+		exit.Deviated = posInBook
+	}
+	return exits
+}
+
+// inBookECO returns true if an opening of the book starts with the moves.
+func inBookECO(b Book, moves []*chess.Move) bool {
+	if len(moves) == 0 {
+		return true
+	}
+	// the possible openings all continue from the longest prefix of the
+	// moves in the book so checking one shows whether every move matched
+	possible := b.Possible(moves)
+	if len(possible) == 0 {
+		return false
+	}
+	bookMoves := possible[0].Game().Moves()
+	if len(bookMoves) < len(moves) {
+		return false
+	}
+	for i, m := range moves {
+		if m.String() != bookMoves[i].String() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package opening_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/opening"
+)
+
+func exitGame(t *testing.T, moves ...string) *chess.Game {
+	g := chess.NewGame()
+	for _, m := range moves {
+		if err := g.MoveStr(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestFindBookExits(t *testing.T) {
+	book := opening.NewBookECO()
+	g := exitGame(t, "e4", "e6", "d4", "d5", "e5", "c5", "a3", "Nc6")
+	exits := opening.FindBookExits(g, book)
+	if exits.White.Ply != 7 || exits.White.Move.String() != "a2a3" || !exits.White.Deviated {
+		t.Fatalf("expected white to deviate but got %+v", exits.White)
+	}
+	if exits.Black.Ply != exits.White.Ply+1 || exits.Black.Deviated {
+		t.Fatalf("expected black to leave book after white without deviating but got %+v", exits.Black)
+	}
+
+	exits = opening.FindBookExits(exitGame(t, "e4", "e5", "Nf3", "Nc6", "Bb5", "h5"), book)
+	if exits.White.Ply != 0 || exits.Black.Ply != 6 || !exits.Black.Deviated {
+		t.Fatalf("expected only black to deviate on ply 6 but got %+v", exits)
+	}
+}
+
+func TestExplorerBookExits(t *testing.T) {
+	pgn := strings.Repeat("1. e4 e5 2. Nf3 Nc6 1-0\n\n", 3) +
+		"1. e4 e5 2. Nf3 f6 1-0\n\n1. e4 e5 2. Nf3 f6 1-0\n\n1. e4 e5 2. Nf3 d6 0-1\n\n"
+	e := opening.NewExplorer()
+	if _, err := e.AddPGN(strings.NewReader(pgn)); err != nil {
+		t.Fatal(err)
+	}
+
+	exits := e.BookExits(exitGame(t, "e4", "e5", "Nf3", "f6", "Nxe5"), opening.ExitMinGames(3))
+	black := exits.Black
+	if black.Ply != 4 || !black.Deviated || black.Games != 2 || black.Score != 0 || !black.KnownBad {
+		t.Fatalf("expected black's f6 to be a known bad deviation but got %+v", black)
+	}
+	if exits.White.Ply != 5 || exits.White.Deviated {
+		t.Fatalf("expected white to leave book after black but got %+v", exits.White)
+	}
+
+	exits = e.BookExits(exitGame(t, "e4", "e5", "Nf3", "d6"), opening.ExitMinGames(3))
+	if exits.Black.Ply != 4 || exits.Black.Games != 1 || exits.Black.Score != 1 || exits.Black.KnownBad {
+		t.Fatalf("expected d6 to be a deviation that isn't known to be bad but got %+v", exits.Black)
+	}
+
+	exits = e.BookExits(exitGame(t, "e4", "e5", "Nf3", "f6"))
+	if exits.White.Ply != 0 || exits.Black.Ply != 0 {
+		t.Fatalf("expected neither side to leave book but got %+v", exits)
+	}
+}
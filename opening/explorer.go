@@ -0,0 +1,248 @@
+package opening
+
+import (
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/notnil/chess"
+)
+
+// MoveStats are the results of the games that played a move.
+type MoveStats struct {
+	// Move is the move in UCI notation.
+	Move  string
+	White int
+	Draws int
+	Black int
+}
+
+// Games returns the number of games that played the move.
+func (s MoveStats) Games() int {
+	return s.White + s.Draws + s.Black
+}
+
+// PositionStats are the results of the games that reached a position
+// and the moves played from it ordered by popularity.
+type PositionStats struct {
+	White int
+	Draws int
+	Black int
+	Moves []MoveStats
+}
+
+// Games returns the number of games that reached the position.
+func (s *PositionStats) Games() int {
+	return s.White + s.Draws + s.Black
+}
+
+// Explorer is an opening explorer built from the games added to it.
+// Positions are keyed by their normalized hash so transpositions share
+// their statistics.  Explorer is safe for concurrent use so games can be
+// added while queries run.
+type Explorer struct {
+	mu        sync.RWMutex
+	maxPly    int
+	maxGames  int
+	games     int
+	positions map[[16]byte]*explorerEntry
+	// records describe the games by ID for queries
+	records []ExplorerGame
+}
+
+type explorerEntry struct {
+	results [3]int
+	moves   map[string]*explorerMove
+	// ended are the IDs of the games whose added moves end here
+	ended []int32
+}
+
+type explorerMove struct {
+	results [3]int
+	// games are the IDs of the games that played the move in order
+	games []int32
+}
+
+// ExplorerMaxPly is an option for NewExplorer that limits the number of
+// plies of each game that are added.  The default of zero adds every
+// ply.
+func ExplorerMaxPly(n int) func(*Explorer) {
+	return func(e *Explorer) {
+		e.maxPly = n
+	}
+}
+
+// ExplorerMaxOccurrences is an option for NewExplorer that only counts the
+// first n games to reach each position so common opening positions don't
+// dominate datasets built from the explorer.  Later games still count for
+// the positions they reach that haven't been seen n times.  The default of
+// zero counts every game.
+func ExplorerMaxOccurrences(n int) func(*Explorer) {
+	return func(e *Explorer) {
+		e.maxGames = n
+	}
+}
+
+// NewExplorer returns an empty explorer.
+func NewExplorer(opts ...func(*Explorer)) *Explorer {
+	e := &Explorer{positions: map[[16]byte]*explorerEntry{}}
+	for _, f := range opts {
+		if f != nil {
+			f(e)
+		}
+	}
+	return e
+}
+
+// AddGame adds the game's moves and result to the explorer.  Games
+// without an outcome are ignored and false is returned.
+func (e *Explorer) AddGame(g *chess.Game) bool {
+	result := resultIndex(g.Outcome())
+	if result == -1 {
+		return false
+	}
+	positions := g.Positions()
+	moves := g.Moves()
+	if e.maxPly > 0 && len(moves) > e.maxPly {
+		moves = moves[:e.maxPly]
+	}
+	record := newExplorerGame(g)
+	// hashing is done before locking to keep the critical section short
+	keys := make([][16]byte, len(moves)+1)
+	for i := range keys {
+		keys[i] = positions[i].NormalizedHash()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := int32(len(e.records))
+	e.records = append(e.records, record)
+	e.games++
+	for i, key := range keys {
+		entry := e.entry(key)
+		if e.maxGames > 0 && entry.results[0]+entry.results[1]+entry.results[2] >= e.maxGames {
+			continue
+		}
+		entry.results[result]++
+		if i == len(moves) {
+			entry.ended = append(entry.ended, id)
+			break
+		}
+		uci := chess.UCINotation{}.Encode(positions[i], moves[i])
+		m, ok := entry.moves[uci]
+		if !ok {
+			m = &explorerMove{}
+			entry.moves[uci] = m
+		}
+		m.results[result]++
+		m.games = append(m.games, id)
+	}
+	return true
+}
+
+// AddPGN adds every game of the PGN data to the explorer and returns the
+// number of games added.
+func (e *Explorer) AddPGN(r io.Reader) (int, error) {
+	n := 0
+	scanner := chess.NewScanner(r)
+	for scanner.Scan() {
+		if e.AddGame(scanner.Next()) {
+			n++
+		}
+	}
+	return n, scanner.Err()
+}
+
+// Position returns the statistics of the position or nil if no game
+// added to the explorer reached it.
+func (e *Explorer) Position(pos *chess.Position) *PositionStats {
+	key := pos.NormalizedHash()
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	entry, ok := e.positions[key]
+	if !ok {
+		return nil
+	}
+	stats := &PositionStats{
+		White: entry.results[0],
+		Draws: entry.results[1],
+		Black: entry.results[2],
+		Moves: []MoveStats{},
+	}
+	for uci, m := range entry.moves {
+		stats.Moves = append(stats.Moves, MoveStats{Move: uci, White: m.results[0], Draws: m.results[1], Black: m.results[2]})
+	}
+	sort.Slice(stats.Moves, func(i, j int) bool {
+		return lessMoveStats(stats.Moves[i], stats.Moves[j])
+	})
+	return stats
+}
+
+// Games returns the number of games added to the explorer.
+func (e *Explorer) Games() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.games
+}
+
+// Len returns the number of distinct positions in the explorer.
+func (e *Explorer) Len() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.positions)
+}
+
+// Snapshot returns a copy of the explorer at this point in time.  Games
+// added to either explorer afterwards don't affect the other.
+func (e *Explorer) Snapshot() *Explorer {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	cp := &Explorer{
+		maxPly:    e.maxPly,
+		maxGames:  e.maxGames,
+		games:     e.games,
+		positions: make(map[[16]byte]*explorerEntry, len(e.positions)),
+		// records are never modified so only the slice is copied
+		records: e.records[:len(e.records):len(e.records)],
+	}
+	for key, entry := range e.positions {
+		moves := make(map[string]*explorerMove, len(entry.moves))
+		for uci, m := range entry.moves {
+			moves[uci] = &explorerMove{results: m.results, games: append([]int32(nil), m.games...)}
+		}
+		cp.positions[key] = &explorerEntry{results: entry.results, moves: moves, ended: append([]int32(nil), entry.ended...)}
+	}
+	return cp
+}
+
+// entry returns the entry for the key creating it if needed.  The write
+// lock must be held.
+func (e *Explorer) entry(key [16]byte) *explorerEntry {
+	entry, ok := e.positions[key]
+	if !ok {
+		entry = &explorerEntry{moves: map[string]*explorerMove{}}
+		e.positions[key] = entry
+	}
+	return entry
+}
+
+// lessMoveStats orders moves by popularity and then by move.
+func lessMoveStats(a, b MoveStats) bool {
+	if a.Games() != b.Games() {
+		return a.Games() > b.Games()
+	}
+	return a.Move < b.Move
+}
+
+// resultIndex returns the index of the outcome in the results arrays or
+// -1 if the game doesn't have an outcome.
+func resultIndex(o chess.Outcome) int {
+	switch o {
+	case chess.WhiteWon:
+		return 0
+	case chess.Draw:
+		return 1
+	case chess.BlackWon:
+		return 2
+	}
+	return -1
+}
@@ -0,0 +1,286 @@
+package opening
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+// explorerMagic starts every serialized explorer.
+const explorerMagic = "CHEX"
+
+// explorerVersion is the version of the serialization format.  It is
+// incremented whenever the format changes.  Version 2 added the games'
+// details used by queries and version 3 the maximum occurrences.
+const explorerVersion = 3
+
+// WriteTo implements the io.WriterTo interface and writes the explorer in
+// a versioned binary format that can be read with ReadExplorer.  The
+// explorer can be written while games are being added to it; the data
+// written is a snapshot.
+func (e *Explorer) WriteTo(w io.Writer) (int64, error) {
+	snapshot := e.Snapshot()
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	cw.writeString(explorerMagic)
+	cw.write([]byte{explorerVersion})
+	cw.writeUvarint(uint64(snapshot.maxPly))
+	cw.writeUvarint(uint64(snapshot.maxGames))
+	cw.writeUvarint(uint64(snapshot.games))
+	cw.writeUvarint(uint64(len(snapshot.records)))
+	for _, g := range snapshot.records {
+		cw.writeExplorerGame(g)
+	}
+	cw.writeUvarint(uint64(len(snapshot.positions)))
+	keys := make([][16]byte, 0, len(snapshot.positions))
+	for key := range snapshot.positions {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	for _, key := range keys {
+		entry := snapshot.positions[key]
+		cw.write(key[:])
+		cw.writeResults(entry.results)
+		cw.writeIDs(entry.ended)
+		cw.writeUvarint(uint64(len(entry.moves)))
+		moves := make([]string, 0, len(entry.moves))
+		for uci := range entry.moves {
+			moves = append(moves, uci)
+		}
+		sort.Strings(moves)
+		for _, uci := range moves {
+			cw.write([]byte{byte(len(uci))})
+			cw.writeString(uci)
+			m := entry.moves[uci]
+			cw.writeResults(m.results)
+			cw.writeIDs(m.games)
+		}
+	}
+	if cw.err == nil {
+		cw.err = bw.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// ReadExplorer reads an explorer written by WriteTo.  Entries are decoded
+// as they are read so the data doesn't need to fit in memory twice.
+// Version 1 data is read without the games' details so Query matches no
+// games.  An error is returned if the data is malformed or was written by
+// an unsupported version.
+func ReadExplorer(r io.Reader) (*Explorer, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(explorerMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("opening: reading explorer header: %s", err)
+	}
+	if string(header[:len(explorerMagic)]) != explorerMagic {
+		return nil, errors.New("opening: data isn't a serialized explorer")
+	}
+	version := header[len(explorerMagic)]
+	if version < 1 || version > explorerVersion {
+		return nil, fmt.Errorf("opening: unsupported explorer version %d", version)
+	}
+	d := &explorerDecoder{r: br}
+	e := NewExplorer(ExplorerMaxPly(d.int()))
+	if version > 2 {
+		e.maxGames = d.int()
+	}
+	e.games = d.int()
+	if version > 1 {
+		records := d.int()
+		for i := 0; i < records && d.err == nil; i++ {
+			e.records = append(e.records, d.explorerGame())
+		}
+	}
+	count := d.int()
+	for i := 0; i < count && d.err == nil; i++ {
+		var key [16]byte
+		d.read(key[:])
+		entry := &explorerEntry{results: d.results(), moves: map[string]*explorerMove{}}
+		if version > 1 {
+			entry.ended = d.ids(len(e.records))
+		}
+		moves := d.int()
+		for j := 0; j < moves && d.err == nil; j++ {
+			uci := make([]byte, d.byte())
+			d.read(uci)
+			m := &explorerMove{results: d.results()}
+			if version > 1 {
+				m.games = d.ids(len(e.records))
+			}
+			entry.moves[string(uci)] = m
+		}
+		e.positions[key] = entry
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("opening: reading explorer: %s", d.err)
+	}
+	return e, nil
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) write(b []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *countingWriter) writeString(s string) {
+	w.write([]byte(s))
+}
+
+func (w *countingWriter) writeUvarint(v uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	w.write(buf[:binary.PutUvarint(buf, v)])
+}
+
+func (w *countingWriter) writeResults(results [3]int) {
+	for _, n := range results {
+		w.writeUvarint(uint64(n))
+	}
+}
+
+func (w *countingWriter) writeBytes(b []byte) {
+	w.writeUvarint(uint64(len(b)))
+	w.write(b)
+}
+
+// writeIDs writes the ascending game IDs as differences from the previous.
+func (w *countingWriter) writeIDs(ids []int32) {
+	w.writeUvarint(uint64(len(ids)))
+	prev := int32(0)
+	for _, id := range ids {
+		w.writeUvarint(uint64(id - prev))
+		prev = id
+	}
+}
+
+func (w *countingWriter) writeExplorerGame(g ExplorerGame) {
+	for _, s := range []string{g.Event, g.Site, g.White, g.Black, g.TimeControl.String()} {
+		w.writeBytes([]byte(s))
+	}
+	w.writeUvarint(uint64(g.WhiteElo))
+	w.writeUvarint(uint64(g.BlackElo))
+	if g.Date.IsZero() {
+		w.writeUvarint(0)
+	} else {
+		w.writeUvarint(uint64(g.Date.Year()))
+		w.writeUvarint(uint64(g.Date.Month()))
+		w.writeUvarint(uint64(g.Date.Day()))
+	}
+	w.write([]byte{byte(resultIndex(g.Outcome))})
+}
+
+// explorerDecoder reads values until the first error which is kept.
+type explorerDecoder struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (d *explorerDecoder) read(b []byte) {
+	if d.err == nil {
+		_, d.err = io.ReadFull(d.r, b)
+	}
+}
+
+func (d *explorerDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	b, err := d.r.ReadByte()
+	d.err = err
+	return b
+}
+
+func (d *explorerDecoder) int() int {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.err = err
+	return int(v)
+}
+
+func (d *explorerDecoder) results() [3]int {
+	return [3]int{d.int(), d.int(), d.int()}
+}
+
+func (d *explorerDecoder) string() string {
+	n := d.int()
+	if d.err != nil {
+		return ""
+	}
+	if n > 1<<20 {
+		d.err = fmt.Errorf("string of %d bytes is too long", n)
+		return ""
+	}
+	b := make([]byte, n)
+	d.read(b)
+	return string(b)
+}
+
+// ids reads game IDs written by writeIDs which must be below n.
+func (d *explorerDecoder) ids(n int) []int32 {
+	count := d.int()
+	if d.err == nil && count > n {
+		d.err = fmt.Errorf("%d game IDs for %d games", count, n)
+	}
+	ids := make([]int32, 0, count)
+	id := 0
+	for i := 0; i < count && d.err == nil; i++ {
+		id += d.int()
+		if id >= n {
+			d.err = fmt.Errorf("game ID %d is out of range", id)
+		}
+		ids = append(ids, int32(id))
+	}
+	return ids
+}
+
+func (d *explorerDecoder) explorerGame() ExplorerGame {
+	g := ExplorerGame{Event: d.string(), Site: d.string(), White: d.string(), Black: d.string()}
+	if tc := d.string(); tc != "" && d.err == nil {
+		t, err := chess.ParseTimeControl(tc)
+		if err != nil {
+			d.err = err
+		}
+		g.TimeControl = t
+	}
+	g.WhiteElo = d.int()
+	g.BlackElo = d.int()
+	if year := d.int(); year != 0 {
+		month := d.int()
+		day := d.int()
+		g.Date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	switch d.byte() {
+	case 0:
+		g.Outcome = chess.WhiteWon
+	case 1:
+		g.Outcome = chess.Draw
+	case 2:
+		g.Outcome = chess.BlackWon
+	default:
+		if d.err == nil {
+			d.err = errors.New("invalid game outcome")
+		}
+	}
+	return g
+}
@@ -0,0 +1,59 @@
+package opening_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/opening"
+)
+
+func TestExplorerWriteRead(t *testing.T) {
+	e := opening.NewExplorer(opening.ExplorerMaxPly(10))
+	if _, err := e.AddPGN(strings.NewReader(explorerPGN)); err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	n, err := e.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("expected %d bytes written but got %d", buf.Len(), n)
+	}
+	data := buf.Bytes()
+	loaded, err := opening.ReadExplorer(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Games() != e.Games() || loaded.Len() != e.Len() {
+		t.Fatalf("expected %d games and %d positions but got %d and %d", e.Games(), e.Len(), loaded.Games(), loaded.Len())
+	}
+	expected := e.Position(chess.StartingPosition())
+	actual := loaded.Position(chess.StartingPosition())
+	if actual == nil || len(actual.Moves) != len(expected.Moves) || actual.Moves[0] != expected.Moves[0] {
+		t.Fatalf("expected %+v but got %+v", expected, actual)
+	}
+
+	// writing is deterministic
+	again := &bytes.Buffer{}
+	if _, err := loaded.WriteTo(again); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again.Bytes()) {
+		t.Fatal("expected the loaded explorer to serialize identically")
+	}
+
+	bad := append([]byte(nil), data...)
+	bad[4] = 99
+	if _, err := opening.ReadExplorer(bytes.NewReader(bad)); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected a version error but got %v", err)
+	}
+	if _, err := opening.ReadExplorer(bytes.NewReader(data[:len(data)-3])); err == nil {
+		t.Fatal("expected an error reading truncated data")
+	}
+	if _, err := opening.ReadExplorer(strings.NewReader("not an explorer")); err == nil {
+		t.Fatal("expected an error reading invalid data")
+	}
+}
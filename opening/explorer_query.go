@@ -0,0 +1,214 @@
+package opening
+
+import (
+	"sort"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+// An ExplorerGame describes a game added to an explorer from its tag
+// pairs.  Unknown values are left empty.
+type ExplorerGame struct {
+	Event       string
+	Site        string
+	White       string
+	Black       string
+	WhiteElo    int
+	BlackElo    int
+	Date        time.Time
+	TimeControl chess.TimeControl
+	Outcome     chess.Outcome
+}
+
+func newExplorerGame(g *chess.Game) ExplorerGame {
+	record := ExplorerGame{Outcome: g.Outcome()}
+	if tag := g.GetTagPair("Event"); tag != nil {
+		record.Event = tag.Value
+	}
+	if tag := g.GetTagPair("Site"); tag != nil {
+		record.Site = tag.Value
+	}
+	if tag := g.GetTagPair("White"); tag != nil {
+		record.White = tag.Value
+	}
+	if tag := g.GetTagPair("Black"); tag != nil {
+		record.Black = tag.Value
+	}
+	record.WhiteElo, _ = g.WhiteElo()
+	record.BlackElo, _ = g.BlackElo()
+	record.Date, _ = g.Date()
+	record.TimeControl, _ = g.TimeControl()
+	return record
+}
+
+// Rating returns the average rating of the players, the rating of the
+// only rated player or zero if neither is rated.
+func (g ExplorerGame) Rating() int {
+	switch {
+	case g.WhiteElo > 0 && g.BlackElo > 0:
+		return (g.WhiteElo + g.BlackElo) / 2
+	case g.WhiteElo > 0:
+		return g.WhiteElo
+	}
+	return g.BlackElo
+}
+
+// ExplorerQuery filters the games of an explorer.  The zero value matches
+// every game.
+type ExplorerQuery struct {
+	// MinRating and MaxRating bound the game's rating inclusively.  Zero
+	// leaves a bound open.  Unrated games don't match a bound.
+	MinRating int
+	MaxRating int
+	// Speeds are the speeds of the games' time controls to match.  No
+	// speeds matches any.
+	Speeds []chess.Speed
+	// Since and Until bound the game's date inclusively.  The zero time
+	// leaves a bound open.  Games without a date don't match a bound.
+	Since time.Time
+	Until time.Time
+	// Samples is the number of sample games returned for each move.
+	Samples int
+}
+
+func (q ExplorerQuery) matches(g *ExplorerGame) bool {
+	rating := g.Rating()
+	if q.MinRating > 0 && (rating == 0 || rating < q.MinRating) {
+		return false
+	}
+	if q.MaxRating > 0 && (rating == 0 || rating > q.MaxRating) {
+		return false
+	}
+	if len(q.Speeds) > 0 {
+		speed := g.TimeControl.Speed()
+		found := false
+		for _, s := range q.Speeds {
+			found = found || s == speed
+		}
+		if !found {
+			return false
+		}
+	}
+	if !q.Since.IsZero() && (g.Date.IsZero() || g.Date.Before(q.Since)) {
+		return false
+	}
+	if !q.Until.IsZero() && (g.Date.IsZero() || g.Date.After(q.Until)) {
+		return false
+	}
+	return true
+}
+
+// ExplorerResult is the result of an explorer query for a position.
+type ExplorerResult struct {
+	White int
+	Draws int
+	Black int
+	// Moves are the moves played from the position ordered by popularity.
+	Moves []ExplorerMove
+}
+
+// Games returns the number of matching games that reached the position.
+func (r *ExplorerResult) Games() int {
+	return r.White + r.Draws + r.Black
+}
+
+// ExplorerMove is the result of an explorer query for a move.
+type ExplorerMove struct {
+	MoveStats
+	// Score is the score from 0 to 1 of the side that played the move.
+	Score float64
+	// Rating is the average rating of the rated games or zero if none were.
+	Rating int
+	// Samples are games that played the move, highest rated and then
+	// most recent first.
+	Samples []ExplorerGame
+}
+
+// Query returns the statistics of the games matching the query that
+// reached the position or nil if no game added to the explorer reached
+// it.  Explorers read from version 1 data have no game details and so
+// match no games.
+func (e *Explorer) Query(pos *chess.Position, q ExplorerQuery) *ExplorerResult {
+	key := pos.NormalizedHash()
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	entry, ok := e.positions[key]
+	if !ok {
+		return nil
+	}
+	result := &ExplorerResult{Moves: []ExplorerMove{}}
+	for _, id := range entry.ended {
+		if g := &e.records[id]; q.matches(g) {
+			addOutcome(&result.White, &result.Draws, &result.Black, g.Outcome)
+		}
+	}
+	for uci, m := range entry.moves {
+		move := ExplorerMove{MoveStats: MoveStats{Move: uci}}
+		ratings, rated := 0, 0
+		samples := []int32{}
+		for _, id := range m.games {
+			g := &e.records[id]
+			if !q.matches(g) {
+				continue
+			}
+			addOutcome(&move.White, &move.Draws, &move.Black, g.Outcome)
+			if r := g.Rating(); r > 0 {
+				ratings += r
+				rated++
+			}
+			samples = append(samples, id)
+		}
+		if move.Games() == 0 {
+			continue
+		}
+		wins := move.White
+		if pos.Turn() == chess.Black {
+			wins = move.Black
+		}
+		move.Score = (float64(wins) + float64(move.Draws)/2) / float64(move.Games())
+		if rated > 0 {
+			move.Rating = ratings / rated
+		}
+		move.Samples = e.samples(samples, q.Samples)
+		result.White += move.White
+		result.Draws += move.Draws
+		result.Black += move.Black
+		result.Moves = append(result.Moves, move)
+	}
+	sort.Slice(result.Moves, func(i, j int) bool {
+		return lessMoveStats(result.Moves[i].MoveStats, result.Moves[j].MoveStats)
+	})
+	return result
+}
+
+// samples returns up to n of the games highest rated and then most recent
+// first.  The read lock must be held.
+func (e *Explorer) samples(ids []int32, n int) []ExplorerGame {
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := &e.records[ids[i]], &e.records[ids[j]]
+		if a.Rating() != b.Rating() {
+			return a.Rating() > b.Rating()
+		}
+		return a.Date.After(b.Date)
+	})
+	if n > len(ids) {
+		n = len(ids)
+	}
+	games := []ExplorerGame{}
+	for _, id := range ids[:n] {
+		games = append(games, e.records[id])
+	}
+	return games
+}
+
+func addOutcome(white, draws, black *int, o chess.Outcome) {
+	switch o {
+	case chess.WhiteWon:
+		*white++
+	case chess.Draw:
+		*draws++
+	case chess.BlackWon:
+		*black++
+	}
+}
@@ -0,0 +1,117 @@
+package opening_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/opening"
+)
+
+const explorerQueryPGN = `[White "A"]
+[Black "B"]
+[WhiteElo "2600"]
+[BlackElo "2500"]
+[Date "2020.05.01"]
+[TimeControl "180+2"]
+
+1. e4 e5 1-0
+
+[White "C"]
+[Black "D"]
+[WhiteElo "1500"]
+[BlackElo "1400"]
+[Date "2021.01.??"]
+[TimeControl "600"]
+
+1. e4 c5 0-1
+
+[White "E"]
+[Black "F"]
+[WhiteElo "2200"]
+[Date "2021.06.15"]
+[TimeControl "60"]
+
+1. d4 d5 1/2-1/2
+
+[White "G"]
+[Black "H"]
+
+1. e4 e5 0-1
+`
+
+func TestExplorerQuery(t *testing.T) {
+	e := opening.NewExplorer()
+	if _, err := e.AddPGN(strings.NewReader(explorerQueryPGN)); err != nil {
+		t.Fatal(err)
+	}
+	start := chess.StartingPosition()
+
+	all := e.Query(start, opening.ExplorerQuery{Samples: 2})
+	if all.Games() != 4 || len(all.Moves) != 2 {
+		t.Fatalf("expected 4 games and 2 moves but got %+v", all)
+	}
+	e4 := all.Moves[0]
+	if e4.Move != "e2e4" || e4.Games() != 3 || e4.White != 1 || e4.Black != 2 {
+		t.Fatalf("unexpected e4 stats %+v", e4.MoveStats)
+	}
+	if e4.Score != 1.0/3 || e4.Rating != (2550+1450)/2 {
+		t.Fatalf("expected e4 to score 1/3 with rating 2000 but got %v and %d", e4.Score, e4.Rating)
+	}
+	if len(e4.Samples) != 2 || e4.Samples[0].White != "A" || e4.Samples[1].White != "C" {
+		t.Fatalf("expected the highest rated samples first but got %+v", e4.Samples)
+	}
+
+	rated := e.Query(start, opening.ExplorerQuery{MinRating: 2000})
+	if rated.Games() != 2 || len(rated.Moves) != 2 {
+		t.Fatalf("expected 2 games rated 2000 or more but got %+v", rated)
+	}
+	if d4 := rated.Moves[0]; d4.Move != "d2d4" || d4.Rating != 2200 || d4.Score != 0.5 {
+		t.Fatalf("unexpected d4 result %+v", d4)
+	}
+
+	blitz := e.Query(start, opening.ExplorerQuery{Speeds: []chess.Speed{chess.Blitz, chess.Rapid}})
+	if blitz.Games() != 2 || len(blitz.Moves) != 1 || blitz.Moves[0].Games() != 2 {
+		t.Fatalf("expected the blitz and rapid e4 games but got %+v", blitz)
+	}
+
+	dated := e.Query(start, opening.ExplorerQuery{
+		Since: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if dated.Games() != 1 || dated.Moves[0].Move != "e2e4" || dated.Moves[0].Black != 1 {
+		t.Fatalf("expected the January 2021 game but got %+v", dated)
+	}
+
+	g := chess.NewGame()
+	g.MoveStr("e4")
+	g.MoveStr("e5")
+	ended := e.Query(g.Position(), opening.ExplorerQuery{})
+	if ended.Games() != 2 || len(ended.Moves) != 0 {
+		t.Fatalf("expected 2 games ending after 1. e4 e5 but got %+v", ended)
+	}
+	g = chess.NewGame()
+	g.MoveStr("h4")
+	if e.Query(g.Position(), opening.ExplorerQuery{}) != nil {
+		t.Fatal("expected nil for a position no game reached")
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := e.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := opening.ReadExplorer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := loaded.Query(start, opening.ExplorerQuery{Samples: 2, MinRating: 1000})
+	if again.Games() != 3 || len(again.Moves[0].Samples) != 2 {
+		t.Fatalf("expected queries to work after reading but got %+v", again)
+	}
+	sample := again.Moves[0].Samples[0]
+	if sample.Date != time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC) || sample.TimeControl.String() != "180+2" || sample.Outcome != chess.WhiteWon {
+		t.Fatalf("expected the sample game to be read back but got %+v", sample)
+	}
+}
@@ -0,0 +1,113 @@
+package opening_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/opening"
+)
+
+const explorerPGN = `[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 1-0
+
+[Result "0-1"]
+
+1. e4 c5 2. Nf3 d6 0-1
+
+[Result "1/2-1/2"]
+
+1. Nf3 Nc6 2. e4 e5 1/2-1/2
+
+[Result "*"]
+
+1. d4 d5 *
+`
+
+func TestExplorer(t *testing.T) {
+	e := opening.NewExplorer()
+	n, err := e.AddPGN(strings.NewReader(explorerPGN))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || e.Games() != 3 {
+		t.Fatalf("expected 3 games to be added but got %d", n)
+	}
+	stats := e.Position(chess.StartingPosition())
+	if stats == nil || stats.White != 1 || stats.Draws != 1 || stats.Black != 1 {
+		t.Fatalf("unexpected starting position stats %+v", stats)
+	}
+	if len(stats.Moves) != 2 || stats.Moves[0].Move != "e2e4" || stats.Moves[0].Games() != 2 {
+		t.Fatalf("unexpected starting position moves %+v", stats.Moves)
+	}
+
+	// the third game transposes into the first
+	g := chess.NewGame()
+	for _, m := range []string{"e4", "e5", "Nf3", "Nc6"} {
+		if err := g.MoveStr(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats = e.Position(g.Position())
+	if stats == nil || stats.Games() != 2 || stats.White != 1 || stats.Draws != 1 {
+		t.Fatalf("expected transposition to share stats but got %+v", stats)
+	}
+}
+
+func TestExplorerMaxPly(t *testing.T) {
+	e := opening.NewExplorer(opening.ExplorerMaxPly(1))
+	if _, err := e.AddPGN(strings.NewReader(explorerPGN)); err != nil {
+		t.Fatal(err)
+	}
+	// starting position and the positions after 1. e4 and 1. Nf3
+	if e.Len() != 3 {
+		t.Fatalf("expected 3 positions but got %d", e.Len())
+	}
+}
+
+func TestExplorerConcurrentSnapshot(t *testing.T) {
+	e := opening.NewExplorer()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := e.AddPGN(strings.NewReader(explorerPGN)); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			e.Position(chess.StartingPosition())
+		}()
+	}
+	wg.Wait()
+	snapshot := e.Snapshot()
+	if _, err := e.AddPGN(strings.NewReader(explorerPGN)); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Games() != 24 || e.Games() != 27 {
+		t.Fatalf("expected snapshot to be unaffected by later games but got %d and %d", snapshot.Games(), e.Games())
+	}
+	if s := snapshot.Position(chess.StartingPosition()); s.Games() != 24 {
+		t.Fatalf("expected 24 games in the snapshot but got %d", s.Games())
+	}
+}
+
+func TestExplorerMaxOccurrences(t *testing.T) {
+	e := opening.NewExplorer(opening.ExplorerMaxOccurrences(2))
+	if _, err := e.AddPGN(strings.NewReader(explorerPGN)); err != nil {
+		t.Fatal(err)
+	}
+	if s := e.Position(chess.StartingPosition()); s.Games() != 2 || s.Moves[0].Move != "e2e4" || s.Moves[0].Games() != 2 {
+		t.Fatalf("expected only the first 2 games to count for the starting position but got %+v", s)
+	}
+	// the third game reaches 1. Nf3 for the first time
+	g := chess.NewGame()
+	g.MoveStr("Nf3")
+	if s := e.Position(g.Position()); s == nil || s.Games() != 1 {
+		t.Fatalf("expected the third game to count after 1. Nf3 but got %+v", s)
+	}
+}
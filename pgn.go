@@ -2,10 +2,11 @@ package chess
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -14,48 +15,76 @@ import (
 // from concatenated PGN files.  It is designed to
 // replace GamesFromPGN in order to handle very large
 // PGN database files such as https://database.lichess.org/.
+// Only the text of the current game is held in memory.
 type Scanner struct {
-	scanr *bufio.Scanner
-	game  *Game
-	err   error
+	r       *bufio.Reader
+	decoder *pgnDecoder
+	// pending is the first line of the next game
+	pending string
+	game    *Game
+	err     error
+	// games is the number of games read
+	games int
+	// errs are the errors recovered from in lenient mode
+	errs []*GameError
+	// offset and lines are the bytes and lines of the games read
+	offset int
+	lines  int
 }
 
-// NewScanner returns a new scanner.
-func NewScanner(r io.Reader) *Scanner {
-	scanr := bufio.NewScanner(r)
-	return &Scanner{scanr: scanr}
+// NewScanner returns a new scanner.  Options such as PreservePGN
+// and LenientPGN configure how each game is decoded.  Gzip and
+// bzip2 compressed input is decompressed automatically as are
+// formats added with RegisterDecompressor.
+func NewScanner(r io.Reader, opts ...func(*pgnDecoder)) *Scanner {
+	d := newPGNDecoder(opts...)
+	if d.lenient {
+		d.partial = true
+	}
+	br, err := decompress(r)
+	if err != nil {
+		br = bufio.NewReader(errReader{err: err})
+	}
+	return &Scanner{r: br, decoder: d}
 }
 
 // Scan returns false if there was an error parsing
 // a game or EOF was reached.  Running scan populates
-// data for Next() and Err().
+// data for Next() and Err().  Scanning may continue
+// after a game that couldn't be parsed.  In lenient
+// mode Scan only returns false at the end of the input
+// or on a read error.
 func (s *Scanner) Scan() bool {
-	s.err = nil
-	var sb strings.Builder
-	count := 0
+	s.game = nil
 	for {
-		scan := s.scanr.Scan()
-		if scan == false {
-			s.err = s.scanr.Err()
+		text, err := s.readGame()
+		if err != nil || text == "" {
+			s.err = err
 			return false
 		}
-		line := s.scanr.Text() + "\n"
-		if strings.TrimSpace(line) == "" {
-			count++
-		} else {
-			sb.WriteString(line)
+		s.games++
+		offset, lines := s.offset, s.lines
+		s.offset += len(text)
+		s.lines += strings.Count(text, "\n")
+		game, p, err := s.decoder.parse(text)
+		if err != nil {
+			locatePGNError(err, offset, lines)
 		}
-		if count == 2 {
-			game, err := decodePGN(sb.String())
-			if err != nil {
-				s.err = err
-				return false
-			}
-			s.game = game
-			break
+		if err != nil && !s.decoder.lenient {
+			s.err = err
+			return false
+		} else if err != nil {
+			s.errs = append(s.errs, &GameError{Game: s.games, Token: pgnErrorToken(err), Err: err})
+			continue
+		}
+		if p.skipped != nil && s.decoder.lenient {
+			locatePGNError(p.skipped, offset, lines)
+			s.errs = append(s.errs, &GameError{Game: s.games, Token: pgnErrorToken(p.skipped), Truncated: true, Err: p.skipped})
 		}
+		s.err = nil
+		s.game = game
+		return true
 	}
-	return true
 }
 
 // Next returns the game from the most recent Scan.
@@ -65,11 +94,98 @@ func (s *Scanner) Next() *Game {
 
 // Err returns an error encountered during scanning.
 // Typically this will be a PGN parsing error or an
-// io.EOF.
+// error from the reader.  Err returns nil if the
+// end of the input was reached without error.
 func (s *Scanner) Err() error {
 	return s.err
 }
 
+// Errors returns the errors of the games that were skipped or truncated
+// so far in lenient mode in the order they were found.
+func (s *Scanner) Errors() []*GameError {
+	return append([]*GameError(nil), s.errs...)
+}
+
+// readGame returns the text of the next game or an empty string at the
+// end of the input.  A game ends at a tag pair line that follows movetext
+// or at a blank line after the game's result.  Lines within comments
+// never end a game.
+func (s *Scanner) readGame() (string, error) {
+	var sb strings.Builder
+	sb.WriteString(s.pending)
+	s.pending = ""
+	inMoves := false
+	inComment := false
+	last := ""
+	for {
+		line, err := s.r.ReadString('\n')
+		if line != "" {
+			trimmed := strings.TrimSpace(line)
+			isTag := !inComment && strings.HasPrefix(trimmed, "[")
+			isEscape := !inComment && strings.HasPrefix(line, "%")
+			if isTag && inMoves {
+				s.pending = line
+				return sb.String(), nil
+			}
+			if trimmed == "" && inMoves && !inComment && isOutcome(last) {
+				sb.WriteString(line)
+				return sb.String(), nil
+			}
+			if !isTag && !isEscape && !inComment && !strings.HasPrefix(trimmed, ";") && trimmed != "" {
+				inMoves = true
+			}
+			if !isTag && !isEscape {
+				fields := strings.Fields(pgnCutLineComment(line, inComment))
+				inComment = pgnCommentOpen(line, inComment)
+				if len(fields) > 0 && !inComment {
+					last = fields[len(fields)-1]
+				}
+			}
+			sb.WriteString(line)
+		}
+		if err == io.EOF {
+			if strings.TrimSpace(sb.String()) == "" {
+				return "", nil
+			}
+			return sb.String(), nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+// pgnCommentOpen returns true if a brace comment is open at the end of
+// the movetext line given whether one was open at its start.
+func pgnCommentOpen(line string, open bool) bool {
+	for i := 0; i < len(line); i++ {
+		switch {
+		case open && line[i] == '}':
+			open = false
+		case !open && line[i] == '{':
+			open = true
+		case !open && line[i] == ';':
+			return false
+		}
+	}
+	return open
+}
+
+// pgnCutLineComment returns the movetext line up to a rest of line
+// comment given whether a brace comment was open at its start.
+func pgnCutLineComment(line string, open bool) string {
+	for i := 0; i < len(line); i++ {
+		switch {
+		case open && line[i] == '}':
+			open = false
+		case !open && line[i] == '{':
+			open = true
+		case !open && line[i] == ';':
+			return line[:i]
+		}
+	}
+	return line
+}
+
 // GamesFromPGN returns all PGN decoding games from the
 // reader.  It is designed to be used decoding multiple PGNs
 // in the same file.  An error is returned if there is an
@@ -120,15 +236,217 @@ func (a multiDecoder) Decode(pos *Position, s string) (*Move, error) {
 	return nil, fmt.Errorf(`chess: failed to decode notation text "%s" for position %s`, s, pos)
 }
 
+// PreservePGN is an option for the PGN function and NewScanner that
+// keeps the annotations of the movetext: comments, NAGs (including move
+// suffixes such as "!?") and variations.  Encoding a game decoded with
+// PreservePGN reproduces the tag order, comment placement, NAG order and
+// result of the input so that the exported PGN is semantically identical.
+// Rest of line comments starting with ; are kept as comments and encoded
+// in braces and escape lines are available from Game.EscapeLines.
+func PreservePGN(d *pgnDecoder) {
+	d.preserve = true
+}
+
+// ExactPGN is an option for the PGN function and NewScanner that keeps the
+// layout of each game's source, its whitespace, escape lines, move numbers
+// and the text of its tokens, along with its annotations as with
+// PreservePGN.  String and MarshalText write the parts of the game that
+// are unchanged as they were in the source, so an unmodified game is
+// written byte for byte and the games read by a Scanner concatenate to its
+// input.  Moves, tags and annotations added or changed since are encoded
+// as usual.  Games truncated in lenient mode don't keep their layout.
+func ExactPGN(d *pgnDecoder) {
+	d.preserve = true
+	d.exact = true
+}
+
+// EngineCommentsPGN is an option for the PGN function and NewScanner that
+// reads the engine output Arena and cutechess write in the comment after
+// each move, such as {+0.34/18 2.1s}, {-M5/20 0.5s} or {+1.05/14 3}, as
+// [%eval] and [%emt] commands so the score, depth and time are available
+// from Move.Eval and Move.MoveTime.  Scores are written from the point of
+// view of the side that moved and are converted to White's.  Text after
+// the engine output such as ", White mates" is kept.  Annotations are
+// kept as with PreservePGN.
+func EngineCommentsPGN(d *pgnDecoder) {
+	d.preserve = true
+	d.engineComments = true
+}
+
+// LocalizedPGN is an option for the PGN function and NewScanner that
+// decodes moves written with the piece letters of another language, such
+// as GermanPieceLetters, as with LocalizedNotation.  Moves in UCI and ICCF
+// notation are still decoded but English algebraic notation isn't since
+// its letters can mean other pieces.
+func LocalizedPGN(letters string) func(*pgnDecoder) {
+	return func(d *pgnDecoder) {
+		d.decoder = multiDecoder([]Decoder{LocalizedNotation{Letters: letters}, UCINotation{}, ICCFNotation{}})
+	}
+}
+
+// LenientPGN is an option for NewScanner that recovers from games that
+// can't be decoded so one malformed game doesn't end the scan of a large
+// database.  Games with invalid movetext are truncated to the moves before
+// the error and games with invalid tag pairs are skipped.  The errors are
+// available from the Scanner's Errors method.
+func LenientPGN(d *pgnDecoder) {
+	d.lenient = true
+}
+
+// A GameError describes a game that LenientPGN recovered from.
+type GameError struct {
+	// Game is the position of the game in the input starting at 1.
+	Game int
+	// Token is the text of the offending token or empty if it isn't
+	// known.
+	Token string
+	// Truncated is true if the game was kept with the moves before the
+	// error and false if the game was skipped.
+	Truncated bool
+	// Err is the error that decoding the game returned.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *GameError) Error() string {
+	action := "skipped"
+	if e.Truncated {
+		action = "truncated"
+	}
+	if e.Token == "" {
+		return fmt.Sprintf("chess: %s game %d: %s", action, e.Game, e.Err)
+	}
+	return fmt.Sprintf("chess: %s game %d at %q: %s", action, e.Game, e.Token, e.Err)
+}
+
+// A PGNError is an error decoding PGN text with the location of the
+// offending token.  Errors from a Scanner are located in the scanned
+// input rather than the game's text.
+type PGNError struct {
+	// Line is the line of the token starting at 1.
+	Line int
+	// Column is the column of the token in bytes starting at 1.
+	Column int
+	// Offset is the byte offset of the token starting at 0.
+	Offset int
+	// Token is the text of the token.  The text of strings and comments
+	// excludes their delimiters.
+	Token string
+	// Message describes the error.
+	Message string
+	// Err is the underlying error such as a move decoding error or nil.
+	Err error
+}
+
+func newPGNError(t pgnToken, err error, format string, a ...interface{}) *PGNError {
+	return &PGNError{
+		Line:    t.line,
+		Column:  t.col,
+		Offset:  t.offset,
+		Token:   t.text,
+		Message: fmt.Sprintf(format, a...),
+		Err:     err,
+	}
+}
+
+// Error implements the error interface.
+func (e *PGNError) Error() string {
+	s := fmt.Sprintf("chess: pgn %s on line %d column %d", e.Message, e.Line, e.Column)
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Unwrap returns the underlying error.
+func (e *PGNError) Unwrap() error {
+	return e.Err
+}
+
+// locatePGNError moves the location of a PGNError in a game's text that
+// starts at the offset and after the lines of the input.
+func locatePGNError(err error, offset, lines int) {
+	var pgnErr *PGNError
+	if errors.As(err, &pgnErr) {
+		pgnErr.Offset += offset
+		pgnErr.Line += lines
+	}
+}
+
+// pgnErrorToken returns the token of a PGNError or an empty string.
+func pgnErrorToken(err error) string {
+	var pgnErr *PGNError
+	if errors.As(err, &pgnErr) {
+		return pgnErr.Token
+	}
+	return ""
+}
+
+// pgnDecoder holds the options used to decode PGN text.
+type pgnDecoder struct {
+	preserve bool
+	partial  bool
+	lenient  bool
+	exact    bool
+	// engineComments converts Arena and cutechess engine output
+	engineComments bool
+	// decoder decodes moves if it isn't nil
+	decoder Decoder
+}
+
+func newPGNDecoder(opts ...func(*pgnDecoder)) *pgnDecoder {
+	d := &pgnDecoder{}
+	for _, f := range opts {
+		if f != nil {
+			f(d)
+		}
+	}
+	return d
+}
+
 func decodePGN(pgn string) (*Game, error) {
-	tagPairs := getTagPairs(pgn)
-	moveStrs, outcome := moveList(pgn)
+	return newPGNDecoder().decode(pgn)
+}
+
+// decodePartialPGN decodes a game that may still be being written.  Moves
+// are applied until one fails to decode, so a game with a truncated final
+// move (or comment) decodes to its legal prefix without an outcome.
+func decodePartialPGN(pgn string) (*Game, error) {
+	d := newPGNDecoder()
+	d.partial = true
+	return d.decode(pgn)
+}
+
+var (
+	pgnMoveDecoder = multiDecoder([]Decoder{AlgebraicNotation{}, LongAlgebraicNotation{}, UCINotation{}, ICCFNotation{}})
+)
+
+func (d *pgnDecoder) decode(pgn string) (*Game, error) {
+	g, _, err := d.parse(pgn)
+	return g, err
+}
+
+// parse decodes the game and returns the parser so that details such as
+// the error skipped in partial mode are available.
+func (d *pgnDecoder) parse(pgn string) (*Game, *pgnParser, error) {
+	tokens, escapes, err := lexPGNEscapes(pgn)
+	if err != nil && !d.partial {
+		return nil, nil, err
+	}
+	p := &pgnParser{d: d, src: pgn, tokens: tokens, skipped: err}
+	if d.exact {
+		p.layout = &pgnLayout{}
+	}
+	tagPairs, err := p.parseTagPairs()
+	if err != nil {
+		return nil, p, err
+	}
 	gameFuncs := []func(*Game){}
 	for _, tp := range tagPairs {
 		if strings.ToLower(tp.Key) == "fen" {
 			fenFunc, err := FEN(tp.Value)
 			if err != nil {
-				return nil, fmt.Errorf("chess: pgn decode error %s on tag %s", err.Error(), tp.Key)
+				return nil, p, newPGNError(p.tagValueToken(tp.Key), err, "invalid %s tag", tp.Key)
 			}
 			gameFuncs = append(gameFuncs, fenFunc)
 			break
@@ -137,99 +455,421 @@ func decodePGN(pgn string) (*Game, error) {
 	gameFuncs = append(gameFuncs, TagPairs(tagPairs))
 	g := NewGame(gameFuncs...)
 	g.ignoreAutomaticDraws = true
-	decoder := multiDecoder([]Decoder{AlgebraicNotation{}, LongAlgebraicNotation{}, UCINotation{}})
-	for _, alg := range moveStrs {
-		m, err := decoder.Decode(g.Position(), alg)
-		if err != nil {
-			return nil, fmt.Errorf("chess: pgn decode error %s on move %d", err.Error(), g.Position().moveCount)
-		}
-		if err := g.Move(m); err != nil {
-			return nil, fmt.Errorf("chess: pgn invalid move error %s on move %d", err.Error(), g.Position().moveCount)
-		}
+	p.annotator = g.Annotator()
+	_, comments, outcome, err := p.parseLine(g.pos, g)
+	if err != nil && !d.partial {
+		return nil, nil, err
+	} else if err != nil {
+		p.skipped = err
+	}
+	g.comments = comments
+	if d.preserve {
+		g.escapes = escapes
 	}
 	g.outcome = outcome
-	return g, nil
+	if outcome == "" {
+		g.outcome = NoOutcome
+	}
+	if d.exact && p.skipped == nil {
+		p.layout.trailing = pgn[p.end:]
+		g.layout = p.layout
+	}
+	return g, p, nil
 }
 
-func encodePGN(g *Game) string {
-	s := ""
-	for _, tag := range g.tagPairs {
-		s += fmt.Sprintf("[%s \"%s\"]\n", tag.Key, tag.Value)
-	}
-	s += "\n"
-	for i, move := range g.moves {
-		pos := g.positions[i]
-		txt := g.notation.Encode(pos, move)
-		if i%2 == 0 {
-			s += fmt.Sprintf("%d.%s", (i/2)+1, txt)
-		} else {
-			s += fmt.Sprintf(" %s ", txt)
-		}
-	}
-	s += " " + string(g.outcome)
-	return s
+type pgnParser struct {
+	d         *pgnDecoder
+	tokens    []pgnToken
+	i         int
+	annotator string
+	// skipped is the error that ended a partial decode
+	skipped error
+	// misnumbered counts main line move numbers that don't match
+	// the position
+	misnumbered int
+	// src is the text of the game and layout its lexical form, which is
+	// only recorded with ExactPGN
+	src    string
+	layout *pgnLayout
+	// end is the offset of the end of the last piece of the layout
+	end int
 }
 
-var (
-	tagPairRegex = regexp.MustCompile(`\[(.*)\s\"(.*)\"\]`)
-)
+// piece returns the piece of the source that begins at offset start and
+// ends with the token.
+func (p *pgnParser) piece(start int, t pgnToken) pgnPiece {
+	piece := pgnPiece{before: p.src[p.end:start], text: p.src[start:t.end]}
+	p.end = t.end
+	return piece
+}
 
-func getTagPairs(pgn string) []*TagPair {
+func (p *pgnParser) parseTagPairs() ([]*TagPair, error) {
 	tagPairs := []*TagPair{}
-	matches := tagPairRegex.FindAllString(pgn, -1)
-	for _, m := range matches {
-		results := tagPairRegex.FindStringSubmatch(m)
-		if len(results) == 3 {
-			pair := &TagPair{
-				Key:   results[1],
-				Value: results[2],
-			}
-			tagPairs = append(tagPairs, pair)
+	for p.i < len(p.tokens) && p.tokens[p.i].typ == tokenTagStart {
+		if p.i+3 >= len(p.tokens) ||
+			p.tokens[p.i+1].typ != tokenSymbol ||
+			p.tokens[p.i+2].typ != tokenString ||
+			p.tokens[p.i+3].typ != tokenTagEnd {
+			return nil, newPGNError(p.tokens[p.i], nil, "invalid tag pair")
 		}
+		tp := &TagPair{
+			Key:   p.tokens[p.i+1].text,
+			Value: tagValueUnescaper.Replace(p.tokens[p.i+2].text),
+		}
+		tagPairs = append(tagPairs, tp)
+		if p.layout != nil {
+			piece := p.piece(p.tokens[p.i].offset, p.tokens[p.i+3])
+			p.layout.tags = append(p.layout.tags, pgnTagLayout{pgnPiece: piece, tag: *tp})
+		}
+		p.i += 4
 	}
-	return tagPairs
+	return tagPairs, nil
 }
 
-var (
-	moveNumRegex = regexp.MustCompile(`(?:\d+\.+)?(.*)`)
-)
+// tagValueToken returns the value token of the first tag pair with the
+// key.
+func (p *pgnParser) tagValueToken(key string) pgnToken {
+	for i := 0; i+2 < len(p.tokens) && p.tokens[i].typ == tokenTagStart; i += 4 {
+		if p.tokens[i+1].text == key {
+			return p.tokens[i+2]
+		}
+	}
+	return pgnToken{line: 1, col: 1}
+}
 
-func moveList(pgn string) ([]string, Outcome) {
-	// remove comments
-	text := removeSection("{", "}", pgn)
-	// remove variations
-	text = removeSection(`\(`, `\)`, text)
-	// remove tag pairs
-	text = removeSection(`\[`, `\]`, text)
-	// remove line breaks
-	text = strings.Replace(text, "\n", " ", -1)
-
-	list := strings.Split(text, " ")
-	filtered := []string{}
+// parseLine parses movetext from pos until the tokens are exhausted or
+// the end of a variation is reached.  If g isn't nil the moves are
+// applied to it.  The comments before the first move are returned
+// separately.
+func (p *pgnParser) parseLine(pos *Position, g *Game) ([]*Move, []Comment, Outcome, error) {
+	moves := []*Move{}
+	comments := []Comment{}
 	var outcome Outcome
-	for _, move := range list {
-		move = strings.TrimSpace(move)
-		switch move {
-		case string(NoOutcome), string(WhiteWon), string(BlackWon), string(Draw):
-			outcome = Outcome(move)
-		case "":
+	var last *Move
+	var prev *Position
+	// pre are the comments before the first move of a variation in the
+	// layout
+	var pre []pgnAnnotation
+	for ; p.i < len(p.tokens); p.i++ {
+		t := p.tokens[p.i]
+		switch t.typ {
+		case tokenPeriod:
+		case tokenAsterisk:
+			outcome = NoOutcome
+			p.recordResult(t, g)
+		case tokenSymbol:
+			if o := Outcome(t.text); o == WhiteWon || o == BlackWon || o == Draw {
+				outcome = o
+				p.recordResult(t, g)
+				continue
+			}
+			if p.moveNumber() {
+				if g != nil && t.text != strconv.Itoa(pos.moveCount) {
+					p.misnumbered++
+				}
+				continue
+			}
+			decoder := p.d.decoder
+			if decoder == nil {
+				decoder = pgnMoveDecoder
+			}
+			m, err := decoder.Decode(pos, t.text)
+			if err != nil {
+				return moves, comments, outcome, newPGNError(t, err, "invalid move %s on move %d", t.text, pos.moveCount)
+			}
+			prev = pos
+			if g != nil {
+				if err := g.Move(m); err != nil {
+					return moves, comments, outcome, newPGNError(t, err, "illegal move %s on move %d", t.text, pos.moveCount)
+				}
+				last = g.moves[len(g.moves)-1]
+				pos = g.pos
+			} else {
+				valid := moveSlice(pos.ValidMoves()).find(m)
+				if m.IsNull() && !pos.inCheck {
+					valid = m
+				}
+				if valid == nil {
+					return moves, comments, outcome, newPGNError(t, nil, "illegal move %s on move %d", t.text, pos.moveCount)
+				}
+				last = valid.copy()
+				pos = pos.Update(last)
+			}
+			moves = append(moves, last)
+			if p.layout != nil {
+				last.layout = &moveLayout{pgnPiece: p.piece(t.offset, t), pre: pre}
+				pre = nil
+			}
+		case tokenNAG, tokenSuffix:
+			if !p.d.preserve || last == nil {
+				continue
+			}
+			nag := t.text
+			if t.typ == tokenSuffix && !isMoveSuffix(nag) {
+				// symbols such as += are stored as NAGs so they can't
+				// be mistaken for part of the move when encoded
+				n, err := ParseNAG(nag)
+				if err != nil {
+					continue
+				}
+				nag = n.String()
+			}
+			last.nags = append(last.nags, nag)
+			if p.layout != nil {
+				last.layout.annotations = append(last.layout.annotations,
+					pgnAnnotation{pgnPiece: p.piece(t.offset, t), typ: tokenNAG, nag: nag})
+			}
+		case tokenComment:
+			if !p.d.preserve {
+				continue
+			}
+			text := t.text
+			if p.d.engineComments && last != nil {
+				text = engineCommentCommands(text, prev.Turn())
+			}
+			c := parseComment(text, p.annotator)
+			var a pgnAnnotation
+			if p.layout != nil {
+				a = pgnAnnotation{pgnPiece: p.piece(t.offset, t), typ: tokenComment, comment: c}
+			}
+			if last == nil {
+				comments = append(comments, c)
+				if p.layout != nil && g != nil {
+					p.layout.comments = append(p.layout.comments, a)
+				} else if p.layout != nil {
+					pre = append(pre, a)
+				}
+			} else {
+				last.comments = append(last.comments, c)
+				if p.layout != nil {
+					last.layout.annotations = append(last.layout.annotations, a)
+				}
+			}
+		case tokenVariationStart:
+			if last == nil {
+				return moves, comments, outcome, newPGNError(t, nil, "variation without a preceding move")
+			}
+			if p.layout != nil {
+				if err := p.parseVariationLayout(prev, last); err != nil {
+					return moves, comments, outcome, err
+				}
+			} else if err := p.parseVariation(prev, last); err != nil {
+				return moves, comments, outcome, err
+			}
+		case tokenVariationEnd:
+			if g != nil {
+				return moves, comments, outcome, newPGNError(t, nil, "unexpected )")
+			}
+			return moves, comments, outcome, nil
 		default:
-			results := moveNumRegex.FindStringSubmatch(move)
-			if len(results) == 2 && results[1] != "" {
-				filtered = append(filtered, results[1])
+			return moves, comments, outcome, newPGNError(t, nil, "unexpected %q", t.text)
+		}
+	}
+	return moves, comments, outcome, nil
+}
+
+// parseVariation parses the variation starting at the current "(" token
+// as an alternative to m from pos and leaves the parser on the closing
+// ")".  Variations are only kept when preserving annotations.
+func (p *pgnParser) parseVariation(pos *Position, m *Move) error {
+	start := p.tokens[p.i]
+	if !p.d.preserve {
+		depth := 0
+		for ; p.i < len(p.tokens); p.i++ {
+			switch p.tokens[p.i].typ {
+			case tokenVariationStart:
+				depth++
+			case tokenVariationEnd:
+				depth--
+				if depth == 0 {
+					return nil
+				}
 			}
 		}
+		return newPGNError(start, nil, "unterminated variation")
+	}
+	p.i++
+	moves, comments, _, err := p.parseLine(pos, nil)
+	if err != nil {
+		return err
+	}
+	if p.i >= len(p.tokens) {
+		return newPGNError(start, nil, "unterminated variation")
+	}
+	if len(moves) > 0 {
+		moves[0].annotator = p.annotator
+		if len(comments) > 0 && comments[0].Text == "" {
+			// an empty comment only attributes the variation
+			moves[0].annotator = comments[0].Annotator
+			comments = comments[1:]
+		}
+		moves[0].preComments = comments
+		m.variations = append(m.variations, moves)
 	}
-	return filtered, outcome
+	return nil
 }
 
-func removeSection(leftChar, rightChar, s string) string {
-	r := regexp.MustCompile(leftChar + ".*?" + rightChar)
-	for {
-		i := r.FindStringIndex(s)
-		if i == nil {
-			return s
+// parseVariationLayout is like parseVariation but also records the
+// layout of the variation.  The parentheses of an empty variation, which
+// isn't kept, are left in the text before the next piece.
+func (p *pgnParser) parseVariationLayout(pos *Position, m *Move) error {
+	end := p.end
+	open := p.piece(p.tokens[p.i].offset, p.tokens[p.i])
+	n := len(m.variations)
+	if err := p.parseVariation(pos, m); err != nil {
+		return err
+	}
+	if len(m.variations) == n {
+		p.end = end
+		return nil
+	}
+	l := m.variations[n][0].layout
+	l.open = open
+	l.close = p.piece(p.tokens[p.i].offset, p.tokens[p.i])
+	m.layout.annotations = append(m.layout.annotations, pgnAnnotation{typ: tokenVariationStart})
+	return nil
+}
+
+// recordResult records the result token of the game in the layout.
+func (p *pgnParser) recordResult(t pgnToken, g *Game) {
+	if p.layout != nil && g != nil {
+		p.layout.result = p.piece(t.offset, t)
+	}
+}
+
+// moveNumber returns true if the current token is a move number, which
+// is followed by a period, rather than a move in ICCF notation.
+func (p *pgnParser) moveNumber() bool {
+	t := p.tokens[p.i]
+	return isMoveNumber(t.text) && p.i+1 < len(p.tokens) && p.tokens[p.i+1].typ == tokenPeriod
+}
+
+func isMoveNumber(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPGNGames splits concatenated PGN text into the text of each game.
+// A game begins at a tag pair line that follows movetext.
+func splitPGNGames(pgn string) []string {
+	games := []string{}
+	var sb strings.Builder
+	inMoves := false
+	for _, line := range strings.SplitAfter(pgn, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && inMoves {
+			games = append(games, sb.String())
+			sb.Reset()
+			inMoves = false
+		}
+		if trimmed != "" && strings.IndexByte("[%;", trimmed[0]) == -1 {
+			inMoves = true
 		}
-		s = s[0:i[0]] + s[i[1]:]
+		sb.WriteString(line)
+	}
+	if strings.TrimSpace(sb.String()) != "" {
+		games = append(games, sb.String())
+	}
+	return games
+}
+
+func encodePGN(g *Game) string {
+	s := ""
+	for _, tag := range g.tagPairs {
+		s += tag.String() + "\n"
+	}
+	if s != "" {
+		s += "\n"
+	}
+	return s + g.EncodeMoves(g.notation)
+}
+
+// EncodeMoves returns the game's movetext, its moves and annotations
+// followed by its result, with the moves encoded in the notation instead
+// of the game's notation.  For example UCINotation encodes the moves for
+// tools that consume coordinate moves.  A nil notation uses the game's
+// notation as with EncodeMove.
+func (g *Game) EncodeMoves(n Notation) string {
+	if n == nil {
+		n = g.notation
+	}
+	annotator := g.Annotator()
+	s := encodeComments("", g.comments, annotator)
+	s += encodeMoveText(n, g.positions, g.moves, annotator)
+	return s + " " + string(g.outcome)
+}
+
+// encodeMoveText encodes the moves where positions[i] is the position
+// before moves[i].  A move number is repeated for black's move when it
+// follows annotations.  Annotations by someone other than the game's
+// annotator are attributed with an annotator command.
+func encodeMoveText(n Notation, positions []*Position, moves []*Move, annotator string) string {
+	s := ""
+	resume := false
+	for i, move := range moves {
+		pos := positions[i]
+		txt := n.Encode(pos, move)
+		if len(move.preComments) > 0 {
+			s = encodeComments(s, move.preComments, annotator)
+			resume = true
+		}
+		if pos.turn == White {
+			s = pgnSeparate(s) + fmt.Sprintf("%d.%s", pos.moveCount, txt)
+		} else if i == 0 || resume {
+			s = pgnSeparate(s) + fmt.Sprintf("%d...%s ", pos.moveCount, txt)
+		} else {
+			s += fmt.Sprintf(" %s ", txt)
+		}
+		resume = false
+		nags := notationNAGs(n, move)
+		if len(nags) == 0 && len(move.comments) == 0 && len(move.variations) == 0 {
+			continue
+		}
+		s = strings.TrimRight(s, " ")
+		for _, nag := range nags {
+			if strings.HasPrefix(nag, "$") {
+				s += " "
+			}
+			s += nag
+		}
+		s = strings.TrimRight(encodeComments(s, move.comments, annotator), " ")
+		for _, variation := range move.variations {
+			s += " (" + encodeVariation(n, pos, variation, annotator) + ")"
+		}
+		resume = true
+	}
+	return s
+}
+
+func encodeVariation(n Notation, pos *Position, moves []*Move, annotator string) string {
+	s := ""
+	if moves[0].annotator != annotator {
+		s = encodeComments(s, []Comment{{Annotator: moves[0].annotator}}, annotator)
+	}
+	positions := []*Position{}
+	for _, m := range moves {
+		positions = append(positions, pos)
+		pos = pos.Update(m)
+	}
+	return strings.TrimSpace(s + encodeMoveText(n, positions, moves, annotator))
+}
+
+// encodeComments appends the comments to s followed by a space.
+func encodeComments(s string, comments []Comment, annotator string) string {
+	for _, c := range comments {
+		s = pgnSeparate(s) + "{" + c.encode(annotator) + "} "
+	}
+	return s
+}
+
+// pgnSeparate adds a space to s if it doesn't end with one.
+func pgnSeparate(s string) string {
+	if s == "" || strings.HasSuffix(s, " ") {
+		return s
 	}
+	return s + " "
 }
@@ -0,0 +1,85 @@
+package chess
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// engineCommentCommands returns the text of a comment with the engine
+// output Arena and cutechess write after a move replaced by [%eval] and
+// [%emt] commands.  The score is converted from the point of view of the
+// side that moved to White's.  Other comments are returned unchanged.
+func engineCommentCommands(text string, mover Color) string {
+	e, d, hasTime, rest, ok := parseEngineComment(text)
+	if !ok {
+		return text
+	}
+	if mover == Black {
+		e.Centipawns, e.Mate = -e.Centipawns, -e.Mate
+	}
+	s := "[%eval " + e.String() + "]"
+	if hasTime {
+		s += " [%emt " + formatClock(d) + "]"
+	}
+	if rest != "" {
+		s += " " + rest
+	}
+	return s
+}
+
+// parseEngineComment parses engine output such as +0.34/18 2.1s,
+// -M5/20 0.5s, White mates or +1.05/14 3, which is the score in pawns or
+// moves to mate, the depth, the time in seconds and any other text after
+// a comma.  The score is from the point of view of the side that moved.
+func parseEngineComment(text string) (e Eval, d time.Duration, hasTime bool, rest string, ok bool) {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, ','); i != -1 {
+		rest = strings.TrimSpace(text[i+1:])
+		text = text[:i]
+	}
+	fields := strings.Fields(text)
+	if len(fields) < 1 || len(fields) > 2 {
+		return Eval{}, 0, false, "", false
+	}
+	parts := strings.Split(fields[0], "/")
+	if len(parts) != 2 {
+		return Eval{}, 0, false, "", false
+	}
+	score := parts[0]
+	sign := 1
+	if strings.HasPrefix(score, "+") || strings.HasPrefix(score, "-") {
+		if score[0] == '-' {
+			sign = -1
+		}
+		score = score[1:]
+	}
+	if strings.HasPrefix(score, "M") {
+		mate, err := strconv.Atoi(score[1:])
+		if err != nil || mate < 0 {
+			return Eval{}, 0, false, "", false
+		}
+		e.Mate = sign * mate
+	} else {
+		pawns, err := strconv.ParseFloat(score, 64)
+		if err != nil || score == "" || !isDigit(score[0]) {
+			return Eval{}, 0, false, "", false
+		}
+		e.Centipawns = sign * int(math.Round(pawns*100))
+	}
+	depth, err := strconv.Atoi(parts[1])
+	if err != nil || depth < 0 {
+		return Eval{}, 0, false, "", false
+	}
+	e.Depth = depth
+	if len(fields) == 2 {
+		seconds, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "s"), 64)
+		if err != nil || seconds < 0 || !isDigit(fields[1][0]) {
+			return Eval{}, 0, false, "", false
+		}
+		d = time.Duration(math.Round(seconds * float64(time.Second)))
+		hasTime = true
+	}
+	return e, d, hasTime, rest, true
+}
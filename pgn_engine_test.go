@@ -0,0 +1,81 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEngineComment(t *testing.T) {
+	tests := []struct {
+		text    string
+		e       Eval
+		d       time.Duration
+		hasTime bool
+		rest    string
+		ok      bool
+	}{
+		{"+0.34/18 2.1s", Eval{Centipawns: 34, Depth: 18}, 2100 * time.Millisecond, true, "", true},
+		{"-1.05/14 3", Eval{Centipawns: -105, Depth: 14}, 3 * time.Second, true, "", true},
+		{"+0.00/0", Eval{}, 0, false, "", true},
+		{"-M5/20 0.5s, White mates", Eval{Mate: -5, Depth: 20}, 500 * time.Millisecond, true, "White mates", true},
+		{"book", Eval{}, 0, false, "", false},
+		{"a good move", Eval{}, 0, false, "", false},
+		{"+inf/3", Eval{}, 0, false, "", false},
+		{"0.3/x 1s", Eval{}, 0, false, "", false},
+	}
+	for _, test := range tests {
+		e, d, hasTime, rest, ok := parseEngineComment(test.text)
+		if ok != test.ok || e != test.e || d != test.d || hasTime != test.hasTime || rest != test.rest {
+			t.Fatalf("expected %q to parse to %v %v %v %q %v but got %v %v %v %q %v", test.text,
+				test.e, test.d, test.hasTime, test.rest, test.ok, e, d, hasTime, rest, ok)
+		}
+	}
+}
+
+func TestEngineCommentsPGN(t *testing.T) {
+	pgn := `[Event "cutechess"]
+
+1. e4 {book} e5 {-0.34/18 2.1s} 2. Nf3 {+0.40/20 1.5s} (2. Qh5 {+0.10/12 1s}) Nc6 {+0.25/19 2s, a comment} *`
+	opt, err := PGN(strings.NewReader(pgn), EngineCommentsPGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	moves := g.Moves()
+	if _, ok := moves[0].Eval(); ok {
+		t.Fatal("expected no eval for a book move")
+	}
+	if c := moves[0].Comments(); len(c) != 1 || c[0].Text != "book" {
+		t.Fatalf("expected the book comment to be kept but got %v", c)
+	}
+	tests := []struct {
+		e Eval
+		d time.Duration
+	}{
+		{Eval{Centipawns: 34, Depth: 18}, 2100 * time.Millisecond},
+		{Eval{Centipawns: 40, Depth: 20}, 1500 * time.Millisecond},
+		{Eval{Centipawns: -25, Depth: 19}, 2 * time.Second},
+	}
+	for i, test := range tests {
+		m := moves[i+1]
+		if e, ok := m.Eval(); !ok || e != test.e {
+			t.Fatalf("expected ply %d to have eval %v but got %v", i+2, test.e, e)
+		}
+		if d, ok := m.MoveTime(); !ok || d != test.d {
+			t.Fatalf("expected ply %d to take %v but got %v", i+2, test.d, d)
+		}
+	}
+	if e, ok := moves[2].Variations()[0][0].Eval(); !ok || e.Centipawns != 10 {
+		t.Fatalf("expected the variation to have eval 0.10 but got %v", e)
+	}
+	if s := g.String(); !strings.Contains(s, "Nc6 {[%eval -0.25,19] [%emt 0:00:02] a comment}") {
+		t.Fatalf("expected the engine output to be encoded as commands but got %s", s)
+	}
+	if err := g.SetMoveTime(1, 4*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := moves[0].MoveTime(); !ok || d != 4*time.Second {
+		t.Fatalf("expected the move time to be set but got %v", d)
+	}
+}
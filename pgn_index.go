@@ -0,0 +1,96 @@
+package chess
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// An IndexedGame is the location of a game in PGN text and its tag
+// pairs.  It is used to load specific games from large files lazily.
+type IndexedGame struct {
+	// Offset is the byte offset of the start of the game.
+	Offset int64
+	// Length is the number of bytes of the game's text.
+	Length int64
+	// TagPairs are the game's tag pairs.
+	TagPairs []*TagPair
+}
+
+// GetTagPair returns the tag pair for the given key or nil if it isn't
+// present.
+func (g *IndexedGame) GetTagPair(k string) *TagPair {
+	for _, tag := range g.TagPairs {
+		if tag.Key == k {
+			return tag
+		}
+	}
+	return nil
+}
+
+// Read decodes the indexed game from r which must hold the indexed text.
+// Options such as PreservePGN configure decoding.
+func (g *IndexedGame) Read(r io.ReaderAt, opts ...func(*pgnDecoder)) (*Game, error) {
+	b := make([]byte, g.Length)
+	if _, err := r.ReadAt(b, g.Offset); err != nil {
+		return nil, fmt.Errorf("chess: reading game at offset %d: %s", g.Offset, err)
+	}
+	return newPGNDecoder(opts...).decode(string(b))
+}
+
+// IndexPGN returns the location and tag pairs of every game in the PGN
+// text without decoding the movetext.  Games are split as with Scanner.
+// An error is returned if reading fails or a game's tag pairs can't be
+// decoded.
+func IndexPGN(r io.Reader) ([]*IndexedGame, error) {
+	s := &Scanner{r: bufio.NewReader(r)}
+	games := []*IndexedGame{}
+	for {
+		text, err := s.readGame()
+		if err != nil {
+			return nil, err
+		}
+		if text == "" {
+			return games, nil
+		}
+		offset, lines := s.offset, s.lines
+		s.offset += len(text)
+		s.lines += strings.Count(text, "\n")
+		trimmed := strings.TrimLeft(text, " \t\r\n")
+		skipped := len(text) - len(trimmed)
+		tagPairs, err := parsePGNTagSection(trimmed)
+		if err != nil {
+			locatePGNError(err, offset+skipped, lines+strings.Count(text[:skipped], "\n"))
+			return nil, err
+		}
+		games = append(games, &IndexedGame{
+			Offset:   int64(offset + skipped),
+			Length:   int64(len(strings.TrimRight(trimmed, " \t\r\n"))),
+			TagPairs: tagPairs,
+		})
+	}
+}
+
+// parsePGNTagSection returns the tag pairs of the game's text by lexing
+// only the lines before the movetext.
+func parsePGNTagSection(text string) ([]*TagPair, error) {
+	end := 0
+	for end < len(text) {
+		next := strings.IndexByte(text[end:], '\n')
+		line := text[end:]
+		if next != -1 {
+			line = text[end : end+next+1]
+		}
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "[") {
+			break
+		}
+		end += len(line)
+	}
+	tokens, err := lexPGN(text[:end])
+	if err != nil {
+		return nil, err
+	}
+	p := &pgnParser{d: newPGNDecoder(), tokens: tokens}
+	return p.parseTagPairs()
+}
@@ -0,0 +1,47 @@
+package chess
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIndexPGN(t *testing.T) {
+	pgn := "\n[Event \"One\"]\n[White \"A\"]\n\n1. e4 e5 1-0\n\n" +
+		"[Event \"Two\"]\n\n1. d4 {a comment\n\n[with a bracket]} d5 *\n" +
+		"[Event \"Three\"]\n\n1. c4 0-1"
+	index, err := IndexPGN(strings.NewReader(pgn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index) != 3 {
+		t.Fatalf("expected 3 games but got %d", len(index))
+	}
+	events := []string{"One", "Two", "Three"}
+	moves := []int{2, 2, 1}
+	for i, entry := range index {
+		text := pgn[entry.Offset : entry.Offset+entry.Length]
+		if !strings.HasPrefix(text, "[Event") {
+			t.Fatalf("expected game %d to start with its tags but got %q", i+1, text)
+		}
+		if tag := entry.GetTagPair("Event"); tag == nil || tag.Value != events[i] {
+			t.Fatalf("expected event %s but got %v", events[i], tag)
+		}
+		g, err := entry.Read(strings.NewReader(pgn))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(g.Moves()) != moves[i] {
+			t.Fatalf("expected game %d to have %d moves but got %d", i+1, moves[i], len(g.Moves()))
+		}
+	}
+	if w := index[0].GetTagPair("White"); w == nil || w.Value != "A" {
+		t.Fatal("expected the White tag of the first game")
+	}
+
+	_, err = IndexPGN(strings.NewReader("[Event \"One\"]\n\n1. e4 *\n\n[Event One]\n\n1. d4 *\n"))
+	var pgnErr *PGNError
+	if !errors.As(err, &pgnErr) || pgnErr.Line != 5 {
+		t.Fatalf("expected a tag pair error on line 5 but got %v", err)
+	}
+}
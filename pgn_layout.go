@@ -0,0 +1,267 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pgnLayout is the lexical form of a game decoded with ExactPGN.  The
+// parts of the game that are still present when it's encoded are written
+// as they were in the source and the rest is encoded as usual.
+type pgnLayout struct {
+	tags []pgnTagLayout
+	// comments are the comments before the first move
+	comments []pgnAnnotation
+	// result is empty if the source doesn't have a result
+	result pgnPiece
+	// trailing is the text after the last token
+	trailing string
+}
+
+// pgnPiece is the source text of a token or group of tokens such as a tag
+// pair.  before is the text between the previous piece and this one:
+// whitespace, escape lines and tokens that aren't kept such as move
+// numbers.
+type pgnPiece struct {
+	before string
+	text   string
+}
+
+type pgnTagLayout struct {
+	pgnPiece
+	tag TagPair
+}
+
+// pgnAnnotation is a NAG, comment or variation in the source.  A
+// variation is only a placeholder that keeps the order of the annotations
+// since its parentheses are kept by its first move.
+type pgnAnnotation struct {
+	pgnPiece
+	typ     pgnTokenType
+	nag     string
+	comment Comment
+}
+
+// moveLayout is the lexical form of a move decoded with ExactPGN.
+type moveLayout struct {
+	// the move including the move number before it
+	pgnPiece
+	// annotations are the NAGs, comments and variations after the move in
+	// source order
+	annotations []pgnAnnotation
+	// open and close are the parentheses of the variation that begins with
+	// the move and pre the comments between the open parenthesis and the
+	// move
+	open, close pgnPiece
+	pre         []pgnAnnotation
+}
+
+// pgnLayoutWriter encodes a game with its layout.
+type pgnLayoutWriter struct {
+	sb        strings.Builder
+	notation  Notation
+	annotator string
+	// sep separates the next encoded word from the previous text
+	sep string
+}
+
+func encodeExactPGN(g *Game) string {
+	w := &pgnLayoutWriter{notation: g.notation, annotator: g.Annotator()}
+	l := g.layout
+	i := 0
+	for _, rec := range l.tags {
+		for k := i; k < len(g.tagPairs); k++ {
+			if *g.tagPairs[k] == rec.tag {
+				w.writeTags(g.tagPairs[i:k])
+				w.piece(rec.pgnPiece)
+				i = k + 1
+				break
+			}
+		}
+	}
+	w.writeTags(g.tagPairs[i:])
+	w.sep = ""
+	if w.sb.Len() > 0 {
+		w.sep = "\n\n"
+	}
+	w.writeComments(l.comments, g.comments)
+	w.writeMoves(g.positions, g.moves, false)
+	if l.result.text == string(g.outcome) || (l.result.text == "" && g.outcome == NoOutcome) {
+		w.piece(l.result)
+	} else {
+		w.word(string(g.outcome))
+	}
+	w.sb.WriteString(l.trailing)
+	return w.sb.String()
+}
+
+// piece writes the source text of a piece.  A separator is added if the
+// piece would otherwise be joined to the previous text, which can happen
+// when the piece that separated them is gone.
+func (w *pgnLayoutWriter) piece(p pgnPiece) {
+	s := w.sb.String()
+	if w.sep == "\n" && !strings.Contains(p.before, "\n") {
+		w.sb.WriteString("\n")
+	} else if p.before == "" && s != "" && p.text != "" &&
+		isSymbolContinuation(s[len(s)-1]) && isSymbolContinuation(p.text[0]) {
+		w.sb.WriteString(" ")
+	}
+	w.sb.WriteString(p.before)
+	w.sb.WriteString(p.text)
+	w.sep = " "
+	if strings.HasPrefix(p.text, ";") {
+		// a rest of line comment ends at the end of the line
+		w.sep = "\n"
+	}
+}
+
+// word writes encoded text.
+func (w *pgnLayoutWriter) word(s string) {
+	w.sb.WriteString(w.sep)
+	w.sb.WriteString(s)
+	w.sep = " "
+}
+
+func (w *pgnLayoutWriter) writeTags(tags []*TagPair) {
+	for _, tag := range tags {
+		if w.sb.Len() > 0 {
+			w.sb.WriteString("\n")
+		}
+		w.sb.WriteString(tag.String())
+	}
+}
+
+// writeComments writes the comments, the ones in the source as they were
+// written.
+func (w *pgnLayoutWriter) writeComments(recs []pgnAnnotation, comments []Comment) {
+	i := 0
+	for _, rec := range recs {
+		i = w.writeRecordedComment(rec, comments, i)
+	}
+	w.writeDefaultComments(comments[i:])
+}
+
+// writeRecordedComment writes the comment in the source if it's still
+// present after the first i comments, preceded by the comments before it,
+// and returns the number of comments written.
+func (w *pgnLayoutWriter) writeRecordedComment(rec pgnAnnotation, comments []Comment, i int) int {
+	for k := i; k < len(comments); k++ {
+		if comments[k] == rec.comment {
+			w.writeDefaultComments(comments[i:k])
+			w.piece(rec.pgnPiece)
+			return k + 1
+		}
+	}
+	return i
+}
+
+func (w *pgnLayoutWriter) writeDefaultComments(comments []Comment) {
+	for _, c := range comments {
+		w.word("{" + c.encode(w.annotator) + "}")
+	}
+}
+
+// writeMoves writes the moves where positions[i] is the position before
+// moves[i].  Move numbers are written as in encodeMoveText.
+func (w *pgnLayoutWriter) writeMoves(positions []*Position, moves []*Move, variation bool) {
+	resume := true
+	for i, m := range moves {
+		pos := positions[i]
+		l := m.layout
+		if l == nil {
+			l = &moveLayout{}
+		}
+		pre := m.preComments
+		if i == 0 && variation && m.annotator != w.annotator {
+			pre = append([]Comment{{Annotator: m.annotator}}, pre...)
+		}
+		if len(pre) > 0 {
+			w.writeComments(l.pre, pre)
+			resume = true
+		}
+		if m.layout != nil {
+			w.piece(l.pgnPiece)
+		} else if txt := w.notation.Encode(pos, m); pos.turn == White {
+			w.word(fmt.Sprintf("%d.%s", pos.moveCount, txt))
+		} else if resume {
+			w.word(fmt.Sprintf("%d...%s", pos.moveCount, txt))
+		} else {
+			w.word(txt)
+		}
+		nags := m.nags
+		if m.layout == nil {
+			nags = notationNAGs(w.notation, m)
+		}
+		w.writeAnnotations(l.annotations, pos, m, nags)
+		resume = len(nags) > 0 || len(m.comments) > 0 || len(m.variations) > 0
+	}
+}
+
+// writeAnnotations writes the move's NAGs and other annotations, the ones
+// in the source in their order and as they were written and the rest as
+// in encodeMoveText.
+func (w *pgnLayoutWriter) writeAnnotations(recs []pgnAnnotation, pos *Position, m *Move, nags []string) {
+	written, comments, variations := 0, 0, 0
+	for _, rec := range recs {
+		switch rec.typ {
+		case tokenNAG:
+			for k := written; k < len(nags); k++ {
+				if nags[k] == rec.nag {
+					w.writeNAGs(nags[written:k])
+					w.piece(rec.pgnPiece)
+					written = k + 1
+					break
+				}
+			}
+		case tokenComment:
+			comments = w.writeRecordedComment(rec, m.comments, comments)
+		case tokenVariationStart:
+			if variations < len(m.variations) {
+				w.writeVariation(pos, m.variations[variations])
+				variations++
+			}
+		}
+	}
+	w.writeNAGs(nags[written:])
+	w.writeDefaultComments(m.comments[comments:])
+	for _, v := range m.variations[variations:] {
+		w.writeVariation(pos, v)
+	}
+}
+
+func (w *pgnLayoutWriter) writeNAGs(nags []string) {
+	for _, nag := range nags {
+		if strings.HasPrefix(nag, "$") || w.sep == "\n" {
+			w.word(nag)
+		} else {
+			// move suffixes follow the move
+			w.sb.WriteString(nag)
+		}
+	}
+}
+
+func (w *pgnLayoutWriter) writeVariation(pos *Position, moves []*Move) {
+	l := moves[0].layout
+	if l != nil && l.open.text == "" {
+		// the move didn't begin a variation in the source
+		l = nil
+	}
+	if l != nil {
+		w.piece(l.open)
+	} else {
+		w.word("(")
+	}
+	w.sep = ""
+	positions := []*Position{}
+	for _, m := range moves {
+		positions = append(positions, pos)
+		pos = pos.Update(m)
+	}
+	w.writeMoves(positions, moves, true)
+	if l != nil {
+		w.piece(l.close)
+	} else {
+		w.sb.WriteString(")")
+		w.sep = " "
+	}
+}
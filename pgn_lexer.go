@@ -0,0 +1,233 @@
+package chess
+
+import "strings"
+
+type pgnTokenType int
+
+const (
+	tokenTagStart pgnTokenType = iota
+	tokenTagEnd
+	tokenString
+	tokenSymbol
+	tokenPeriod
+	tokenAsterisk
+	tokenNAG
+	tokenSuffix
+	tokenComment
+	tokenVariationStart
+	tokenVariationEnd
+)
+
+// pgnToken is a lexical token of the PGN import format.  The text of
+// strings and comments excludes their delimiters.  The token spans the
+// input from offset to end.
+type pgnToken struct {
+	typ    pgnTokenType
+	text   string
+	line   int
+	col    int
+	offset int
+	end    int
+}
+
+type pgnLexer struct {
+	s      string
+	offset int
+	line   int
+	col    int
+	// escapes are the escape lines skipped so far without their %
+	escapes []string
+}
+
+// lexPGN returns the tokens of the PGN text.  If an error is encountered
+// the tokens before the error are returned along with it.
+func lexPGN(s string) ([]pgnToken, error) {
+	tokens, _, err := lexPGNEscapes(s)
+	return tokens, err
+}
+
+// lexPGNEscapes is like lexPGN but also returns the escape lines, which
+// begin with % in the first column and are otherwise ignored.
+func lexPGNEscapes(s string) ([]pgnToken, []string, error) {
+	l := &pgnLexer{s: s, line: 1, col: 1}
+	tokens := []pgnToken{}
+	for {
+		t, ok, err := l.next()
+		if err != nil {
+			return tokens, l.escapes, err
+		}
+		if !ok {
+			return tokens, l.escapes, nil
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+func (l *pgnLexer) advance(n int) {
+	for i := 0; i < n; i++ {
+		if l.s[l.offset] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.offset++
+	}
+}
+
+func (l *pgnLexer) next() (pgnToken, bool, error) {
+	for l.offset < len(l.s) {
+		if c := l.s[l.offset]; c == '%' && l.col == 1 {
+			end := l.lineEnd()
+			l.escapes = append(l.escapes, strings.TrimRight(l.s[l.offset+1:end], "\r"))
+			l.advance(end - l.offset)
+		} else if strings.IndexByte(" \t\r\n", c) != -1 {
+			l.advance(1)
+		} else {
+			break
+		}
+	}
+	if l.offset >= len(l.s) {
+		return pgnToken{}, false, nil
+	}
+	t := pgnToken{line: l.line, col: l.col, offset: l.offset}
+	c := l.s[l.offset]
+	if typ, ok := pgnSingleCharTokens[c]; ok {
+		t.typ = typ
+		t.text = string(c)
+		l.advance(1)
+		t.end = l.offset
+		return t, true, nil
+	}
+	switch {
+	case c == '"':
+		end := l.offset + 1
+		for ; end < len(l.s) && l.s[end] != '"'; end++ {
+			if l.s[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(l.s) {
+			return t, false, l.errorf(t, "unterminated string")
+		}
+		t.typ = tokenString
+		t.text = l.s[l.offset+1 : end]
+		l.advance(end + 1 - l.offset)
+	case c == '{':
+		end := strings.IndexByte(l.s[l.offset:], '}')
+		if end == -1 {
+			return t, false, l.errorf(t, "unterminated comment")
+		}
+		t.typ = tokenComment
+		t.text = l.s[l.offset+1 : l.offset+end]
+		l.advance(end + 1)
+	case c == ';':
+		// rest of line comments are returned like brace comments
+		end := l.lineEnd()
+		t.typ = tokenComment
+		t.text = strings.TrimRight(l.s[l.offset+1:end], "\r")
+		l.advance(end - l.offset)
+	case c == '$':
+		end := l.offset + 1
+		for end < len(l.s) && isDigit(l.s[end]) {
+			end++
+		}
+		if end == l.offset+1 {
+			return t, false, l.errorf(t, "invalid NAG")
+		}
+		t.typ = tokenNAG
+		t.text = l.s[l.offset:end]
+		l.advance(end - l.offset)
+	case c == '!' || c == '?':
+		end := l.offset
+		for end < len(l.s) && (l.s[end] == '!' || l.s[end] == '?') {
+			end++
+		}
+		t.typ = tokenSuffix
+		t.text = l.s[l.offset:end]
+		l.advance(end - l.offset)
+	case strings.IndexByte("+-=", c) != -1:
+		// evaluation symbols such as += and +/- used in place of NAGs
+		end := l.offset
+		for end < len(l.s) && strings.IndexByte("+-=/", l.s[end]) != -1 {
+			end++
+		}
+		t.typ = tokenSuffix
+		t.text = l.s[l.offset:end]
+		if t.text == pgnNullMove {
+			t.typ = tokenSymbol
+		}
+		l.advance(end - l.offset)
+	case glyphPrefix(l.s[l.offset:]) != "":
+		t.typ = tokenSuffix
+		t.text = glyphPrefix(l.s[l.offset:])
+		l.advance(len(t.text))
+	case isSymbolStart(c):
+		end := l.offset
+		for end < len(l.s) && isSymbolContinuation(l.s[end]) {
+			end++
+		}
+		t.typ = tokenSymbol
+		t.text = l.s[l.offset:end]
+		l.advance(end - l.offset)
+	default:
+		return t, false, l.errorf(t, "unexpected character %q", c)
+	}
+	t.end = l.offset
+	return t, true, nil
+}
+
+// lineEnd returns the offset of the end of the current line.
+func (l *pgnLexer) lineEnd() int {
+	if i := strings.IndexByte(l.s[l.offset:], '\n'); i != -1 {
+		return l.offset + i
+	}
+	return len(l.s)
+}
+
+// errorf returns a PGNError at the token whose text is the word of the
+// input that starts at the token.
+func (l *pgnLexer) errorf(t pgnToken, format string, a ...interface{}) error {
+	word := l.s[t.offset:]
+	if i := strings.IndexAny(word, " \t\r\n"); i != -1 {
+		word = word[:i]
+	}
+	t.text = word
+	return newPGNError(t, nil, format, a...)
+}
+
+var (
+	pgnSingleCharTokens = map[byte]pgnTokenType{
+		'[': tokenTagStart,
+		']': tokenTagEnd,
+		'.': tokenPeriod,
+		'*': tokenAsterisk,
+		'(': tokenVariationStart,
+		')': tokenVariationEnd,
+	}
+)
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isSymbolStart(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isSymbolContinuation(c byte) bool {
+	return isSymbolStart(c) || strings.IndexByte("_+#=:-/", c) != -1
+}
+
+// glyphPrefix returns the non-ASCII NAG symbol such as ± that s begins
+// with or an empty string.
+func glyphPrefix(s string) string {
+	for _, symbols := range nagSymbols {
+		for _, symbol := range symbols {
+			if symbol[0] >= 0x80 && strings.HasPrefix(s, symbol) {
+				return symbol
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,228 @@
+package chess
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ParallelScanner decodes the games of concatenated PGN text with a pool
+// of workers.  The input is split into the text of each game as with
+// Scanner and the games are decoded concurrently, which speeds up
+// decoding large databases on multiple cores.  It is used like Scanner
+// and Close should be called if scanning stops before the end of the
+// input.
+type ParallelScanner struct {
+	src     *Scanner
+	workers int
+	ordered bool
+	pgnOpts []func(*pgnDecoder)
+
+	results chan parallelResult
+	done    chan struct{}
+	closed  sync.Once
+	// readErr is set by the reader before the results are closed
+	readErr error
+	// pending holds results that arrived ahead of their turn when ordered
+	pending map[int]parallelResult
+	next    int
+
+	game *Game
+	err  error
+	errs []*GameError
+}
+
+type parallelJob struct {
+	index  int
+	text   string
+	offset int
+	lines  int
+}
+
+type parallelResult struct {
+	index   int
+	game    *Game
+	err     error
+	skipped error
+}
+
+// ParallelWorkers is an option for NewParallelScanner that sets the number
+// of games decoded concurrently.  The default is the number of CPUs.
+func ParallelWorkers(n int) func(*ParallelScanner) {
+	return func(s *ParallelScanner) {
+		s.workers = n
+	}
+}
+
+// ParallelOrdered is an option for NewParallelScanner that returns games in
+// the order of the input.  By default games are returned as soon as they
+// are decoded.
+func ParallelOrdered(s *ParallelScanner) {
+	s.ordered = true
+}
+
+// ParallelPGNOptions is an option for NewParallelScanner that sets the
+// options, such as PreservePGN and LenientPGN, used to decode each game.
+func ParallelPGNOptions(opts ...func(*pgnDecoder)) func(*ParallelScanner) {
+	return func(s *ParallelScanner) {
+		s.pgnOpts = opts
+	}
+}
+
+// NewParallelScanner returns a new parallel scanner and starts reading and
+// decoding games in the background.  Compressed input is decompressed as
+// with NewScanner.
+func NewParallelScanner(r io.Reader, opts ...func(*ParallelScanner)) *ParallelScanner {
+	s := &ParallelScanner{workers: runtime.NumCPU(), next: 1}
+	for _, f := range opts {
+		if f != nil {
+			f(s)
+		}
+	}
+	if s.workers < 1 {
+		s.workers = 1
+	}
+	s.src = NewScanner(r, s.pgnOpts...)
+	s.results = make(chan parallelResult, s.workers)
+	s.done = make(chan struct{})
+	s.pending = map[int]parallelResult{}
+	jobs := make(chan parallelJob, s.workers)
+	go s.read(jobs)
+	var wg sync.WaitGroup
+	wg.Add(s.workers)
+	for i := 0; i < s.workers; i++ {
+		go func() {
+			defer wg.Done()
+			s.decode(jobs)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(s.results)
+	}()
+	return s
+}
+
+// Scan returns false if there was an error parsing a game or the end of
+// the input was reached.  Running scan populates data for Next and Err.
+// Scanning may continue after a game that couldn't be parsed.  In lenient
+// mode Scan only returns false at the end of the input or on a read error.
+func (s *ParallelScanner) Scan() bool {
+	s.game = nil
+	for {
+		res, ok := s.nextResult()
+		if !ok {
+			s.err = s.readErr
+			return false
+		}
+		lenient := s.src.decoder.lenient
+		if res.err != nil && !lenient {
+			s.err = res.err
+			return false
+		} else if res.err != nil {
+			s.errs = append(s.errs, &GameError{Game: res.index, Token: pgnErrorToken(res.err), Err: res.err})
+			continue
+		}
+		if res.skipped != nil {
+			s.errs = append(s.errs, &GameError{Game: res.index, Token: pgnErrorToken(res.skipped), Truncated: true, Err: res.skipped})
+		}
+		s.err = nil
+		s.game = res.game
+		return true
+	}
+}
+
+// Next returns the game from the most recent Scan.
+func (s *ParallelScanner) Next() *Game {
+	return s.game
+}
+
+// Err returns an error encountered during scanning.  Err returns nil if
+// the end of the input was reached without error.
+func (s *ParallelScanner) Err() error {
+	return s.err
+}
+
+// Errors returns the errors of the games that were skipped or truncated
+// so far in lenient mode in the order they were returned.
+func (s *ParallelScanner) Errors() []*GameError {
+	return append([]*GameError(nil), s.errs...)
+}
+
+// Close stops reading and decoding games.  Scan returns false after Close.
+func (s *ParallelScanner) Close() {
+	s.closed.Do(func() {
+		close(s.done)
+	})
+}
+
+// nextResult returns the next decoded game or false once every game has
+// been returned.
+func (s *ParallelScanner) nextResult() (parallelResult, bool) {
+	select {
+	case <-s.done:
+		return parallelResult{}, false
+	default:
+	}
+	if !s.ordered {
+		res, ok := <-s.results
+		return res, ok
+	}
+	for {
+		if res, ok := s.pending[s.next]; ok {
+			delete(s.pending, s.next)
+			s.next++
+			return res, true
+		}
+		res, ok := <-s.results
+		if !ok {
+			return parallelResult{}, false
+		}
+		s.pending[res.index] = res
+	}
+}
+
+// read splits the input into the text of each game.
+func (s *ParallelScanner) read(jobs chan<- parallelJob) {
+	defer close(jobs)
+	for index := 1; ; index++ {
+		text, err := s.src.readGame()
+		if err != nil {
+			s.readErr = err
+			return
+		}
+		if text == "" {
+			return
+		}
+		job := parallelJob{index: index, text: text, offset: s.src.offset, lines: s.src.lines}
+		s.src.offset += len(text)
+		s.src.lines += strings.Count(text, "\n")
+		select {
+		case jobs <- job:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// decode decodes games until there are no more jobs or the scanner is
+// closed.
+func (s *ParallelScanner) decode(jobs <-chan parallelJob) {
+	d := s.src.decoder
+	for job := range jobs {
+		game, p, err := d.parse(job.text)
+		res := parallelResult{index: job.index, game: game, err: err}
+		if err != nil {
+			locatePGNError(err, job.offset, job.lines)
+		} else if p.skipped != nil && d.lenient {
+			locatePGNError(p.skipped, job.offset, job.lines)
+			res.skipped = p.skipped
+		}
+		select {
+		case s.results <- res:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// parallelPGN returns games with the round tag set to their number.
+func parallelPGN(n int) string {
+	var sb strings.Builder
+	lines := []string{"1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 1-0", "1. d4 d5 2. c4 e6 1/2-1/2", "1. c4 {English} c5 0-1"}
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "[Round \"%d\"]\n\n%s\n\n", i, lines[i%len(lines)])
+	}
+	return sb.String()
+}
+
+func TestParallelScannerOrdered(t *testing.T) {
+	scanner := NewParallelScanner(strings.NewReader(parallelPGN(100)), ParallelWorkers(4), ParallelOrdered)
+	defer scanner.Close()
+	n := 0
+	for scanner.Scan() {
+		n++
+		if round := scanner.Next().GetTagPair("Round").Value; round != fmt.Sprint(n) {
+			t.Fatalf("expected round %d but got %s", n, round)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 100 {
+		t.Fatalf("expected 100 games but got %d", n)
+	}
+}
+
+func TestParallelScannerUnordered(t *testing.T) {
+	scanner := NewParallelScanner(strings.NewReader(parallelPGN(100)), ParallelWorkers(4))
+	defer scanner.Close()
+	seen := map[string]bool{}
+	for scanner.Scan() {
+		seen[scanner.Next().GetTagPair("Round").Value] = true
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 100 {
+		t.Fatalf("expected 100 distinct games but got %d", len(seen))
+	}
+}
+
+func TestParallelScannerErrors(t *testing.T) {
+	pgn := parallelPGN(2) + "[Round \"3\"]\n\n1. e4 e5 2. Ke3 *\n\n" + parallelPGN(1)
+	scanner := NewParallelScanner(strings.NewReader(pgn), ParallelOrdered)
+	defer scanner.Close()
+	n := 0
+	for scanner.Scan() {
+		n++
+	}
+	if n != 2 || scanner.Err() == nil {
+		t.Fatalf("expected an error after 2 games but got %d games and %v", n, scanner.Err())
+	}
+	if e, ok := scanner.Err().(*PGNError); !ok || e.Line != 11 {
+		t.Fatalf("expected a PGN error on line 11 but got %v", scanner.Err())
+	}
+	if !scanner.Scan() || scanner.Next().GetTagPair("Round").Value != "1" {
+		t.Fatal("expected scanning to continue after the error")
+	}
+
+	scanner = NewParallelScanner(strings.NewReader(pgn), ParallelOrdered, ParallelPGNOptions(LenientPGN))
+	defer scanner.Close()
+	n = 0
+	for scanner.Scan() {
+		n++
+	}
+	if n != 4 || scanner.Err() != nil {
+		t.Fatalf("expected 4 games without error but got %d and %v", n, scanner.Err())
+	}
+	if errs := scanner.Errors(); len(errs) != 1 || errs[0].Game != 3 || !errs[0].Truncated {
+		t.Fatalf("expected game 3 to be truncated but got %v", errs)
+	}
+}
+
+func TestParallelScannerClose(t *testing.T) {
+	scanner := NewParallelScanner(strings.NewReader(parallelPGN(100)), ParallelWorkers(2))
+	if !scanner.Scan() {
+		t.Fatal(scanner.Err())
+	}
+	scanner.Close()
+	if scanner.Scan() {
+		t.Fatal("expected Scan to return false after Close")
+	}
+}
@@ -1,6 +1,7 @@
 package chess
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -87,6 +88,46 @@ func TestValidPGNs(t *testing.T) {
 	}
 }
 
+func TestPreservePGN(t *testing.T) {
+	for i, test := range validPGNs {
+		opt, err := PGN(strings.NewReader(test.PGN), PreservePGN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded := NewGame(opt).String()
+		opt, err = PGN(strings.NewReader(encoded), PreservePGN)
+		if err != nil {
+			t.Fatalf("pgn %d failed to decode its encoding %s", i, err)
+		}
+		if reencoded := NewGame(opt).String(); reencoded != encoded {
+			t.Fatalf("pgn %d expected round trip\n%s\nbut got\n%s", i, encoded, reencoded)
+		}
+	}
+	opt, err := PGN(strings.NewReader(validPGNs[1].PGN), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewGame(opt).String()
+	expected := `38.Kf2 Kg7?! {(0.70 → 1.52) Inaccuracy. The best move was Rd6.} (38...Rd6 39.Re1`
+	if !strings.Contains(s, expected) {
+		t.Fatalf("expected pgn to contain %s but got %s", expected, s)
+	}
+	if !strings.Contains(s, "45.g7 {Black resigns} 1-0") {
+		t.Fatalf("expected pgn to end with comment and result but got %s", s)
+	}
+}
+
+func TestPGNWithoutPreserve(t *testing.T) {
+	game, err := decodePGN(validPGNs[1].PGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := game.String()
+	if movetext := s[strings.Index(s, "\n\n"):]; strings.ContainsAny(movetext, "{}()!?") {
+		t.Fatalf("expected annotations to be dropped but got %s", s)
+	}
+}
+
 func BenchmarkPGN(b *testing.B) {
 	pgn := `[Event "?"]
 	[Site "?"]
@@ -112,3 +153,337 @@ func BenchmarkPGN(b *testing.B) {
 		NewGame(opt)
 	}
 }
+
+func TestScanner(t *testing.T) {
+	long := strings.Repeat("Nf3 Nf6 Ng1 Ng8 ", 5000)
+	pgn := "[Event \"A\"]\n\n1. e4 {a comment\n\n[not a tag] still the comment} e5 1-0\n\n" +
+		"[Event \"B\"]\n1. d4 d5 0-1\n" +
+		"[Event \"C\"]\n\n" + long + "*\n\n" +
+		"1. c4 *\n\n\n" +
+		"1. Nf3 1/2-1/2"
+	scanner := NewScanner(strings.NewReader(pgn))
+	moves := []int{2, 2, 20000, 1, 1}
+	i := 0
+	for scanner.Scan() {
+		if i >= len(moves) {
+			t.Fatalf("expected %d games", len(moves))
+		}
+		if n := len(scanner.Next().Moves()); n != moves[i] {
+			t.Fatalf("expected game %d to have %d moves but got %d", i, moves[i], n)
+		}
+		i++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(moves) {
+		t.Fatalf("expected %d games but got %d", len(moves), i)
+	}
+}
+
+func TestScannerContinuesAfterError(t *testing.T) {
+	scanner := NewScanner(strings.NewReader("1. e4 e5 2. Ke3 *\n\n1. d4 *\n"))
+	if scanner.Scan() || scanner.Err() == nil {
+		t.Fatal("expected an error scanning the illegal game")
+	}
+	if !scanner.Scan() || len(scanner.Next().Moves()) != 1 {
+		t.Fatalf("expected to scan the next game but got %v", scanner.Err())
+	}
+	if scanner.Scan() || scanner.Err() != nil {
+		t.Fatalf("expected the end of the input but got %v", scanner.Err())
+	}
+}
+
+func TestScannerLenient(t *testing.T) {
+	pgn := "1. e4 e5 2. Ke3 Nc6 *\n\n" +
+		"[FEN \"not a fen\"]\n\n1. e4 *\n\n" +
+		"1. d4 d5 *\n"
+	scanner := NewScanner(strings.NewReader(pgn), LenientPGN)
+	counts := []int{}
+	for scanner.Scan() {
+		counts = append(counts, len(scanner.Next().Moves()))
+	}
+	if scanner.Err() != nil {
+		t.Fatal(scanner.Err())
+	}
+	if len(counts) != 2 || counts[0] != 2 || counts[1] != 2 {
+		t.Fatalf("expected the truncated and valid games but got move counts %v", counts)
+	}
+	errs := scanner.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors but got %d", len(errs))
+	}
+	if e := errs[0]; e.Game != 1 || e.Token != "Ke3" || !e.Truncated {
+		t.Fatalf("unexpected error for the truncated game %+v", e)
+	}
+	if e := errs[1]; e.Game != 2 || e.Token != "not a fen" || e.Truncated {
+		t.Fatalf("unexpected error for the skipped game %+v", e)
+	}
+}
+
+func TestPGNError(t *testing.T) {
+	tests := []struct {
+		pgn    string
+		token  string
+		line   int
+		column int
+	}{
+		{"[Event \"x\"]\n\n1. e4 e5\n2. Ke3 *", "Ke3", 4, 4},
+		{"1. e4 & e5 *", "&", 1, 7},
+		{"1. e4 e5 {unterminated\n*", "{unterminated", 1, 10},
+		{"[FEN \"bad\"]\n\n1. e4 *", "bad", 1, 6},
+		{"1. e4 ) e5 *", ")", 1, 7},
+	}
+	for _, test := range tests {
+		_, err := PGN(strings.NewReader(test.pgn))
+		var pgnErr *PGNError
+		if !errors.As(err, &pgnErr) {
+			t.Fatalf("expected a PGNError for %q but got %v", test.pgn, err)
+		}
+		if pgnErr.Token != test.token || pgnErr.Line != test.line || pgnErr.Column != test.column {
+			t.Fatalf("expected %s at %d:%d for %q but got %s at %d:%d", test.token, test.line, test.column, test.pgn, pgnErr.Token, pgnErr.Line, pgnErr.Column)
+		}
+		if offset := strings.Index(test.pgn, test.token); pgnErr.Offset != offset && pgnErr.Offset != offset-1 {
+			t.Fatalf("expected offset %d for %q but got %d", offset, test.pgn, pgnErr.Offset)
+		}
+	}
+}
+
+func TestPGNIllegalVariationMoves(t *testing.T) {
+	for _, pgn := range []string{"1. e4 (1. e2e5) e5 *", "1. e4 (1. a3a4) e5 *", "1. e4 (1. e1e8) e5 *"} {
+		_, err := PGN(strings.NewReader(pgn), PreservePGN)
+		var pgnErr *PGNError
+		if !errors.As(err, &pgnErr) || !strings.Contains(err.Error(), "illegal move") {
+			t.Fatalf("expected an illegal move error for %q but got %v", pgn, err)
+		}
+	}
+}
+
+func TestScannerErrorLocation(t *testing.T) {
+	pgn := "[Event \"1\"]\n\n1. e4 e5 *\n\n[Event \"2\"]\n\n1. d4 Ke3 *\n"
+	scanner := NewScanner(strings.NewReader(pgn))
+	if !scanner.Scan() {
+		t.Fatal(scanner.Err())
+	}
+	if scanner.Scan() {
+		t.Fatal("expected an error scanning the second game")
+	}
+	var pgnErr *PGNError
+	if !errors.As(scanner.Err(), &pgnErr) {
+		t.Fatalf("expected a PGNError but got %v", scanner.Err())
+	}
+	if pgnErr.Line != 7 || pgnErr.Column != 7 || pgnErr.Offset != strings.Index(pgn, "Ke3") {
+		t.Fatalf("expected Ke3 at line 7 column 7 offset %d but got %+v", strings.Index(pgn, "Ke3"), pgnErr)
+	}
+}
+
+func TestTagValueEscaping(t *testing.T) {
+	g := NewGame()
+	g.AddTagPair("White", `O"Kelly \ test`)
+	g.AddTagPair("Event", "line\none")
+	if s := g.GetTagPair("White").String(); s != `[White "O\"Kelly \\ test"]` {
+		t.Fatalf("expected the value to be escaped but got %s", s)
+	}
+	if s := g.GetTagPair("Event").String(); s != `[Event "line one"]` {
+		t.Fatalf("expected the newline to be written as a space but got %s", s)
+	}
+	if err := g.MoveStr("e4"); err != nil {
+		t.Fatal(err)
+	}
+	pgn := g.String() + "\n\n" + g.String()
+	scanner := NewScanner(strings.NewReader(pgn))
+	n := 0
+	for scanner.Scan() {
+		n++
+		if v := scanner.Next().GetTagPair("White").Value; v != `O"Kelly \ test` {
+			t.Fatalf("expected the value to round trip but got %s", v)
+		}
+	}
+	if scanner.Err() != nil || n != 2 {
+		t.Fatalf("expected 2 games but got %d and %v", n, scanner.Err())
+	}
+	index, err := IndexPGN(strings.NewReader(pgn))
+	if err != nil || len(index) != 2 || index[1].GetTagPair("White").Value != `O"Kelly \ test` {
+		t.Fatalf("expected the indexed value to be unescaped but got %v", err)
+	}
+}
+
+func TestPGNLineCommentsAndEscapes(t *testing.T) {
+	pgn := "% exported by some program\n" +
+		"[Event \"A\"]\n\n" +
+		"1. e4 ; the king's pawn {not a brace comment}\n" +
+		"e5 2. Nf3 1-0 ; white resigned\n\n" +
+		"%private data\n" +
+		"[Event \"B\"]\n\n1. d4 *\n"
+	scanner := NewScanner(strings.NewReader(pgn), PreservePGN)
+	games := []*Game{}
+	for scanner.Scan() {
+		games = append(games, scanner.Next())
+	}
+	if scanner.Err() != nil || len(games) != 2 {
+		t.Fatalf("expected 2 games but got %d and %v", len(games), scanner.Err())
+	}
+	g := games[0]
+	if len(g.Moves()) != 3 || g.Outcome() != WhiteWon {
+		t.Fatalf("expected 3 moves and 1-0 but got %s", g)
+	}
+	if lines := g.EscapeLines(); len(lines) != 1 || lines[0] != " exported by some program" {
+		t.Fatalf("expected the escape line to be preserved but got %q", lines)
+	}
+	comments, err := g.Comments(1)
+	if err != nil || len(comments) != 1 || comments[0].Text != "the king's pawn {not a brace comment}" {
+		t.Fatalf("expected the rest of line comment but got %+v %v", comments, err)
+	}
+	opt, err := PGN(strings.NewReader(g.String()), PreservePGN)
+	if err != nil {
+		t.Fatalf("expected the encoded game to decode but got %v", err)
+	}
+	if s := NewGame(opt).String(); !strings.Contains(s, "1.e4 {the king's pawn {not a brace comment)}") {
+		t.Fatalf("expected the comment to be encoded in braces but got %s", s)
+	}
+	if lines := games[1].EscapeLines(); len(lines) != 1 || lines[0] != "private data" {
+		t.Fatalf("expected the escape line before the tags to start the next game but got %q", lines)
+	}
+	opt, err = PGN(strings.NewReader("%skipped\n1. e4 ; comment\n*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := NewGame(opt); len(g.EscapeLines()) != 0 || strings.Contains(g.String(), "comment") {
+		t.Fatalf("expected escapes and comments to be dropped without PreservePGN but got %s", g)
+	}
+}
+
+func TestPGNNullMoves(t *testing.T) {
+	pgn := "1. e4 e5 2. Nf3 (2. -- Nc6 3. Z0 Nf6) 2... -- 3. Nxe5 *"
+	opt, err := PGN(strings.NewReader(pgn), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	if len(g.Moves()) != 5 || !g.Moves()[3].IsNull() {
+		t.Fatalf("expected black's fourth move to be null but got %s", g)
+	}
+	s := g.String()
+	if !strings.Contains(s, "(2.-- Nc6 3.-- Nf6) 2...-- 3.Nxe5") {
+		t.Fatalf("expected the null moves to be encoded as -- but got %s", s)
+	}
+	opt, err = PGN(strings.NewReader(s), PreservePGN)
+	if err != nil {
+		t.Fatalf("expected the encoded game to decode but got %v", err)
+	}
+	if reencoded := NewGame(opt).String(); reencoded != s {
+		t.Fatalf("expected round trip\n%s\nbut got\n%s", s, reencoded)
+	}
+}
+
+func TestChessBasePGN(t *testing.T) {
+	pgn := `[Event "ChessBase"]
+[Annotator "Fritz"]
+
+{[%evp 0,20,18]} 1. e4 $220 e5 $146 {[%tqu "En","Which move?","","","Nf3","",10]} 2. Nf3 (2. -- Nc6 3. Z0 {[%csl Rd4]} d5) Nc6 ∆ 3. Bb5 $221 {[%mdl 8192]} a6 4. 0-0 1-0`
+	opt, err := PGN(strings.NewReader(pgn), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	moves := g.Moves()
+	if len(moves) != 7 || !moves[6].HasTag(KingSideCastle) {
+		t.Fatalf("expected castling written with zeros to be decoded but got %s", g)
+	}
+	if !moves[0].Diagram() || !moves[4].Diagram() || moves[1].Diagram() {
+		t.Fatal("expected diagrams after the first and fifth moves")
+	}
+	if nags := moves[3].NAGs(); len(nags) != 1 || nags[0] != WithTheIdea {
+		t.Fatalf("expected the ∆ symbol to be decoded as a NAG but got %v", nags)
+	}
+	if c := moves[1].Comments(); len(c) != 1 || !strings.HasPrefix(c[0].Text, "[%tqu ") {
+		t.Fatalf("expected the training question to be kept but got %v", c)
+	}
+	if v := moves[2].Variations(); len(v) != 1 || !v[0][0].IsNull() || !v[0][2].IsNull() {
+		t.Fatalf("expected the null moves of the analysis to be kept but got %v", v)
+	}
+	s := g.String()
+	if !strings.Contains(s, "2...Nc6 $140 3.Bb5 $221 {[%mdl 8192]}") {
+		t.Fatalf("expected the NAGs and media comment to be encoded but got %s", s)
+	}
+	opt, err = PGN(strings.NewReader(s), PreservePGN)
+	if err != nil {
+		t.Fatalf("expected the encoded game to decode but got %v", err)
+	}
+	if reencoded := NewGame(opt).String(); reencoded != s {
+		t.Fatalf("expected round trip\n%s\nbut got\n%s", s, reencoded)
+	}
+}
+
+func TestExactPGN(t *testing.T) {
+	pgn := "% archived\n[Event \"A\"]\n[White \"X\"]  \n\n" +
+		"1.e4  e5 2. Nf3 {a\n  comment} ; rest of line\n2... Nc6\t$1 (2... d6) 1-0\n\n" +
+		"[Event \"B\"]\r\n\r\n1. d4 d5 *\r\n"
+	scanner := NewScanner(strings.NewReader(pgn), ExactPGN)
+	games := []*Game{}
+	var sb strings.Builder
+	for scanner.Scan() {
+		games = append(games, scanner.Next())
+		sb.WriteString(scanner.Next().String())
+	}
+	if scanner.Err() != nil || len(games) != 2 {
+		t.Fatalf("expected 2 games but got %d and %v", len(games), scanner.Err())
+	}
+	if sb.String() != pgn {
+		t.Fatalf("expected the games to encode to their source\n%q\nbut got\n%q", pgn, sb.String())
+	}
+	clone := games[0].Clone()
+	if clone.String() != games[0].String() {
+		t.Fatalf("expected the clone to keep the source")
+	}
+	if err := games[1].MoveStr("c4"); err != nil {
+		t.Fatal(err)
+	}
+	if s := games[1].String(); s != "[Event \"B\"]\r\n\r\n1. d4 d5 2.c4 *\r\n" {
+		t.Fatalf("expected only the new move to be encoded but got %q", s)
+	}
+	opt, err := PGN(strings.NewReader(games[0].String()), ExactPGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	if s := g.String(); s != strings.Split(pgn, "[Event \"B\"]")[0] {
+		t.Fatalf("expected the PGN function to keep the source but got %q", s)
+	}
+	g.AddTagPair("Site", "Y")
+	if s := g.String(); !strings.HasPrefix(s, "% archived\n[Event \"A\"]\n[White \"X\"]\n[Site \"Y\"]  \n\n1.e4  e5") {
+		t.Fatalf("expected the new tag to be added to the layout but got %q", s)
+	}
+	restored := NewGame()
+	if err := restored.Restore(g.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	if restored.String() != g.String() {
+		t.Fatalf("expected the snapshot to keep the layout but got %q", restored.String())
+	}
+}
+
+func TestExactPGNEdits(t *testing.T) {
+	pgn := "[Event \"A\"]\n\n1.e4 ; line\n{gone} e5  2.Nf3 (2.f4) $1 {kept}\n*"
+	tests := []struct {
+		edit     func(g *Game)
+		expected string
+	}{
+		{func(g *Game) {}, pgn},
+		{func(g *Game) { g.SetComment(1, "") }, "[Event \"A\"]\n\n1.e4 e5  2.Nf3 (2.f4) $1 {kept}\n*"},
+		{func(g *Game) { g.Strip(StripVariations) }, "[Event \"A\"]\n\n1.e4 ; line\n{gone} e5  2.Nf3 $1 {kept}\n*"},
+		{func(g *Game) { g.AddComment(3, "", "new") }, "[Event \"A\"]\n\n1.e4 ; line\n{gone} e5  2.Nf3 (2.f4) $1 {kept} {new}\n*"},
+		{func(g *Game) { g.Resign(Black) }, "[Event \"A\"]\n\n1.e4 ; line\n{gone} e5  2.Nf3 (2.f4) $1 {kept} 1-0"},
+	}
+	for _, test := range tests {
+		opt, err := PGN(strings.NewReader(pgn), ExactPGN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := NewGame(opt)
+		test.edit(g)
+		if s := g.String(); s != test.expected {
+			t.Fatalf("expected %q but got %q", test.expected, s)
+		}
+	}
+}
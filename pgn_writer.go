@@ -0,0 +1,224 @@
+package chess
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SevenTagRoster are the tags that the PGN export format requires in the
+// order it requires them.
+var SevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// PGNLineWidth is an option for WritePGN that wraps movetext lines to at
+// most n characters.  Lines aren't wrapped if n is zero or less.  The
+// default is 80.
+func PGNLineWidth(n int) func(*pgnEncoder) {
+	return func(e *pgnEncoder) {
+		e.width = n
+	}
+}
+
+// PGNTagOrder is an option for WritePGN that writes the given tags first
+// and in the given order.  The remaining tags follow in the order they
+// were added to the game.  The default order is SevenTagRoster.
+func PGNTagOrder(keys ...string) func(*pgnEncoder) {
+	return func(e *pgnEncoder) {
+		e.tagOrder = keys
+	}
+}
+
+// PGNWithoutComments is an option for WritePGN that leaves out comments.
+func PGNWithoutComments(e *pgnEncoder) {
+	e.noComments = true
+}
+
+// PGNWithoutNAGs is an option for WritePGN that leaves out NAGs.
+func PGNWithoutNAGs(e *pgnEncoder) {
+	e.noNAGs = true
+}
+
+// PGNWithoutVariations is an option for WritePGN that leaves out
+// variations.
+func PGNWithoutVariations(e *pgnEncoder) {
+	e.noVariations = true
+}
+
+// pgnEncoder holds the options used to write PGN text.
+type pgnEncoder struct {
+	width        int
+	tagOrder     []string
+	noComments   bool
+	noNAGs       bool
+	noVariations bool
+}
+
+// WritePGN writes the game to w in the PGN export format: tags in the
+// order of SevenTagRoster, moves in algebraic notation with a space after
+// each move number, NAGs in $N form (so move suffixes such as !? are
+// written as $5) and movetext wrapped to 80 characters.  Options change
+// the line width and tag order and leave out annotations.  Unlike String
+// the output ends with a newline.
+func (g *Game) WritePGN(w io.Writer, opts ...func(*pgnEncoder)) error {
+	e := &pgnEncoder{width: 80, tagOrder: SevenTagRoster}
+	for _, f := range opts {
+		if f != nil {
+			f(e)
+		}
+	}
+	var sb strings.Builder
+	for _, tag := range e.orderTags(g.tagPairs) {
+		sb.WriteString(tag.String() + "\n")
+	}
+	sb.WriteString("\n")
+	words := &pgnWords{}
+	annotator := g.Annotator()
+	e.writeComments(words, g.comments, annotator)
+	e.writeMoveText(words, g.positions, g.moves, annotator)
+	words.add(string(g.outcome))
+	sb.WriteString(words.wrap(e.width))
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// orderTags returns the tags in the encoder's order.
+func (e *pgnEncoder) orderTags(tags []*TagPair) []*TagPair {
+	ordered := []*TagPair{}
+	used := map[*TagPair]bool{}
+	for _, key := range e.tagOrder {
+		for _, tag := range tags {
+			if tag.Key == key && !used[tag] {
+				ordered = append(ordered, tag)
+				used[tag] = true
+			}
+		}
+	}
+	for _, tag := range tags {
+		if !used[tag] {
+			ordered = append(ordered, tag)
+		}
+	}
+	return ordered
+}
+
+// writeMoveText writes the moves where positions[i] is the position
+// before moves[i].  As with String the move number is repeated for
+// black's move when it follows a comment or variation.
+func (e *pgnEncoder) writeMoveText(words *pgnWords, positions []*Position, moves []*Move, annotator string) {
+	resume := true
+	for i, m := range moves {
+		pos := positions[i]
+		if !e.noComments && len(m.preComments) > 0 {
+			e.writeComments(words, m.preComments, annotator)
+			resume = true
+		}
+		if pos.turn == White {
+			words.add(fmt.Sprintf("%d.", pos.moveCount))
+		} else if resume {
+			words.add(fmt.Sprintf("%d...", pos.moveCount))
+		}
+		words.add(AlgebraicNotation{}.Encode(pos, m))
+		resume = false
+		if !e.noNAGs {
+			for _, s := range m.nags {
+				if n, err := ParseNAG(s); err == nil {
+					words.add(n.String())
+				}
+			}
+		}
+		if !e.noComments && len(m.comments) > 0 {
+			e.writeComments(words, m.comments, annotator)
+			resume = true
+		}
+		if e.noVariations {
+			continue
+		}
+		for _, variation := range m.variations {
+			words.open()
+			if !e.noComments && variation[0].annotator != annotator {
+				e.writeComments(words, []Comment{{Annotator: variation[0].annotator}}, annotator)
+			}
+			vpos := pos
+			vpositions := []*Position{}
+			for _, vm := range variation {
+				vpositions = append(vpositions, vpos)
+				vpos = vpos.Update(vm)
+			}
+			e.writeMoveText(words, vpositions, variation, annotator)
+			words.close()
+			resume = true
+		}
+	}
+}
+
+// writeComments writes each comment as brace delimited words.
+func (e *pgnEncoder) writeComments(words *pgnWords, comments []Comment, annotator string) {
+	if e.noComments {
+		return
+	}
+	for _, c := range comments {
+		fields := strings.Fields(c.encode(annotator))
+		if len(fields) == 0 {
+			words.add("{}")
+			continue
+		}
+		fields[0] = "{" + fields[0]
+		fields[len(fields)-1] += "}"
+		for _, f := range fields {
+			words.add(f)
+		}
+	}
+}
+
+// pgnWords are the words of movetext which are separated by whitespace
+// that may be wrapped.
+type pgnWords struct {
+	words []string
+	// join is true if the next word is written without a space
+	join bool
+}
+
+func (w *pgnWords) add(word string) {
+	if w.join && len(w.words) > 0 {
+		w.words[len(w.words)-1] += word
+	} else {
+		w.words = append(w.words, word)
+	}
+	w.join = false
+}
+
+// open starts a variation.
+func (w *pgnWords) open() {
+	w.add("(")
+	w.join = true
+}
+
+// close ends a variation.
+func (w *pgnWords) close() {
+	w.words[len(w.words)-1] += ")"
+}
+
+// wrap returns the words joined into lines of at most width characters.
+// A word longer than width is written on a line of its own.
+func (w *pgnWords) wrap(width int) string {
+	if width <= 0 {
+		return strings.Join(w.words, " ")
+	}
+	var sb strings.Builder
+	lineLen := 0
+	for _, word := range w.words {
+		switch {
+		case lineLen == 0:
+		case lineLen+1+len(word) > width:
+			sb.WriteString("\n")
+			lineLen = 0
+		default:
+			sb.WriteString(" ")
+			lineLen++
+		}
+		sb.WriteString(word)
+		lineLen += len(word)
+	}
+	return sb.String()
+}
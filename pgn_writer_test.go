@@ -0,0 +1,79 @@
+package chess
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const writerPGN = `[Black "Kasparov"]
+[ECO "C20"]
+[White "Carlsen"]
+[Event "Test"]
+[Result "*"]
+
+1. e4 {best by test} e5! (1... c5 2. Nf3 $14) 2. Nf3 Nc6 *`
+
+func TestWritePGN(t *testing.T) {
+	pgn, err := PGN(strings.NewReader(writerPGN), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	tests := []struct {
+		opts     []func(*pgnEncoder)
+		expected string
+	}{
+		{
+			nil,
+			"[Event \"Test\"]\n[White \"Carlsen\"]\n[Black \"Kasparov\"]\n[Result \"*\"]\n[ECO \"C20\"]\n\n" +
+				"1. e4 {best by test} 1... e5 $1 (1... c5 2. Nf3 $14) 2. Nf3 Nc6 *\n",
+		},
+		{
+			[]func(*pgnEncoder){PGNLineWidth(20), PGNTagOrder("ECO")},
+			"[ECO \"C20\"]\n[Black \"Kasparov\"]\n[White \"Carlsen\"]\n[Event \"Test\"]\n[Result \"*\"]\n\n" +
+				"1. e4 {best by test}\n1... e5 $1 (1... c5\n2. Nf3 $14) 2. Nf3\nNc6 *\n",
+		},
+		{
+			[]func(*pgnEncoder){PGNWithoutComments, PGNWithoutNAGs, PGNWithoutVariations, PGNTagOrder()},
+			"[Black \"Kasparov\"]\n[ECO \"C20\"]\n[White \"Carlsen\"]\n[Event \"Test\"]\n[Result \"*\"]\n\n" +
+				"1. e4 e5 2. Nf3 Nc6 *\n",
+		},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := g.WritePGN(&buf, test.opts...); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.expected {
+			t.Fatalf("expected\n%s\nbut got\n%s", test.expected, buf.String())
+		}
+	}
+}
+
+func TestWritePGNLineWidth(t *testing.T) {
+	g := NewGame()
+	for i := 0; i < 3; i++ {
+		for _, s := range []string{"Nf3", "Nf6", "Ng1", "Ng8"} {
+			if err := g.MoveStr(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := g.WritePGN(&buf, PGNLineWidth(20)); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if len(line) > 20 {
+			t.Fatalf("expected lines of at most 20 characters but got %q", line)
+		}
+	}
+	pgn, err := PGN(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(NewGame(pgn).Moves()) != 12 {
+		t.Fatal("expected the wrapped pgn to decode")
+	}
+}
@@ -0,0 +1,78 @@
+package chess
+
+// PlaneCount is the number of 8x8 planes in a position's plane encoding.
+const PlaneCount = 20
+
+// Planes encodes the position as PlaneCount planes of 64 values each for
+// use as neural network input.  Within a plane squares are indexed from
+// A1 (0) to H8 (63).  The planes are:
+//
+//	0-5    white pawns, knights, bishops, rooks, queens and king
+//	6-11   black pawns, knights, bishops, rooks, queens and king
+//	12     ones if white is to move
+//	13-16  ones for each of the K, Q, k and q castling rights
+//	17     one on the en passant square
+//	18     the half move clock
+//	19     the full move number
+func (pos *Position) Planes() []float32 {
+	planes := make([]float32, PlaneCount*numOfSquaresInBoard)
+	pos.encodePlanes(func(i int, v int) {
+		planes[i] = float32(v)
+	})
+	return planes
+}
+
+// PlanesUint8 is like Planes but encodes the values as bytes.  The
+// counters are capped at 255.
+func (pos *Position) PlanesUint8() []uint8 {
+	planes := make([]uint8, PlaneCount*numOfSquaresInBoard)
+	pos.encodePlanes(func(i int, v int) {
+		if v > 255 {
+			v = 255
+		}
+		planes[i] = uint8(v)
+	})
+	return planes
+}
+
+var planePieceTypes = []PieceType{Pawn, Knight, Bishop, Rook, Queen, King}
+
+// encodePlanes calls set for every non-zero value of the encoding.
+func (pos *Position) encodePlanes(set func(i int, v int)) {
+	fill := func(plane, v int) {
+		for sq := 0; sq < numOfSquaresInBoard; sq++ {
+			set(plane*numOfSquaresInBoard+sq, v)
+		}
+	}
+	for sq, p := range pos.board.SquareMap() {
+		plane := 0
+		for i, pt := range planePieceTypes {
+			if pt == p.Type() {
+				plane = i
+			}
+		}
+		if p.Color() == Black {
+			plane += len(planePieceTypes)
+		}
+		set(plane*numOfSquaresInBoard+int(sq), 1)
+	}
+	if pos.turn == White {
+		fill(12, 1)
+	}
+	rights := []struct {
+		c    Color
+		side Side
+	}{{White, KingSide}, {White, QueenSide}, {Black, KingSide}, {Black, QueenSide}}
+	for i, r := range rights {
+		if pos.canCastle(r.c, r.side) {
+			fill(13+i, 1)
+		}
+	}
+	if pos.enPassantSquare != NoSquare {
+		set(17*numOfSquaresInBoard+int(pos.enPassantSquare), 1)
+	}
+	if pos.halfMoveClock > 0 {
+		fill(18, pos.halfMoveClock)
+	}
+	fill(19, pos.moveCount)
+}
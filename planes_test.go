@@ -0,0 +1,37 @@
+package chess
+
+import "testing"
+
+func TestPlanes(t *testing.T) {
+	pos := unsafeFEN("rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b Kq e3 3 300")
+	planes := pos.Planes()
+	if len(planes) != PlaneCount*64 {
+		t.Fatalf("expected %d values but got %d", PlaneCount*64, len(planes))
+	}
+	tests := []struct {
+		plane int
+		sq    Square
+		v     float32
+	}{
+		{0, E4, 1},
+		{0, E2, 0},
+		{5, E1, 1},
+		{6, D4, 1},
+		{11, E8, 1},
+		{12, A1, 0},
+		{13, H8, 1},
+		{14, A1, 0},
+		{16, A1, 1},
+		{17, E3, 1},
+		{18, C5, 3},
+		{19, H1, 300},
+	}
+	for _, test := range tests {
+		if v := planes[test.plane*64+int(test.sq)]; v != test.v {
+			t.Fatalf("expected plane %d square %s to be %f but got %f", test.plane, test.sq, test.v, v)
+		}
+	}
+	if v := pos.PlanesUint8()[19*64]; v != 255 {
+		t.Fatalf("expected capped full move number but got %d", v)
+	}
+}
@@ -0,0 +1,107 @@
+package chess
+
+import (
+	"math/rand"
+
+	"github.com/notnil/chess/internal/weighted"
+)
+
+// PlayoutResult is the result of a random playout.
+type PlayoutResult struct {
+	// Moves are the moves played.
+	Moves []*Move
+	// Position is the final position.
+	Position *Position
+	// Outcome is the result or NoOutcome if the ply limit was reached.
+	Outcome Outcome
+	// Method is the method of the outcome.
+	Method Method
+}
+
+type playout struct {
+	maxPlies int
+	policy   func(pos *Position, moves []*Move) []float64
+}
+
+// PlayoutMaxPlies is an option for Playout that limits the number of
+// half moves played.  The default is 500.
+func PlayoutMaxPlies(n int) func(*playout) {
+	return func(p *playout) {
+		p.maxPlies = n
+	}
+}
+
+// PlayoutPolicy is an option for Playout that weights the choice of
+// move.  The policy returns a non-negative weight for each move.  If all
+// weights are zero the move is chosen uniformly.  Missing weights are
+// zero and extra weights are ignored.
+func PlayoutPolicy(policy func(pos *Position, moves []*Move) []float64) func(*playout) {
+	return func(p *playout) {
+		p.policy = policy
+	}
+}
+
+// Playout plays random legal moves from the position until the game
+// ends by checkmate, stalemate, insufficient material or the fifty move
+// rule, or the ply limit is reached.  Moves are chosen uniformly unless
+// PlayoutPolicy is given.  The random source r makes playouts
+// reproducible; if it is nil the math/rand default source is used.
+// Repetitions aren't tracked.
+func Playout(pos *Position, r *rand.Rand, opts ...func(*playout)) *PlayoutResult {
+	p := &playout{maxPlies: 500}
+	for _, f := range opts {
+		if f != nil {
+			f(p)
+		}
+	}
+	intn := rand.Intn
+	if r != nil {
+		intn = r.Intn
+	}
+	result := &PlayoutResult{Moves: []*Move{}, Outcome: NoOutcome}
+	for {
+		if outcome, method := playoutStatus(pos); outcome != NoOutcome {
+			result.Outcome = outcome
+			result.Method = method
+			break
+		}
+		if len(result.Moves) >= p.maxPlies {
+			break
+		}
+		moves := pos.ValidMoves()
+		m := moves[intn(len(moves))]
+		if p.policy != nil {
+			weights := p.policy(pos, moves)
+			// weights past the last move are ignored
+			if len(weights) > len(moves) {
+				weights = weights[:len(moves)]
+			}
+			if i := weighted.Index(weights, r); i != -1 {
+				m = moves[i]
+			}
+		}
+		result.Moves = append(result.Moves, m)
+		pos = pos.Update(m)
+	}
+	result.Position = pos
+	return result
+}
+
+func playoutStatus(pos *Position) (Outcome, Method) {
+	switch pos.Status() {
+	case Checkmate:
+		if pos.Turn() == White {
+			return BlackWon, Checkmate
+		}
+		return WhiteWon, Checkmate
+	case Stalemate:
+		return Draw, Stalemate
+	}
+	if !pos.board.hasSufficientMaterial() {
+		return Draw, InsufficientMaterial
+	}
+	if pos.halfMoveClock >= 100 {
+		return Draw, FiftyMoveRule
+	}
+	return NoOutcome, NoMethod
+}
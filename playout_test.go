@@ -0,0 +1,54 @@
+package chess
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPlayout(t *testing.T) {
+	r1 := Playout(StartingPosition(), rand.New(rand.NewSource(7)))
+	r2 := Playout(StartingPosition(), rand.New(rand.NewSource(7)))
+	if len(r1.Moves) != len(r2.Moves) || r1.Position.String() != r2.Position.String() {
+		t.Fatal("expected playouts with the same seed to be equal")
+	}
+	if r1.Outcome != NoOutcome && r1.Method == NoMethod {
+		t.Fatalf("expected a method for outcome %s", r1.Outcome)
+	}
+	r := Playout(StartingPosition(), rand.New(rand.NewSource(1)), PlayoutMaxPlies(10))
+	if len(r.Moves) != 10 || r.Outcome != NoOutcome {
+		t.Fatalf("expected 10 moves without an outcome but got %d %s", len(r.Moves), r.Outcome)
+	}
+}
+
+func TestPlayoutPolicy(t *testing.T) {
+	// a policy that only plays Qh4 which mates in the fool's mate position
+	pos := unsafeFEN("rnbqkbnr/pppp1ppp/8/4p3/6P1/5P2/PPPPP2P/RNBQKBNR b KQkq - 0 2")
+	policy := func(pos *Position, moves []*Move) []float64 {
+		weights := make([]float64, len(moves))
+		for i, m := range moves {
+			if m.S2() == H4 && pos.Board().Piece(m.S1()) == BlackQueen {
+				weights[i] = 1
+			}
+		}
+		return weights
+	}
+	r := Playout(pos, rand.New(rand.NewSource(1)), PlayoutPolicy(policy))
+	if r.Outcome != BlackWon || r.Method != Checkmate || len(r.Moves) != 1 {
+		t.Fatalf("expected Qh4 checkmate but got %s %s", r.Outcome, r.Method)
+	}
+}
+
+func TestPlayoutPolicyExtraWeights(t *testing.T) {
+	// only the weights past the last move are positive
+	policy := func(pos *Position, moves []*Move) []float64 {
+		weights := make([]float64, len(moves)+5)
+		for i := len(moves); i < len(weights); i++ {
+			weights[i] = 1
+		}
+		return weights
+	}
+	r := Playout(StartingPosition(), rand.New(rand.NewSource(1)), PlayoutPolicy(policy), PlayoutMaxPlies(20))
+	if len(r.Moves) != 20 {
+		t.Fatalf("expected 20 moves but got %d", len(r.Moves))
+	}
+}
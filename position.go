@@ -24,7 +24,9 @@ const (
 type CastleRights string
 
 // CanCastle returns true if the given color and side combination
-// can castle, otherwise returns false.
+// can castle, otherwise returns false.  Rights written as the file of an
+// inner rook (ex. G in X-FEN) depend on the king's file and aren't
+// reported.
 func (cr CastleRights) CanCastle(c Color, side Side) bool {
 	char := "k"
 	if side == QueenSide {
@@ -71,15 +73,17 @@ func StartingPosition() *Position {
 // Game's Move method.  This method is more performant for bots that
 // rely on the ValidMoves because it skips redundant validation.
 func (pos *Position) Update(m *Move) *Position {
+	if m.IsNull() {
+		return pos.updateNull()
+	}
 	moveCount := pos.moveCount
 	if pos.turn == Black {
 		moveCount++
 	}
-	cr := pos.CastleRights()
-	ncr := pos.updateCastleRights(m)
+	rights := pos.updateCastleRights(m)
 	p := pos.board.Piece(m.s1)
 	halfMove := pos.halfMoveClock
-	if p.Type() == Pawn || m.HasTag(Capture) || cr != ncr {
+	if p.Type() == Pawn || m.HasTag(Capture) || len(rights) != len(pos.castleRightsList()) {
 		halfMove = 0
 	} else {
 		halfMove++
@@ -89,7 +93,7 @@ func (pos *Position) Update(m *Move) *Position {
 	return &Position{
 		board:           b,
 		turn:            pos.turn.Other(),
-		castleRights:    ncr,
+		castleRights:    formatCastleRights(b, rights),
 		enPassantSquare: pos.updateEnPassantSquare(m),
 		halfMoveClock:   halfMove,
 		moveCount:       moveCount,
@@ -97,6 +101,25 @@ func (pos *Position) Update(m *Move) *Position {
 	}
 }
 
+// updateNull returns the position after a null move, which passes the
+// turn and clears the en passant square without changing the board.
+func (pos *Position) updateNull() *Position {
+	moveCount := pos.moveCount
+	if pos.turn == Black {
+		moveCount++
+	}
+	next := &Position{
+		board:           pos.board.copy(),
+		turn:            pos.turn.Other(),
+		castleRights:    pos.castleRights,
+		enPassantSquare: NoSquare,
+		halfMoveClock:   pos.halfMoveClock + 1,
+		moveCount:       moveCount,
+	}
+	next.inCheck = isInCheck(next)
+	return next
+}
+
 // ValidMoves returns a list of valid moves for the position.
 func (pos *Position) ValidMoves() []*Move {
 	if pos.validMoves != nil {
@@ -173,7 +196,7 @@ func (pos *Position) UnmarshalText(text []byte) error {
 	pos.enPassantSquare = cp.enPassantSquare
 	pos.halfMoveClock = cp.halfMoveClock
 	pos.moveCount = cp.moveCount
-	pos.inCheck = isInCheck(cp)
+	pos.inCheck = cp.inCheck
 	return nil
 }
 
@@ -202,17 +225,19 @@ func (pos *Position) MarshalBinary() (data []byte, err error) {
 	if err := binary.Write(buf, binary.BigEndian, pos.enPassantSquare); err != nil {
 		return nil, err
 	}
+	// rights with an inner rook can't be told apart from those with the
+	// outermost rook in the binary format
 	var b uint8
-	if pos.castleRights.CanCastle(White, KingSide) {
+	if pos.canCastle(White, KingSide) {
 		b = b | bitsCastleWhiteKing
 	}
-	if pos.castleRights.CanCastle(White, QueenSide) {
+	if pos.canCastle(White, QueenSide) {
 		b = b | bitsCastleWhiteQueen
 	}
-	if pos.castleRights.CanCastle(Black, KingSide) {
+	if pos.canCastle(Black, KingSide) {
 		b = b | bitsCastleBlackKing
 	}
-	if pos.castleRights.CanCastle(Black, QueenSide) {
+	if pos.canCastle(Black, QueenSide) {
 		b = b | bitsCastleBlackQueen
 	}
 	if pos.turn == Black {
@@ -294,25 +319,19 @@ func (pos *Position) copy() *Position {
 	}
 }
 
-func (pos *Position) updateCastleRights(m *Move) CastleRights {
-	cr := string(pos.castleRights)
+// updateCastleRights returns the castling rights that remain after the
+// move.  Moving the king loses its color's rights and moving or capturing
+// a castling rook loses its right.
+func (pos *Position) updateCastleRights(m *Move) []castleRight {
+	rights := []castleRight{}
 	p := pos.board.Piece(m.s1)
-	if p == WhiteKing || m.s1 == H1 || m.s2 == H1 {
-		cr = strings.Replace(cr, "K", "", -1)
-	}
-	if p == WhiteKing || m.s1 == A1 || m.s2 == A1 {
-		cr = strings.Replace(cr, "Q", "", -1)
-	}
-	if p == BlackKing || m.s1 == H8 || m.s2 == H8 {
-		cr = strings.Replace(cr, "k", "", -1)
-	}
-	if p == BlackKing || m.s1 == A8 || m.s2 == A8 {
-		cr = strings.Replace(cr, "q", "", -1)
-	}
-	if cr == "" {
-		cr = "-"
+	for _, r := range pos.castleRightsList() {
+		if p == getPiece(King, r.color) || m.s1 == r.rook || m.s2 == r.rook {
+			continue
+		}
+		rights = append(rights, r)
 	}
-	return CastleRights(cr)
+	return rights
 }
 
 func (pos *Position) updateEnPassantSquare(m *Move) Square {
@@ -0,0 +1,32 @@
+package chess
+
+// IsPromotion returns true if a legal move from s1 to s2 requires a
+// promotion choice.  UIs can use it to show a piece picker before
+// looking up the final move.
+func (pos *Position) IsPromotion(s1, s2 Square) bool {
+	return len(pos.PromotionChoices(s1, s2)) > 0
+}
+
+// PromotionChoices returns the piece types that the pawn on s1 can legally
+// promote to when moving to s2 ordered from queen to knight.  An empty
+// slice is returned if the move isn't a legal promotion.
+func (pos *Position) PromotionChoices(s1, s2 Square) []PieceType {
+	choices := []PieceType{}
+	for _, pt := range []PieceType{Queen, Rook, Bishop, Knight} {
+		if pos.PromotionMove(s1, s2, pt) != nil {
+			choices = append(choices, pt)
+		}
+	}
+	return choices
+}
+
+// PromotionMove returns the valid move from s1 to s2 that promotes to the
+// given piece type or nil if there isn't one.
+func (pos *Position) PromotionMove(s1, s2 Square, promo PieceType) *Move {
+	for _, m := range pos.ValidMoves() {
+		if m.s1 == s1 && m.s2 == s2 && m.promo == promo && promo != NoPieceType {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package chess
+
+import "testing"
+
+func TestPromotionChoices(t *testing.T) {
+	pos := unsafeFEN("1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1")
+	if !pos.IsPromotion(A7, B8) || !pos.IsPromotion(A7, A8) {
+		t.Fatal("expected a7 moves to require a promotion")
+	}
+	if pos.IsPromotion(E1, E2) {
+		t.Fatal("expected king move not to require a promotion")
+	}
+	choices := pos.PromotionChoices(A7, B8)
+	if len(choices) != 4 || choices[0] != Queen || choices[3] != Knight {
+		t.Fatalf("expected four promotion choices but got %v", choices)
+	}
+	m := pos.PromotionMove(A7, B8, Knight)
+	if m == nil || m.Promo() != Knight || !m.HasTag(Capture) {
+		t.Fatalf("expected knight capture promotion but got %v", m)
+	}
+	if pos.PromotionMove(A7, B8, King) != nil {
+		t.Fatal("expected no king promotion")
+	}
+}
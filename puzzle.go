@@ -0,0 +1,330 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// A PuzzleTheme is a tag describing a puzzle using the theme vocabulary
+// of lichess such as "fork" or "mateIn2".
+type PuzzleTheme string
+
+// The puzzle themes that PuzzleThemes detects.  Mate themes such as
+// mateIn2 are created for mates in one to five moves.
+const (
+	ThemeMate             PuzzleTheme = "mate"
+	ThemeOneMove          PuzzleTheme = "oneMove"
+	ThemeShort            PuzzleTheme = "short"
+	ThemeLong             PuzzleTheme = "long"
+	ThemeVeryLong         PuzzleTheme = "veryLong"
+	ThemeOpening          PuzzleTheme = "opening"
+	ThemeMiddlegame       PuzzleTheme = "middlegame"
+	ThemeEndgame          PuzzleTheme = "endgame"
+	ThemePawnEndgame      PuzzleTheme = "pawnEndgame"
+	ThemeKnightEndgame    PuzzleTheme = "knightEndgame"
+	ThemeBishopEndgame    PuzzleTheme = "bishopEndgame"
+	ThemeRookEndgame      PuzzleTheme = "rookEndgame"
+	ThemeQueenEndgame     PuzzleTheme = "queenEndgame"
+	ThemeQueenRookEndgame PuzzleTheme = "queenRookEndgame"
+	ThemeFork             PuzzleTheme = "fork"
+	ThemePin              PuzzleTheme = "pin"
+	ThemeSkewer           PuzzleTheme = "skewer"
+	ThemeDoubleCheck      PuzzleTheme = "doubleCheck"
+	ThemeDiscoveredAttack PuzzleTheme = "discoveredAttack"
+	ThemeDeflection       PuzzleTheme = "deflection"
+	ThemeSacrifice        PuzzleTheme = "sacrifice"
+	ThemePromotion        PuzzleTheme = "promotion"
+	ThemeUnderPromotion   PuzzleTheme = "underPromotion"
+	ThemeCastling         PuzzleTheme = "castling"
+	ThemeEnPassant        PuzzleTheme = "enPassant"
+	ThemeAdvancedPawn     PuzzleTheme = "advancedPawn"
+	ThemeBackRankMate     PuzzleTheme = "backRankMate"
+	ThemeSmotheredMate    PuzzleTheme = "smotheredMate"
+	ThemeArabianMate      PuzzleTheme = "arabianMate"
+	ThemeAnastasiaMate    PuzzleTheme = "anastasiaMate"
+	ThemeHookMate         PuzzleTheme = "hookMate"
+	ThemeBodenMate        PuzzleTheme = "bodenMate"
+	ThemeDoubleBishopMate PuzzleTheme = "doubleBishopMate"
+	ThemeDovetailMate     PuzzleTheme = "dovetailMate"
+)
+
+const (
+	// puzzleOpeningMoves is the last move number of the opening phase.
+	puzzleOpeningMoves = 10
+	// puzzleSacrificeCentipawns is the material given up by a sacrifice.
+	puzzleSacrificeCentipawns = 200
+)
+
+// mateThemes are the themes of the mate patterns that lichess tags.
+var mateThemes = map[MatePattern]PuzzleTheme{
+	BackRankMate:     ThemeBackRankMate,
+	SmotheredMate:    ThemeSmotheredMate,
+	ArabianMate:      ThemeArabianMate,
+	AnastasiaMate:    ThemeAnastasiaMate,
+	HookMate:         ThemeHookMate,
+	BodenMate:        ThemeBodenMate,
+	DoubleBishopMate: ThemeDoubleBishopMate,
+	DovetailMate:     ThemeDovetailMate,
+}
+
+// PuzzleThemes returns the themes of the puzzle starting at pos whose
+// solution alternates between the solver's moves and the opponent's
+// replies starting with the solver.  Themes are detected statically:
+//
+//	mate, mateInN and mate patterns for solutions ending in checkmate
+//	oneMove, short, long and veryLong by the number of solver moves
+//	opening, middlegame, endgame and the endgame type of pos
+//	fork, pin, skewer and deflection created by a solver move
+//	doubleCheck and discoveredAttack for discovered checks
+//	sacrifice if the solver gives up two pawns of material
+//	promotion, underPromotion, castling, enPassant and advancedPawn
+//
+// Forks, skewers and deflections are only tagged if the solver wins a
+// piece with the following move.  An error is returned if the solution is
+// empty or a move isn't legal.
+func PuzzleThemes(pos *Position, solution []*Move) ([]PuzzleTheme, error) {
+	if len(solution) == 0 {
+		return nil, errors.New("chess: puzzle has no solution moves")
+	}
+	positions := []*Position{pos}
+	moves := []*Move{}
+	for i, m := range solution {
+		cur := positions[i]
+		valid := moveSlice(cur.ValidMoves()).find(m)
+		if valid == nil {
+			return nil, fmt.Errorf("chess: puzzle move %d %s isn't legal in position %s", i+1, m, cur)
+		}
+		moves = append(moves, valid)
+		positions = append(positions, cur.Update(valid))
+	}
+	t := &puzzleTagger{themes: []PuzzleTheme{}}
+	final := positions[len(positions)-1]
+	solverMoves := (len(moves) + 1) / 2
+	if final.Status() == Checkmate {
+		t.add(ThemeMate)
+		if solverMoves <= 5 {
+			t.add(PuzzleTheme("mateIn" + strconv.Itoa(solverMoves)))
+		}
+		for _, p := range final.MatePatterns() {
+			if theme, ok := mateThemes[p]; ok {
+				t.add(theme)
+			}
+		}
+	}
+	switch solverMoves {
+	case 1:
+		t.add(ThemeOneMove)
+	case 2:
+		t.add(ThemeShort)
+	case 3:
+		t.add(ThemeLong)
+	default:
+		t.add(ThemeVeryLong)
+	}
+	t.phase(pos)
+	for i := 0; i < len(moves); i += 2 {
+		var next *Move
+		if i+2 < len(moves) {
+			next = moves[i+2]
+		}
+		t.move(positions[i], moves[i], positions[i+1], next)
+		if i+2 < len(moves) {
+			t.deflection(positions[i+1], moves[i], moves[i+1], positions[i+2], next)
+		}
+	}
+	t.sacrifice(positions, pos.turn)
+	return t.themes, nil
+}
+
+// puzzleTagger collects the themes of a puzzle.
+type puzzleTagger struct {
+	themes []PuzzleTheme
+}
+
+func (t *puzzleTagger) add(theme PuzzleTheme) {
+	for _, existing := range t.themes {
+		if existing == theme {
+			return
+		}
+	}
+	t.themes = append(t.themes, theme)
+}
+
+// phase adds the game phase and endgame type of the position.
+func (t *puzzleTagger) phase(pos *Position) {
+	e := pos.Endgame()
+	switch {
+	case e.Type != NoEndgame:
+		t.add(ThemeEndgame)
+	case pos.moveCount <= puzzleOpeningMoves:
+		t.add(ThemeOpening)
+		return
+	default:
+		t.add(ThemeMiddlegame)
+		return
+	}
+	switch e.Type {
+	case PawnEnding:
+		t.add(ThemePawnEndgame)
+	case KnightEnding:
+		t.add(ThemeKnightEndgame)
+	case BishopEnding:
+		t.add(ThemeBishopEndgame)
+	case RookEnding:
+		t.add(ThemeRookEndgame)
+	case QueenEnding:
+		t.add(ThemeQueenEndgame)
+	case MixedEnding:
+		b := pos.board
+		minors := 0
+		for _, p := range []Piece{WhiteBishop, WhiteKnight, BlackBishop, BlackKnight} {
+			minors += len(pieceSquares(b, p))
+		}
+		if minors == 0 {
+			t.add(ThemeQueenRookEndgame)
+		}
+	}
+}
+
+// move adds the themes of a solver move m from before to after.  Next is
+// the solver's following move or nil.
+func (t *puzzleTagger) move(before *Position, m *Move, after *Position, next *Move) {
+	mover := before.board.Piece(m.s1)
+	switch {
+	case m.promo != NoPieceType:
+		t.add(ThemePromotion)
+		if m.promo != Queen {
+			t.add(ThemeUnderPromotion)
+		}
+	case m.HasTag(KingSideCastle) || m.HasTag(QueenSideCastle):
+		t.add(ThemeCastling)
+	case m.HasTag(EnPassant):
+		t.add(ThemeEnPassant)
+	case mover.Type() == Pawn:
+		rank := int(m.s2.Rank())
+		if mover.Color() == Black {
+			rank = 7 - rank
+		}
+		if rank >= 5 {
+			t.add(ThemeAdvancedPawn)
+		}
+	}
+	enemy := mover.Color().Other()
+	occ := ^after.board.emptySqs
+	if m.HasTag(Check) {
+		king := after.board.whiteKingSq
+		if enemy == Black {
+			king = after.board.blackKingSq
+		}
+		checkers := attackersOf(after.board, king, mover.Color(), occ)
+		if len(checkers) > 1 {
+			t.add(ThemeDoubleCheck)
+		}
+		for _, sq := range checkers {
+			if sq != m.s2 {
+				t.add(ThemeDiscoveredAttack)
+			}
+		}
+	}
+	piece := after.board.Piece(m.s2)
+	if next != nil && t.fork(after, piece, m.s2, next) {
+		t.add(ThemeFork)
+	}
+	t.lines(after, piece, m.s2, next)
+}
+
+// fork returns true if the piece on sq attacks at least two enemy pieces
+// other than pawns that are either the king, more valuable than the piece
+// or undefended, and the next move captures one of them.
+func (t *puzzleTagger) fork(pos *Position, piece Piece, sq Square, next *Move) bool {
+	occ := ^pos.board.emptySqs
+	enemy := piece.Color().Other()
+	values := DefaultEvalParams
+	targets := map[Square]bool{}
+	for i := 0; i < numOfSquaresInBoard; i++ {
+		target := Square(i)
+		p := pos.board.Piece(target)
+		if p == NoPiece || p.Color() != enemy || p.Type() == Pawn || !pieceAttacks(piece, sq, occ).Occupied(target) {
+			continue
+		}
+		if p.Type() == King || values.seeValue(p.Type()) > values.seeValue(piece.Type()) ||
+			len(attackersOf(pos.board, target, enemy, occ)) == 0 {
+			targets[target] = true
+		}
+	}
+	return len(targets) >= 2 && targets[next.s2] && next.HasTag(Capture)
+}
+
+// lines adds pins and skewers along the lines of the sliding piece on sq.
+func (t *puzzleTagger) lines(pos *Position, piece Piece, sq Square, next *Move) {
+	var directions [][2]int
+	switch piece.Type() {
+	case Bishop:
+		directions = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+	case Rook:
+		directions = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	case Queen:
+		directions = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}, {1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	}
+	enemy := piece.Color().Other()
+	values := DefaultEvalParams
+	for _, d := range directions {
+		found := []Square{}
+		for s, ok := offsetSquare(sq, d[0], d[1]); ok && len(found) < 2; s, ok = offsetSquare(s, d[0], d[1]) {
+			if pos.board.Piece(s) != NoPiece {
+				found = append(found, s)
+			}
+		}
+		if len(found) < 2 {
+			continue
+		}
+		front, back := pos.board.Piece(found[0]), pos.board.Piece(found[1])
+		if front.Color() != enemy || back.Color() != enemy {
+			continue
+		}
+		frontValue, backValue := values.seeValue(front.Type()), values.seeValue(back.Type())
+		switch {
+		case front.Type() != King && front.Type() != Pawn && backValue > frontValue:
+			t.add(ThemePin)
+		case frontValue > backValue && next != nil && next.s2 == found[1] && next.HasTag(Capture):
+			t.add(ThemeSkewer)
+		}
+	}
+}
+
+// deflection adds a deflection if the solver move m forced the reply to
+// move a piece that defended the square the solver captures on or checks
+// from with the next move.
+func (t *puzzleTagger) deflection(before *Position, m, reply *Move, after *Position, next *Move) {
+	if !m.HasTag(Check) && !m.HasTag(Capture) {
+		return
+	}
+	if !next.HasTag(Capture) && !next.HasTag(Check) {
+		return
+	}
+	defender := before.board.Piece(reply.s1)
+	if defender.Type() == King {
+		return
+	}
+	defended := pieceAttacks(defender, reply.s1, ^before.board.emptySqs).Occupied(next.s2)
+	stillDefends := pieceAttacks(after.board.Piece(reply.s2), reply.s2, ^after.board.emptySqs).Occupied(next.s2)
+	if defended && !stillDefends {
+		t.add(ThemeDeflection)
+	}
+}
+
+// sacrifice adds a sacrifice if the solver's material lead after any of
+// the opponent's replies is at least two pawns less than at the start.
+func (t *puzzleTagger) sacrifice(positions []*Position, solver Color) {
+	diff := func(pos *Position) int {
+		return DefaultEvalParams.Material(pos.board, solver) - DefaultEvalParams.Material(pos.board, solver.Other())
+	}
+	start := diff(positions[0])
+	for i := 2; i < len(positions); i += 2 {
+		if diff(positions[i]) <= start-puzzleSacrificeCentipawns {
+			t.add(ThemeSacrifice)
+			return
+		}
+	}
+}
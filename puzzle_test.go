@@ -0,0 +1,67 @@
+package chess
+
+import "testing"
+
+func TestPuzzleThemes(t *testing.T) {
+	tests := []struct {
+		fen      string
+		solution []string
+		themes   []PuzzleTheme
+		absent   []PuzzleTheme
+	}{
+		{"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", []string{"Ra8#"},
+			[]PuzzleTheme{ThemeMate, "mateIn1", ThemeBackRankMate, ThemeOneMove, ThemeEndgame, ThemeRookEndgame}, nil},
+		{"r3k3/8/8/1N6/8/8/8/6K1 w - - 0 1", []string{"Nc7+", "Kd7", "Nxa8"},
+			[]PuzzleTheme{ThemeFork, ThemeShort}, []PuzzleTheme{ThemeMate, ThemeDeflection}},
+		{"4k3/8/4n3/8/8/8/8/3QK3 w - - 0 1", []string{"Qe2"},
+			[]PuzzleTheme{ThemePin}, []PuzzleTheme{ThemeSkewer}},
+		{"3q4/8/8/3k4/8/8/8/K6R w - - 0 1", []string{"Rd1+", "Ke4", "Rxd8"},
+			[]PuzzleTheme{ThemeSkewer}, []PuzzleTheme{ThemePin}},
+		{"8/5P1k/8/8/8/8/8/K7 w - - 0 1", []string{"f8=N+"},
+			[]PuzzleTheme{ThemePromotion, ThemeUnderPromotion, ThemePawnEndgame}, nil},
+		{"r6k/6pp/7N/3Q4/8/8/8/6K1 w - - 0 1", []string{"Qg8+", "Rxg8", "Nf7#"},
+			[]PuzzleTheme{ThemeMate, "mateIn2", ThemeSmotheredMate, ThemeSacrifice, ThemeShort}, []PuzzleTheme{ThemeDeflection}},
+		{"4k3/8/8/8/4B3/8/8/4R1K1 w - - 0 1", []string{"Bc6+"},
+			[]PuzzleTheme{ThemeDoubleCheck, ThemeDiscoveredAttack}, nil},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6", "Ba4"},
+			[]PuzzleTheme{ThemeOpening, ThemeVeryLong}, []PuzzleTheme{ThemeSacrifice, ThemeMate}},
+	}
+	for _, test := range tests {
+		fen, err := FEN(test.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pos := NewGame(fen).Position()
+		solution := []*Move{}
+		cur := pos
+		for _, s := range test.solution {
+			m, err := AlgebraicNotation{}.Decode(cur, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			solution = append(solution, m)
+			cur = cur.Update(m)
+		}
+		themes, err := PuzzleThemes(pos, solution)
+		if err != nil {
+			t.Fatal(err)
+		}
+		has := map[PuzzleTheme]bool{}
+		for _, theme := range themes {
+			has[theme] = true
+		}
+		for _, theme := range test.themes {
+			if !has[theme] {
+				t.Fatalf("expected %s to have theme %s but got %v", test.fen, theme, themes)
+			}
+		}
+		for _, theme := range test.absent {
+			if has[theme] {
+				t.Fatalf("expected %s not to have theme %s but got %v", test.fen, theme, themes)
+			}
+		}
+	}
+	if _, err := PuzzleThemes(StartingPosition(), nil); err == nil {
+		t.Fatal("expected an error for an empty solution")
+	}
+}
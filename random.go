@@ -0,0 +1,145 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+type randomPosition struct {
+	pieces []Piece
+	// count is the number of pieces from the pool if hasCount is set
+	count    int
+	hasCount bool
+	turn     Color
+	noMate   bool
+}
+
+// RandomPieces is an option for RandomPosition that sets the pieces other
+// than the kings to place.
+func RandomPieces(pieces ...Piece) func(*randomPosition) {
+	return func(r *randomPosition) {
+		r.pieces = pieces
+	}
+}
+
+// RandomPieceCount is an option for RandomPosition that places n pieces
+// other than the kings chosen from a standard set of 30.  Lower counts
+// produce endgames and higher counts middlegames.  It is ignored if
+// RandomPieces is given.  RandomPosition returns an error if n isn't
+// between 0 and 30.
+func RandomPieceCount(n int) func(*randomPosition) {
+	return func(r *randomPosition) {
+		r.count = n
+		r.hasCount = true
+	}
+}
+
+// RandomTurn is an option for RandomPosition that sets the color to move.
+// By default the color to move is random.
+func RandomTurn(c Color) func(*randomPosition) {
+	return func(r *randomPosition) {
+		r.turn = c
+	}
+}
+
+// RandomNoMate is an option for RandomPosition that rejects positions
+// that are checkmate, stalemate or have a mate in one for the color to
+// move.
+func RandomNoMate(r *randomPosition) {
+	r.noMate = true
+}
+
+const randomPositionAttempts = 10000
+
+var randomPiecePool = []Piece{
+	WhiteQueen, WhiteRook, WhiteRook, WhiteBishop, WhiteBishop, WhiteKnight, WhiteKnight,
+	WhitePawn, WhitePawn, WhitePawn, WhitePawn, WhitePawn, WhitePawn, WhitePawn, WhitePawn,
+	BlackQueen, BlackRook, BlackRook, BlackBishop, BlackBishop, BlackKnight, BlackKnight,
+	BlackPawn, BlackPawn, BlackPawn, BlackPawn, BlackPawn, BlackPawn, BlackPawn, BlackPawn,
+}
+
+// RandomPosition returns a random legal position generated from the random
+// source so the same seed produces the same position.  Positions are
+// legal in the sense of SetupPosition: they may not be reachable from the
+// starting position.  Castling and en passant aren't possible in the
+// generated positions.  An error is returned if no position satisfying
+// the options is found.
+func RandomPosition(r *rand.Rand, opts ...func(*randomPosition)) (*Position, error) {
+	rp := &randomPosition{}
+	for _, f := range opts {
+		if f != nil {
+			f(rp)
+		}
+	}
+	if rp.hasCount && (rp.count < 0 || rp.count > len(randomPiecePool)) {
+		return nil, fmt.Errorf("chess: random position piece count %d isn't between 0 and 30", rp.count)
+	}
+	for i := 0; i < randomPositionAttempts; i++ {
+		pos := rp.generate(r)
+		if pos == nil {
+			continue
+		}
+		if rp.noMate && (pos.Status() != NoMethod || hasMateInOne(pos)) {
+			continue
+		}
+		return pos, nil
+	}
+	return nil, errors.New("chess: failed to generate a random position for the constraints")
+}
+
+func (rp *randomPosition) generate(r *rand.Rand) *Position {
+	pieces := rp.pieces
+	if pieces == nil {
+		count := rp.count
+		if !rp.hasCount {
+			count = r.Intn(len(randomPiecePool) + 1)
+		}
+		perm := r.Perm(len(randomPiecePool))
+		pieces = []Piece{}
+		for _, i := range perm[:count] {
+			pieces = append(pieces, randomPiecePool[i])
+		}
+	}
+	m := map[Square]Piece{}
+	squares := r.Perm(numOfSquaresInBoard)
+	next := 0
+	place := func(p Piece) bool {
+		for ; next < len(squares); next++ {
+			sq := Square(squares[next])
+			if p.Type() == Pawn && (sq.Rank() == Rank1 || sq.Rank() == Rank8) {
+				continue
+			}
+			m[sq] = p
+			next++
+			return true
+		}
+		return false
+	}
+	for _, p := range append([]Piece{WhiteKing, BlackKing}, pieces...) {
+		if !place(p) {
+			return nil
+		}
+	}
+	turn := rp.turn
+	if turn == NoColor {
+		turn = White
+		if r.Intn(2) == 1 {
+			turn = Black
+		}
+	}
+	pos, err := SetupPosition(m, SetupTurn(turn))
+	if err != nil {
+		return nil
+	}
+	return pos
+}
+
+func hasMateInOne(pos *Position) bool {
+	for _, m := range pos.ValidMoves() {
+		if m.HasTag(Check) && pos.Update(m).Status() == Checkmate {
+			return true
+		}
+	}
+	return false
+}
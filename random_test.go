@@ -0,0 +1,49 @@
+package chess
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomPosition(t *testing.T) {
+	p1, err := RandomPosition(rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := RandomPosition(rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1.String() != p2.String() {
+		t.Fatalf("expected the same seed to generate the same position but got %s and %s", p1, p2)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		pos, err := RandomPosition(r, RandomPieceCount(6), RandomTurn(Black), RandomNoMate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(pos.Board().SquareMap()); n != 8 {
+			t.Fatalf("expected 8 pieces but got %d in %s", n, pos)
+		}
+		if pos.Turn() != Black || pos.Status() != NoMethod || hasMateInOne(pos) {
+			t.Fatalf("position %s doesn't satisfy the constraints", pos)
+		}
+	}
+	pos, err := RandomPosition(r, RandomPieces(WhiteRook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pos.Board().SquareMap()) != 3 {
+		t.Fatalf("expected kings and a rook but got %s", pos)
+	}
+	if _, err := RandomPosition(r, RandomPieceCount(31)); err == nil {
+		t.Fatal("expected too many pieces to return an error")
+	}
+	if _, err := RandomPosition(r, RandomPieceCount(-5)); err == nil {
+		t.Fatal("expected a negative piece count to return an error")
+	}
+	if pos, err := RandomPosition(r, RandomPieceCount(0)); err != nil || len(pos.Board().SquareMap()) != 2 {
+		t.Fatalf("expected only kings but got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package chess
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// Recognizer is the interface implemented by position recognition
+// providers (OCR or computer vision backends).  Given an image of a
+// board it returns the position in FEN notation.  Since images rarely
+// show the side to move or castling rights, a recognizer may return only
+// the board section of the FEN (ex. rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR)
+// in which case white to move, no castling rights, and no en passant
+// square are assumed.
+type Recognizer interface {
+	Recognize(img image.Image) (string, error)
+}
+
+// RecognizerFunc is an adapter to allow the use of ordinary functions as
+// Recognizers.
+type RecognizerFunc func(img image.Image) (string, error)
+
+// Recognize implements the Recognizer interface.
+func (f RecognizerFunc) Recognize(img image.Image) (string, error) {
+	return f(img)
+}
+
+// RecognizePosition returns the position the recognizer finds in the image.
+// An error is returned if recognition fails or the recognizer's output isn't
+// valid FEN.
+func RecognizePosition(r Recognizer, img image.Image) (*Position, error) {
+	fen, err := r.Recognize(img)
+	if err != nil {
+		return nil, fmt.Errorf("chess: position recognition failed %w", err)
+	}
+	fen = strings.TrimSpace(fen)
+	if len(strings.Fields(fen)) == 1 {
+		fen += " w - - 0 1"
+	}
+	pos, err := decodeFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// Image takes a recognizer and an image and returns a function that updates
+// the game to reflect the recognized position.  Like FEN, the move list will
+// be empty.  The returned function is designed to be used in the NewGame
+// constructor.  An error is returned if there is a problem recognizing the
+// position.
+func Image(r Recognizer, img image.Image) (func(*Game), error) {
+	pos, err := RecognizePosition(r, img)
+	if err != nil {
+		return nil, err
+	}
+	return func(g *Game) {
+		g.pos = pos
+		g.positions = []*Position{pos}
+		g.lastIrreversible = 0
+		g.updatePosition()
+	}, nil
+}
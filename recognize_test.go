@@ -0,0 +1,29 @@
+package chess
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestRecognizer(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	r := RecognizerFunc(func(image.Image) (string, error) {
+		return "4k3/8/8/8/8/8/8/4K2R", nil
+	})
+	opt, err := Image(r, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	expected := "4k3/8/8/8/8/8/8/4K2R w - - 0 1"
+	if g.FEN() != expected {
+		t.Fatalf("expected fen %s but got %s", expected, g.FEN())
+	}
+	failing := RecognizerFunc(func(image.Image) (string, error) {
+		return "", errors.New("no board found")
+	})
+	if _, err := Image(failing, img); err == nil {
+		t.Fatal("expected recognition error")
+	}
+}
@@ -0,0 +1,67 @@
+package chess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	notationsMu sync.RWMutex
+	notations   = map[string]Notation{
+		"san":         AlgebraicNotation{},
+		"lan":         LongAlgebraicNotation{},
+		"uci":         UCINotation{},
+		"figurine":    FigurineNotation{},
+		"iccf":        ICCFNotation{},
+		"descriptive": DescriptiveNotation{},
+		"multi":       MultiNotation{},
+	}
+)
+
+// RegisterNotation makes the notation available by name to NotationByName
+// so that it can be chosen by a configuration string.  Names aren't case
+// sensitive, surrounding whitespace is ignored and registering a name again replaces its notation, such as
+// "san" with AlgebraicNotation{Strict: true}.  The notations san, lan,
+// uci, figurine, iccf, descriptive and multi are registered by default.
+// RegisterNotation panics if the name is empty or the notation is nil.
+func RegisterNotation(name string, n Notation) {
+	key := notationKey(name)
+	if key == "" || n == nil {
+		panic("chess: RegisterNotation requires a name and a notation")
+	}
+	notationsMu.Lock()
+	defer notationsMu.Unlock()
+	notations[key] = n
+}
+
+// NotationByName returns the notation registered with the name.  An error
+// is returned if there isn't one.
+func NotationByName(name string) (Notation, error) {
+	notationsMu.RLock()
+	defer notationsMu.RUnlock()
+	n, ok := notations[notationKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("chess: unknown notation %q", name)
+	}
+	return n, nil
+}
+
+// NotationNames returns the names of the registered notations in
+// alphabetical order.
+func NotationNames() []string {
+	notationsMu.RLock()
+	defer notationsMu.RUnlock()
+	names := []string{}
+	for name := range notations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// notationKey returns the name as it's registered.
+func notationKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,55 @@
+package chess
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotationRegistry(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"san", "Nf3"},
+		{"SAN", "Nf3"},
+		{"lan", "Ng1-f3"},
+		{"uci", "g1f3"},
+		{"figurine", "♘f3"},
+		{"iccf", "7163"},
+	}
+	pos := StartingPosition()
+	for _, test := range tests {
+		n, err := NotationByName(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := n.Decode(pos, test.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := n.Encode(pos, m); s != test.text {
+			t.Fatalf("expected %s notation to encode %s but got %s", test.name, test.text, s)
+		}
+	}
+	if _, err := NotationByName("klingon"); err == nil {
+		t.Fatal("expected an error for an unknown notation")
+	}
+
+	RegisterNotation(" German ", LocalizedNotation{Letters: GermanPieceLetters})
+	defer func() {
+		notationsMu.Lock()
+		delete(notations, "german")
+		notationsMu.Unlock()
+	}()
+	n, err := NotationByName("german ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.Decode(pos, "Sf3"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"descriptive", "figurine", "german", "iccf", "lan", "multi", "san", "uci"}
+	if names := NotationNames(); !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected names %v but got %v", want, names)
+	}
+}
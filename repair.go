@@ -0,0 +1,64 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RepairReport describes the changes made by RepairPGN.
+type RepairReport struct {
+	// Changes describes each repair in the order it was made.
+	Changes []string
+}
+
+// Changed returns true if any repairs were made.
+func (r *RepairReport) Changed() bool {
+	return len(r.Changes) > 0
+}
+
+func (r *RepairReport) add(format string, a ...interface{}) {
+	r.Changes = append(r.Changes, fmt.Sprintf(format, a...))
+}
+
+// RepairPGN makes a best effort to decode a broken game so that bulk
+// imports salvage as much data as possible.  Move numbers that don't
+// match the moves are ignored, movetext after the last legal move is
+// dropped, the SetUp tag is reconciled with the FEN tag and the result
+// is reconciled as with FixResult.  The returned report describes what
+// was changed.  An error is returned if the tag pairs or FEN tag can't be
+// decoded.
+func RepairPGN(pgn string, opts ...func(*pgnDecoder)) (*Game, *RepairReport, error) {
+	d := newPGNDecoder(opts...)
+	d.partial = true
+	g, p, err := d.parse(pgn)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &RepairReport{}
+	if p.misnumbered > 0 {
+		r.add("ignored %d move numbers that don't match the moves", p.misnumbered)
+	}
+	if p.skipped != nil {
+		r.add("dropped movetext after move %d: %s", len(g.moves), p.skipped)
+	}
+	fen := ""
+	for _, tp := range g.tagPairs {
+		if strings.ToLower(tp.Key) == "fen" {
+			fen = tp.Value
+			break
+		}
+	}
+	setUp := g.GetTagPair("SetUp")
+	switch {
+	case fen != "" && (setUp == nil || setUp.Value != "1"):
+		g.AddTagPair("SetUp", "1")
+		r.add("set SetUp tag to 1 for FEN tag")
+	case fen == "" && setUp != nil && setUp.Value != "0":
+		g.RemoveTagPair("SetUp")
+		r.add("removed SetUp tag without FEN tag")
+	}
+	if result := g.FixResult(); !result.Consistent() {
+		r.add("set result to %s: %s", g.outcome, strings.Join(result.Problems, ", "))
+	}
+	return g, r, nil
+}
@@ -0,0 +1,42 @@
+package chess
+
+import (
+	"testing"
+)
+
+func TestRepairPGN(t *testing.T) {
+	tests := []struct {
+		pgn     string
+		moves   int
+		changes int
+		outcome Outcome
+	}{
+		{"[Result \"1-0\"]\n\n1. e4 e5 2. Nf3 1-0", 3, 0, WhiteWon},
+		{"[Result \"1-0\"]\n\n1. e4 e5 3. Nf3 1-0", 3, 1, WhiteWon},
+		{"[Result \"1-0\"]\n\n1. e4 e5 2. Nf3 Ke7 3. Ke3 1-0", 4, 2, WhiteWon},
+		{"[Result \"*\"]\n[SetUp \"1\"]\n\n1. e4 *", 1, 1, NoOutcome},
+		{"[Result \"*\"]\n[FEN \"4k3/8/8/8/8/8/4P3/4K3 w - - 0 1\"]\n\n1. e4 *", 1, 1, NoOutcome},
+		{"[Result \"1-0\"]\n\n1. e4 e5 {unterminated", 2, 2, WhiteWon},
+	}
+	for i, test := range tests {
+		g, r, err := RepairPGN(test.pgn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(g.Moves()) != test.moves {
+			t.Fatalf("test %d expected %d moves but got %d", i, test.moves, len(g.Moves()))
+		}
+		if len(r.Changes) != test.changes {
+			t.Fatalf("test %d expected %d changes but got %v", i, test.changes, r.Changes)
+		}
+		if g.Outcome() != test.outcome {
+			t.Fatalf("test %d expected outcome %s but got %s", i, test.outcome, g.Outcome())
+		}
+	}
+	if _, r, _ := RepairPGN("1. e4 e5 3. Nf3 *"); r.Changes[0] != "ignored 1 move numbers that don't match the moves" {
+		t.Fatalf("expected the misnumbered move to be reported but got %v", r.Changes)
+	}
+	if _, _, err := RepairPGN("[FEN \"invalid\"]\n\n1. e4 *"); err == nil {
+		t.Fatal("expected an invalid FEN tag to return an error")
+	}
+}
@@ -0,0 +1,93 @@
+package chess
+
+import "fmt"
+
+// ResultReport is the result of cross checking the sources of a game's
+// result: the Result tag, the game's outcome (the result token of decoded
+// movetext) and the outcome determined by the final position.
+type ResultReport struct {
+	// Tag is the value of the Result tag or an empty string if the tag
+	// is missing.
+	Tag string
+	// Outcome is the game's outcome.
+	Outcome Outcome
+	// Computed is the outcome required by the final position or
+	// NoOutcome if the final position doesn't end the game.
+	Computed Outcome
+	// Method is the method that determines Computed.
+	Method Method
+	// Problems describes each mismatch.
+	Problems []string
+}
+
+// Consistent returns true if no mismatches were found.
+func (r *ResultReport) Consistent() bool {
+	return len(r.Problems) == 0
+}
+
+// CheckResult cross checks the game's Result tag, outcome and final
+// position and reports any mismatches.  Mismatches are common in scraped
+// databases, for example a Result tag of "*" for a finished game or a
+// result token that contradicts a final checkmate.
+func (g *Game) CheckResult() *ResultReport {
+	r := &ResultReport{Outcome: g.outcome, Computed: NoOutcome}
+	if tp := g.GetTagPair("Result"); tp != nil {
+		r.Tag = tp.Value
+	}
+	switch g.pos.Status() {
+	case Checkmate:
+		r.Method = Checkmate
+		r.Computed = WhiteWon
+		if g.pos.Turn() == White {
+			r.Computed = BlackWon
+		}
+	case Stalemate:
+		r.Method = Stalemate
+		r.Computed = Draw
+	default:
+		if !g.pos.board.hasSufficientMaterial() {
+			r.Method = InsufficientMaterial
+			r.Computed = Draw
+		}
+	}
+	switch {
+	case r.Tag == "":
+		r.Problems = append(r.Problems, "missing Result tag")
+	case !isOutcome(r.Tag):
+		r.Problems = append(r.Problems, fmt.Sprintf("invalid Result tag %q", r.Tag))
+	case Outcome(r.Tag) != r.Outcome:
+		r.Problems = append(r.Problems, fmt.Sprintf("Result tag %s doesn't match result %s", r.Tag, r.Outcome))
+	}
+	if r.Computed != NoOutcome && r.Outcome != r.Computed {
+		r.Problems = append(r.Problems, fmt.Sprintf("result %s doesn't match %s by %s", r.Outcome, r.Computed, r.Method))
+	}
+	return r
+}
+
+// FixResult reconciles the game's result sources and returns the report
+// from before the fix.  The final position takes precedence, followed by
+// the game's outcome and then a valid Result tag.  The Result tag is
+// updated to match.
+func (g *Game) FixResult() *ResultReport {
+	r := g.CheckResult()
+	if r.Consistent() {
+		return r
+	}
+	switch {
+	case r.Computed != NoOutcome:
+		g.outcome = r.Computed
+		g.method = r.Method
+	case r.Outcome == NoOutcome && isOutcome(r.Tag):
+		g.outcome = Outcome(r.Tag)
+	}
+	g.AddTagPair("Result", string(g.outcome))
+	return r
+}
+
+func isOutcome(s string) bool {
+	switch Outcome(s) {
+	case NoOutcome, WhiteWon, BlackWon, Draw:
+		return true
+	}
+	return false
+}
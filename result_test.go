@@ -0,0 +1,38 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckResult(t *testing.T) {
+	tests := []struct {
+		pgn        string
+		consistent bool
+		fixed      Outcome
+	}{
+		{"[Result \"1-0\"]\n\n1. e4 e5 1-0", true, WhiteWon},
+		{"[Result \"*\"]\n\n1. e4 e5 1-0", false, WhiteWon},
+		{"[Result \"1-0\"]\n\n1. e4 e5 *", false, WhiteWon},
+		{"1. e4 e5 0-1", false, BlackWon},
+		{"[Result \"1/2-1/2\"]\n\n1. f3 e5 2. g4 Qh4# 1/2-1/2", false, BlackWon},
+		{"[Result \"0-1\"]\n\n1. f3 e5 2. g4 Qh4# 0-1", true, BlackWon},
+	}
+	for i, test := range tests {
+		opt, err := PGN(strings.NewReader(test.pgn))
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := NewGame(opt)
+		if r := g.CheckResult(); r.Consistent() != test.consistent {
+			t.Fatalf("test %d expected consistent to be %v but got problems %v", i, test.consistent, r.Problems)
+		}
+		g.FixResult()
+		if g.Outcome() != test.fixed {
+			t.Fatalf("test %d expected fixed outcome %s but got %s", i, test.fixed, g.Outcome())
+		}
+		if r := g.CheckResult(); !r.Consistent() {
+			t.Fatalf("test %d expected fixed game to be consistent but got %v", i, r.Problems)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package chess
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A ReviewCard is the review schedule of a puzzle or repertoire line.
+type ReviewCard struct {
+	// ID identifies the puzzle or line.
+	ID string
+	// EaseFactor scales the interval after each successful review.  It
+	// starts at 2.5 and is never less than 1.3.
+	EaseFactor float64
+	// IntervalDays is the number of days between the last review and the
+	// next.
+	IntervalDays int
+	// Repetitions is the number of successful reviews in a row.
+	Repetitions int
+	// Due is when the card should next be reviewed.
+	Due time.Time
+	// LastReview is when the card was last reviewed or the zero time if
+	// it hasn't been.
+	LastReview time.Time
+}
+
+// The grades of a review from 0 to 5 used by the SM-2 algorithm.  Grades
+// below ReviewHard are failures which restart the card's schedule.
+const (
+	ReviewBlackout  = 0
+	ReviewIncorrect = 1
+	ReviewFamiliar  = 2
+	ReviewHard      = 3
+	ReviewGood      = 4
+	ReviewPerfect   = 5
+)
+
+const (
+	initialEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+// A Scheduler schedules reviews of puzzles and repertoire lines by ID
+// with the SM-2 spaced repetition algorithm.  It is safe for concurrent
+// use.
+type Scheduler struct {
+	mu    sync.Mutex
+	cards map[string]*ReviewCard
+	now   func() time.Time
+}
+
+// NewScheduler returns a scheduler without cards.  Options such as
+// SchedulerCards and SchedulerClock configure it.
+func NewScheduler(opts ...func(*Scheduler)) *Scheduler {
+	s := &Scheduler{cards: map[string]*ReviewCard{}, now: time.Now}
+	for _, f := range opts {
+		if f != nil {
+			f(s)
+		}
+	}
+	return s
+}
+
+// SchedulerCards is an option for NewScheduler that restores cards, such
+// as those saved from the Cards method.
+func SchedulerCards(cards ...ReviewCard) func(*Scheduler) {
+	return func(s *Scheduler) {
+		for _, c := range cards {
+			cp := c
+			s.cards[c.ID] = &cp
+		}
+	}
+}
+
+// SchedulerClock is an option for NewScheduler that sets the function
+// returning the current time.  The default is time.Now.
+func SchedulerClock(now func() time.Time) func(*Scheduler) {
+	return func(s *Scheduler) {
+		s.now = now
+	}
+}
+
+// Add adds a card for the ID that is due immediately.  Adding an ID that
+// already has a card does nothing.
+func (s *Scheduler) Add(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.card(id)
+}
+
+// Remove removes the ID's card.
+func (s *Scheduler) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cards, id)
+}
+
+// Record records a review of the ID graded from ReviewBlackout to
+// ReviewPerfect and returns the updated card.  A card is added for IDs
+// without one.  An error is returned if the grade is out of range.
+func (s *Scheduler) Record(id string, grade int) (ReviewCard, error) {
+	if grade < ReviewBlackout || grade > ReviewPerfect {
+		return ReviewCard{}, fmt.Errorf("chess: review grade %d isn't between %d and %d", grade, ReviewBlackout, ReviewPerfect)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.card(id)
+	if grade >= ReviewHard {
+		switch c.Repetitions {
+		case 0:
+			c.IntervalDays = 1
+		case 1:
+			c.IntervalDays = 6
+		default:
+			c.IntervalDays = int(math.Round(float64(c.IntervalDays) * c.EaseFactor))
+		}
+		c.Repetitions++
+	} else {
+		c.Repetitions = 0
+		c.IntervalDays = 1
+	}
+	q := float64(ReviewPerfect - grade)
+	c.EaseFactor = math.Max(minEaseFactor, c.EaseFactor+0.1-q*(0.08+q*0.02))
+	c.LastReview = s.now()
+	c.Due = c.LastReview.AddDate(0, 0, c.IntervalDays)
+	return *c, nil
+}
+
+// Card returns the ID's card and false if it doesn't have one.
+func (s *Scheduler) Card(id string) (ReviewCard, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cards[id]
+	if !ok {
+		return ReviewCard{}, false
+	}
+	return *c, true
+}
+
+// Cards returns every card ordered by due date and then ID.
+func (s *Scheduler) Cards() []ReviewCard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cards := make([]ReviewCard, 0, len(s.cards))
+	for _, c := range s.cards {
+		cards = append(cards, *c)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if !cards[i].Due.Equal(cards[j].Due) {
+			return cards[i].Due.Before(cards[j].Due)
+		}
+		return cards[i].ID < cards[j].ID
+	})
+	return cards
+}
+
+// Due returns the cards that are due now ordered by due date and then ID.
+func (s *Scheduler) Due() []ReviewCard {
+	now := s.now()
+	due := []ReviewCard{}
+	for _, c := range s.Cards() {
+		if c.Due.After(now) {
+			break
+		}
+		due = append(due, c)
+	}
+	return due
+}
+
+// card returns the ID's card adding it if needed.  The lock must be held.
+func (s *Scheduler) card(id string) *ReviewCard {
+	c, ok := s.cards[id]
+	if !ok {
+		c = &ReviewCard{ID: id, EaseFactor: initialEaseFactor, Due: s.now()}
+		s.cards[id] = c
+	}
+	return c
+}
@@ -0,0 +1,52 @@
+package chess
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewScheduler(SchedulerClock(func() time.Time { return now }))
+	s.Add("puzzle-1")
+	s.Add("puzzle-2")
+	if due := s.Due(); len(due) != 2 || due[0].ID != "puzzle-1" {
+		t.Fatalf("expected both new cards to be due but got %v", due)
+	}
+	intervals := []int{1, 6, 15}
+	for _, interval := range intervals {
+		c, err := s.Record("puzzle-1", ReviewGood)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.IntervalDays != interval || !c.Due.Equal(now.AddDate(0, 0, interval)) {
+			t.Fatalf("expected an interval of %d days but got %d", interval, c.IntervalDays)
+		}
+	}
+	c, err := s.Record("puzzle-1", ReviewIncorrect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Repetitions != 0 || c.IntervalDays != 1 || c.EaseFactor != 1.96 {
+		t.Fatalf("expected a failed review to restart the card but got %+v", c)
+	}
+	if _, err := s.Record("puzzle-1", 6); err == nil {
+		t.Fatal("expected an error for an invalid grade")
+	}
+	if due := s.Due(); len(due) != 1 || due[0].ID != "puzzle-2" {
+		t.Fatalf("expected only puzzle-2 to be due but got %v", due)
+	}
+	now = now.AddDate(0, 0, 1)
+	if due := s.Due(); len(due) != 2 || due[0].ID != "puzzle-2" {
+		t.Fatalf("expected both cards to be due but got %v", due)
+	}
+
+	restored := NewScheduler(SchedulerCards(s.Cards()...))
+	if c, ok := restored.Card("puzzle-1"); !ok || c.EaseFactor != 1.96 {
+		t.Fatalf("expected the restored card but got %+v", c)
+	}
+	restored.Remove("puzzle-1")
+	if _, ok := restored.Card("puzzle-1"); ok {
+		t.Fatal("expected the card to be removed")
+	}
+}
@@ -0,0 +1,167 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeSAN returns the canonical algebraic notation of the legal move
+// described by s in the position so that move strings from users and
+// databases can be compared.  Annotations and check symbols are removed,
+// disambiguation is added or removed as needed, castling may be written
+// with zeros and the move may be given in long algebraic or UCI notation.
+// For example Ng1f3, Ngf3, N-f3 and g1f3 all normalize to Nf3 in the
+// starting position.  An error is returned if s doesn't describe exactly
+// one legal move.
+func NormalizeSAN(pos *Position, s string) (string, error) {
+	m, err := decodeLenientSAN(pos, s)
+	if err != nil {
+		return "", err
+	}
+	return AlgebraicNotation{}.Encode(pos, m), nil
+}
+
+// decodeLenientSAN returns the only legal move that matches the text.
+func decodeLenientSAN(pos *Position, s string) (*Move, error) {
+	text := removeSubstrings(strings.TrimSpace(s), "?", "!", "+", "#", "e.p.")
+	castle := strings.Replace(text, "0", "O", -1)
+	if castle == "O-O" || castle == "O-O-O" {
+		tag := KingSideCastle
+		if castle == "O-O-O" {
+			tag = QueenSideCastle
+		}
+		for _, m := range pos.ValidMoves() {
+			if m.HasTag(tag) {
+				return m, nil
+			}
+		}
+		return nil, fmt.Errorf("chess: %s isn't a legal move for position %s", s, pos)
+	}
+	pt := NoPieceType
+	if len(text) > 0 && strings.ContainsRune("KQRBN", rune(text[0])) {
+		pt = pieceTypeFromChar(strings.ToLower(text[:1]))
+		if text[0] == 'K' {
+			pt = King
+		}
+		text = text[1:]
+	}
+	promo := NoPieceType
+	if i := strings.LastIndexAny(text, "12345678"); i != -1 && i < len(text)-1 {
+		promo = pieceTypeFromChar(strings.ToLower(strings.TrimPrefix(text[i+1:], "=")))
+		if promo == NoPieceType {
+			return nil, fmt.Errorf("chess: invalid promotion in %s", s)
+		}
+		text = text[:i+1]
+	}
+	if len(text) < 2 {
+		return nil, fmt.Errorf("chess: invalid move text %s", s)
+	}
+	s2, ok := strToSquareMap[text[len(text)-2:]]
+	if !ok {
+		return nil, fmt.Errorf("chess: invalid move text %s", s)
+	}
+	from := strings.TrimRight(text[:len(text)-2], "x-:")
+	if len(from) > 2 {
+		return nil, fmt.Errorf("chess: invalid move text %s", s)
+	}
+	if s1, ok := strToSquareMap[from]; ok && pt == NoPieceType {
+		// long algebraic and UCI moves may omit the piece
+		pt = pos.board.Piece(s1).Type()
+	} else if pt == NoPieceType {
+		pt = Pawn
+	}
+	var match *Move
+	for _, m := range pos.ValidMoves() {
+		if m.s2 != s2 || m.promo != promo || pos.board.Piece(m.s1).Type() != pt {
+			continue
+		}
+		if !strings.HasPrefix(m.s1.String(), from) && !strings.HasSuffix(m.s1.String(), from) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("chess: %s is ambiguous for position %s", s, pos)
+		}
+		match = m
+	}
+	if match == nil {
+		return nil, fmt.Errorf("chess: %s isn't a legal move for position %s", s, pos)
+	}
+	return match, nil
+}
+
+// sanPieceTypes are the piece types of the letters of algebraic notation.
+var sanPieceTypes = map[byte]PieceType{'K': King, 'Q': Queen, 'R': Rook, 'B': Bishop, 'N': Knight}
+
+// decodeSAN returns the valid move that is encoded as the text in
+// algebraic notation, ignoring check symbols and annotations.  Rather than
+// encoding every valid move it parses the piece, destination, capture and
+// disambiguation and checks them against the moves to the destination, so
+// the disambiguation is only computed for the matching move.
+func decodeSAN(pos *Position, s string) (*Move, error) {
+	text, suffix := splitMoveSuffix(s)
+	text = removeSubstrings(text, "?", "!", "+", "#", "e.p.")
+	err := fmt.Errorf("chess: could not decode algebraic notation %s for position %s", text, pos)
+	if castle := strings.Replace(text, "0", "O", -1); castle == "O-O" || castle == "O-O-O" {
+		tag := KingSideCastle
+		if castle == "O-O-O" {
+			tag = QueenSideCastle
+		}
+		for _, m := range pos.ValidMoves() {
+			if m.HasTag(tag) {
+				return withSuffix(m, suffix), nil
+			}
+		}
+		return nil, err
+	}
+	pt := Pawn
+	if len(text) > 0 {
+		if t, ok := sanPieceTypes[text[0]]; ok {
+			pt = t
+			text = text[1:]
+		}
+	}
+	promo := NoPieceType
+	if i := strings.IndexByte(text, '='); i != -1 {
+		t, ok := sanPieceTypes[text[len(text)-1]]
+		if !ok || t == King || pt != Pawn || i != len(text)-2 {
+			return nil, err
+		}
+		promo = t
+		text = text[:i]
+	}
+	if len(text) < 2 {
+		return nil, err
+	}
+	s2, ok := strToSquareMap[text[len(text)-2:]]
+	if !ok {
+		return nil, err
+	}
+	from := text[:len(text)-2]
+	capture := strings.HasSuffix(from, "x")
+	from = strings.TrimSuffix(from, "x")
+	if pt == Pawn && capture != (len(from) == 1) {
+		// pawn captures give the file and other pawn moves nothing
+		return nil, err
+	}
+	for _, m := range pos.ValidMoves() {
+		if m.s2 != s2 || m.promo != promo || pos.board.Piece(m.s1).Type() != pt {
+			continue
+		}
+		if capture != (m.HasTag(Capture) || m.HasTag(EnPassant)) {
+			continue
+		}
+		if pt == Pawn {
+			if from == "" || from == m.s1.File().String() {
+				return withSuffix(m, suffix), nil
+			}
+			continue
+		}
+		if !strings.HasPrefix(m.s1.String(), from) && !strings.HasSuffix(m.s1.String(), from) {
+			continue
+		}
+		if formS1(pos, m) == from {
+			return withSuffix(m, suffix), nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,50 @@
+package chess
+
+import "testing"
+
+func TestNormalizeSAN(t *testing.T) {
+	tests := []struct {
+		fen      string
+		input    string
+		expected string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Nf3", "Nf3"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Ng1f3", "Nf3"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Ngf3!?", "Nf3"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "N-f3", "Nf3"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "g1f3", "Nf3"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e2-e4", "e4"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "ed5", "exd5"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "e4xd5", "exd5"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "0-0", "O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "0-0-0+", "O-O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", "O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "Rd1", "Rd1"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "Rhf1", "Rf1"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "Rxa8", "Rxa8+"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b8Q", "b8=Q+"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b7b8n", "b8=N"},
+		{"4k3/8/8/8/8/8/8/R4RK1 w - - 0 1", "a1b1", "Rab1"},
+	}
+	for _, test := range tests {
+		s, err := NormalizeSAN(unsafeFEN(test.fen), test.input)
+		if err != nil {
+			t.Fatalf("%s: %s", test.input, err)
+		}
+		if s != test.expected {
+			t.Fatalf("expected %s to normalize to %s but got %s", test.input, test.expected, s)
+		}
+	}
+}
+
+func TestNormalizeSANErrors(t *testing.T) {
+	pos := unsafeFEN("r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1")
+	for _, s := range []string{"Nf3", "e4", "Ra9", "", "b8=K", "O-O-O-O"} {
+		if _, err := NormalizeSAN(pos, s); err == nil {
+			t.Fatalf("expected an error normalizing %q", s)
+		}
+	}
+	if _, err := NormalizeSAN(unsafeFEN("4k3/8/8/8/8/8/8/R4RK1 w - - 0 1"), "Rb1"); err == nil {
+		t.Fatal("expected an error normalizing an ambiguous move")
+	}
+}
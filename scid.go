@@ -0,0 +1,93 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScidFlags are the flags that Scid databases use to curate games, such as
+// marking a game as an endgame study or as containing a novelty.  They are
+// written in the ScidFlags tag pair as a letter for each flag, such as
+// "EN" for the endgame and novelty flags, which Scid reads back when the
+// PGN is imported.
+type ScidFlags uint32
+
+// The Scid flags in the order of their letters DWBMENPTKQ!?U123456.
+const (
+	ScidDeleted ScidFlags = 1 << iota
+	ScidWhiteOpening
+	ScidBlackOpening
+	ScidMiddlegame
+	ScidEndgame
+	ScidNovelty
+	ScidPawnStructure
+	ScidTactics
+	ScidKingside
+	ScidQueenside
+	ScidBrilliancy
+	ScidBlunder
+	ScidUser
+	ScidCustom1
+	ScidCustom2
+	ScidCustom3
+	ScidCustom4
+	ScidCustom5
+	ScidCustom6
+)
+
+// scidFlagLetters are the letters of the flags from the lowest bit.
+const scidFlagLetters = "DWBMENPTKQ!?U123456"
+
+// scidFlagsTag is the tag pair Scid writes its flags in.
+const scidFlagsTag = "ScidFlags"
+
+// ParseScidFlags parses the value of a ScidFlags tag pair such as "WEN".
+// Letters may be in any order.
+func ParseScidFlags(s string) (ScidFlags, error) {
+	var flags ScidFlags
+	for _, c := range strings.TrimSpace(s) {
+		i := strings.IndexRune(scidFlagLetters, c)
+		if i == -1 {
+			return 0, fmt.Errorf("chess: invalid Scid flag %q in %q", c, s)
+		}
+		flags |= 1 << uint(i)
+	}
+	return flags, nil
+}
+
+// String returns the letters of the flags in Scid's order such as "WEN".
+func (f ScidFlags) String() string {
+	var sb strings.Builder
+	for i := 0; i < len(scidFlagLetters); i++ {
+		if f&(1<<uint(i)) != 0 {
+			sb.WriteByte(scidFlagLetters[i])
+		}
+	}
+	return sb.String()
+}
+
+// Has returns true if every flag of other is set.
+func (f ScidFlags) Has(other ScidFlags) bool {
+	return f&other == other
+}
+
+// ScidFlags returns the flags of the game's ScidFlags tag pair.  No flags
+// are returned if the tag pair is missing and an error is returned if it
+// isn't valid.
+func (g *Game) ScidFlags() (ScidFlags, error) {
+	tag := g.GetTagPair(scidFlagsTag)
+	if tag == nil {
+		return 0, nil
+	}
+	return ParseScidFlags(tag.Value)
+}
+
+// SetScidFlags sets the game's ScidFlags tag pair so that the flags are
+// written when the game is encoded.  No flags removes the tag pair.
+func (g *Game) SetScidFlags(f ScidFlags) {
+	if f == 0 {
+		g.RemoveTagPair(scidFlagsTag)
+		return
+	}
+	g.AddTagPair(scidFlagsTag, f.String())
+}
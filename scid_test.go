@@ -0,0 +1,49 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScidFlags(t *testing.T) {
+	pgn := `[Event "Club ch"]
+[Site "?"]
+[Date "2021.??.??"]
+[Round "3"]
+[White "Smith, J."]
+[Black "Jones, K."]
+[Result "1-0"]
+[EventDate "2021.??.??"]
+[ScidFlags "NE!"]
+
+1. e4 e5 2. Nf3 1-0`
+	opt, err := PGN(strings.NewReader(pgn), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	flags, err := g.ScidFlags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags != ScidEndgame|ScidNovelty|ScidBrilliancy || !flags.Has(ScidNovelty) || flags.Has(ScidBlunder) {
+		t.Fatalf("expected the endgame, novelty and brilliancy flags but got %s", flags)
+	}
+	if s := flags.String(); s != "EN!" {
+		t.Fatalf("expected the flags to be written in Scid's order but got %s", s)
+	}
+	if !strings.Contains(g.String(), "[EventDate \"2021.??.??\"]\n[ScidFlags \"NE!\"]") {
+		t.Fatalf("expected the Scid tag pairs to be kept but got %s", g)
+	}
+	g.SetScidFlags(flags | ScidCustom2 | ScidDeleted)
+	if tag := g.GetTagPair("ScidFlags"); tag == nil || tag.Value != "DEN!2" {
+		t.Fatalf("expected the flags to be set but got %v", tag)
+	}
+	g.SetScidFlags(0)
+	if flags, err := g.ScidFlags(); err != nil || flags != 0 || g.GetTagPair("ScidFlags") != nil {
+		t.Fatalf("expected the flags to be removed but got %s %v", flags, err)
+	}
+	if _, err := ParseScidFlags("EX"); err == nil {
+		t.Fatal("expected an error for an unknown flag")
+	}
+}
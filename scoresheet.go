@@ -0,0 +1,119 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+type scoresheet struct {
+	width      int
+	pageLength int
+}
+
+// ScoresheetWidth is an option for Scoresheet that sets the width of the
+// White and Black columns.  Longer moves and names are truncated.  The
+// default is 12.
+func ScoresheetWidth(n int) func(*scoresheet) {
+	return func(s *scoresheet) {
+		s.width = n
+	}
+}
+
+// ScoresheetPageLength is an option for Scoresheet that limits the number
+// of move rows per page.  Pages are separated by a form feed and each
+// page repeats the column headings.  The default of zero doesn't break
+// pages.
+func ScoresheetPageLength(n int) func(*scoresheet) {
+	return func(s *scoresheet) {
+		s.pageLength = n
+	}
+}
+
+// Scoresheet returns the game formatted as a traditional scoresheet with
+// a row for each move number and columns for White's and Black's moves
+// in the game's notation:
+//
+//	   Carlsen      Caruana
+//	1. e4           e5
+//	2. Nf3          Nc6
+//	   Result: 1-0
+//
+// The column headings are the White and Black tag pairs.  A game that
+// starts with Black to move shows "..." in White's column.
+func (g *Game) Scoresheet(opts ...func(*scoresheet)) string {
+	s := &scoresheet{width: 12}
+	for _, f := range opts {
+		if f != nil {
+			f(s)
+		}
+	}
+	if s.width < 1 {
+		s.width = 1
+	}
+	type row struct {
+		number       int
+		white, black string
+	}
+	rows := []*row{}
+	for i, m := range g.moves {
+		pos := g.positions[i]
+		text := g.notation.Encode(pos, m)
+		if pos.turn == White || len(rows) == 0 {
+			rows = append(rows, &row{number: pos.moveCount})
+		}
+		r := rows[len(rows)-1]
+		if pos.turn == White {
+			r.white = text
+		} else {
+			if r.white == "" {
+				r.white = "..."
+			}
+			r.black = text
+		}
+	}
+	numWidth := 1
+	if len(rows) > 0 {
+		numWidth = len(fmt.Sprint(rows[len(rows)-1].number))
+	}
+	margin := strings.Repeat(" ", numWidth+2)
+	header := margin + s.cell(g.playerName("White")) + " " + s.truncate(g.playerName("Black"))
+	lines := []string{}
+	for i, r := range rows {
+		if s.pageLength > 0 && i > 0 && i%s.pageLength == 0 {
+			lines = append(lines, "\f"+header)
+		} else if i == 0 {
+			lines = append(lines, header)
+		}
+		lines = append(lines, fmt.Sprintf("%*d. %s %s", numWidth, r.number, s.cell(r.white), s.truncate(r.black)))
+	}
+	if len(rows) == 0 {
+		lines = append(lines, header)
+	}
+	if g.outcome != NoOutcome {
+		lines = append(lines, margin+"Result: "+g.outcome.String())
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// cell returns the text truncated and padded to the column width.
+func (s *scoresheet) cell(text string) string {
+	text = s.truncate(text)
+	return text + strings.Repeat(" ", s.width-len([]rune(text)))
+}
+
+func (s *scoresheet) truncate(text string) string {
+	if runes := []rune(text); len(runes) > s.width {
+		return string(runes[:s.width])
+	}
+	return text
+}
+
+func (g *Game) playerName(color string) string {
+	if tp := g.GetTagPair(color); tp != nil && tp.Value != "" && tp.Value != "?" {
+		return tp.Value
+	}
+	return color
+}
@@ -0,0 +1,50 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScoresheet(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("[White \"Carlsen\"]\n[Black \"Nepomniachtchi\"]\n[Result \"1-0\"]\n\n1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 4. Ba4 Nf6 5. O-O Be7 6. Re1 b5 7. Bb3 d6 8. c3 O-O 9. h3 Nb8 10. d4 1-0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	expected := "    Carlsen    Nepomniach\n" +
+		" 1. e4         e5\n" +
+		" 2. Nf3        Nc6\n" +
+		" 3. Bb5        a6\n" +
+		" 4. Ba4        Nf6\n" +
+		"\f    Carlsen    Nepomniach\n" +
+		" 5. O-O        Be7\n" +
+		" 6. Re1        b5\n" +
+		" 7. Bb3        d6\n" +
+		" 8. c3         O-O\n" +
+		"\f    Carlsen    Nepomniach\n" +
+		" 9. h3         Nb8\n" +
+		"10. d4\n" +
+		"    Result: 1-0\n"
+	if s := g.Scoresheet(ScoresheetWidth(10), ScoresheetPageLength(4)); s != expected {
+		t.Fatalf("expected scoresheet\n%s\nbut got\n%s", expected, s)
+	}
+}
+
+func TestScoresheetBlackToMove(t *testing.T) {
+	fen, err := FEN("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(fen)
+	for _, m := range []string{"e5", "Nf3"} {
+		if err := g.MoveStr(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := "   White        Black\n" +
+		"1. ...          e5\n" +
+		"2. Nf3\n"
+	if s := g.Scoresheet(); s != expected {
+		t.Fatalf("expected scoresheet\n%s\nbut got\n%s", expected, s)
+	}
+}
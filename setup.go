@@ -0,0 +1,170 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetupError is returned by SetupPosition when the pieces don't form a
+// legal position.  Problems describes every issue found.
+type SetupError struct {
+	Problems []string
+}
+
+// Error implements the error interface.
+func (e *SetupError) Error() string {
+	return "chess: invalid setup: " + strings.Join(e.Problems, ", ")
+}
+
+type setup struct {
+	turn           Color
+	castleRights   CastleRights
+	deriveCastling bool
+	allowIllegal   bool
+}
+
+// SetupTurn is an option for SetupPosition that sets the color to move.
+// White moves by default.
+func SetupTurn(c Color) func(*setup) {
+	return func(s *setup) {
+		s.turn = c
+	}
+}
+
+// SetupCastleRights is an option for SetupPosition that sets the castling
+// rights.  By default neither color can castle.
+func SetupCastleRights(cr CastleRights) func(*setup) {
+	return func(s *setup) {
+		s.castleRights = cr
+	}
+}
+
+// SetupDeriveCastleRights is an option for SetupPosition that gives each
+// color the castling rights allowed by its king and rooks being on their
+// original squares.
+func SetupDeriveCastleRights(s *setup) {
+	s.deriveCastling = true
+}
+
+// SetupAllowIllegal is an option for SetupPosition that skips legality
+// checks.  This is useful for board editors and puzzles with positions
+// that can't arise in a game.
+func SetupAllowIllegal(s *setup) {
+	s.allowIllegal = true
+}
+
+// SetupPosition returns a position with the given piece placement.  Unless
+// SetupAllowIllegal is given, a *SetupError is returned if the position is
+// illegal: each color must have one king, pawns can't be on the first or
+// last rank, piece counts must be reachable by promotion, the color not
+// to move can't be in check and castling rights require the king and rook
+// on their original squares.
+func SetupPosition(m map[Square]Piece, opts ...func(*setup)) (*Position, error) {
+	s := &setup{turn: White, castleRights: "-"}
+	for _, f := range opts {
+		if f != nil {
+			f(s)
+		}
+	}
+	b := NewBoard(m)
+	if s.deriveCastling {
+		s.castleRights = castleRightsFromBoard(b)
+	}
+	if _, err := formCastleRights(string(s.castleRights)); err != nil {
+		return nil, err
+	}
+	rights, err := parseCastleRights(b, s.castleRights)
+	if err != nil {
+		return nil, err
+	}
+	pos := &Position{
+		board:           b,
+		turn:            s.turn,
+		castleRights:    formatCastleRights(b, rights),
+		enPassantSquare: NoSquare,
+		moveCount:       1,
+	}
+	if !s.allowIllegal {
+		if problems := setupProblems(pos); len(problems) > 0 {
+			return nil, &SetupError{Problems: problems}
+		}
+	}
+	pos.inCheck = isInCheck(pos)
+	return pos, nil
+}
+
+func setupProblems(pos *Position) []string {
+	problems := []string{}
+	counts := map[Piece]int{}
+	for sq, p := range pos.board.SquareMap() {
+		counts[p]++
+		if p.Type() == Pawn && (sq.Rank() == Rank1 || sq.Rank() == Rank8) {
+			problems = append(problems, fmt.Sprintf("pawn on %s", sq))
+		}
+	}
+	for _, c := range []Color{White, Black} {
+		if n := counts[getPiece(King, c)]; n != 1 {
+			problems = append(problems, fmt.Sprintf("%s has %d kings", c.Name(), n))
+		}
+		// each piece beyond the original set must be a promoted pawn
+		promoted := 0
+		for pt, n := range map[PieceType]int{Queen: 1, Rook: 2, Bishop: 2, Knight: 2} {
+			if extra := counts[getPiece(pt, c)] - n; extra > 0 {
+				promoted += extra
+			}
+		}
+		if pawns := counts[getPiece(Pawn, c)]; pawns+promoted > 8 {
+			problems = append(problems, fmt.Sprintf("%s has too many pawns and promoted pieces", c.Name()))
+		}
+	}
+	opponent := &Position{board: pos.board, turn: pos.turn.Other()}
+	if counts[getPiece(King, pos.turn.Other())] == 1 && isInCheck(opponent) {
+		problems = append(problems, fmt.Sprintf("%s is in check but not to move", pos.turn.Other().Name()))
+	}
+	derived := castleRightsFromBoard(pos.board)
+	for _, c := range []Color{White, Black} {
+		for _, side := range []Side{KingSide, QueenSide} {
+			if pos.castleRights.CanCastle(c, side) && !derived.CanCastle(c, side) {
+				name := "kingside"
+				if side == QueenSide {
+					name = "queenside"
+				}
+				problems = append(problems, fmt.Sprintf("%s can't castle %s without king and rook on their original squares", c.Name(), name))
+			}
+		}
+	}
+	return problems
+}
+
+// castleRightsFromBoard returns the castling rights allowed by the kings
+// and rooks on their original squares.
+func castleRightsFromBoard(b *Board) CastleRights {
+	s := ""
+	for _, c := range []Color{White, Black} {
+		king, kingSide, queenSide := E1, H1, A1
+		if c == Black {
+			king, kingSide, queenSide = E8, H8, A8
+		}
+		if b.Piece(king) != getPiece(King, c) {
+			continue
+		}
+		rook := getPiece(Rook, c)
+		if b.Piece(kingSide) == rook {
+			s += fenCastleChar(c, "k")
+		}
+		if b.Piece(queenSide) == rook {
+			s += fenCastleChar(c, "q")
+		}
+	}
+	if s == "" {
+		return "-"
+	}
+	return CastleRights(s)
+}
+
+func fenCastleChar(c Color, s string) string {
+	if c == White {
+		return strings.ToUpper(s)
+	}
+	return s
+}
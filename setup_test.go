@@ -0,0 +1,44 @@
+package chess
+
+import "testing"
+
+func TestSetupPosition(t *testing.T) {
+	m := map[Square]Piece{E1: WhiteKing, H1: WhiteRook, A1: WhiteRook, E8: BlackKing, A8: BlackRook}
+	pos, err := SetupPosition(m, SetupDeriveCastleRights, SetupTurn(Black))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := pos.String(); s != "r3k3/8/8/8/8/8/8/R3K2R b KQq - 0 1" {
+		t.Fatalf("unexpected position %s", s)
+	}
+	if _, err := SetupPosition(m, SetupCastleRights("KQkq")); err == nil {
+		t.Fatal("expected castling rights without a rook to return an error")
+	}
+}
+
+func TestSetupPositionProblems(t *testing.T) {
+	tests := []struct {
+		m        map[Square]Piece
+		opts     []func(*setup)
+		problems int
+	}{
+		{map[Square]Piece{E1: WhiteKing}, nil, 1},
+		{map[Square]Piece{E1: WhiteKing, E8: BlackKing, A1: WhitePawn, H8: BlackPawn}, nil, 2},
+		{map[Square]Piece{E1: WhiteKing, E8: BlackKing, E6: WhiteQueen}, nil, 1},
+		{map[Square]Piece{E1: WhiteKing, E8: BlackKing, E6: WhiteQueen}, []func(*setup){SetupTurn(Black)}, 0},
+		{map[Square]Piece{E1: WhiteKing, E8: BlackKing, A3: WhiteQueen, B3: WhiteQueen, C3: WhitePawn, D3: WhitePawn, E3: WhitePawn, F3: WhitePawn, G3: WhitePawn, H3: WhitePawn, H4: WhitePawn, B4: WhitePawn}, nil, 1},
+	}
+	for i, test := range tests {
+		_, err := SetupPosition(test.m, test.opts...)
+		n := 0
+		if err != nil {
+			n = len(err.(*SetupError).Problems)
+		}
+		if n != test.problems {
+			t.Fatalf("test %d expected %d problems but got %v", i, test.problems, err)
+		}
+		if _, err := SetupPosition(test.m, append(test.opts, SetupAllowIllegal)...); err != nil {
+			t.Fatalf("test %d expected illegal setup to be allowed but got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package chess
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MaterialSignature returns the key of the position's material used to
+// organize endgame databases: White's pieces followed by Black's in the
+// order KQRBNP such as KRPPKRP.  Endgame returns the signature with the
+// stronger side first instead.
+func (pos *Position) MaterialSignature() string {
+	return materialSignature(pos.board, White) + materialSignature(pos.board, Black)
+}
+
+// MatchSignature reports whether the material signature matches the
+// pattern.  The pattern is a signature for each side (such as KRPKR)
+// where each side may use the wildcards of path.Match: '*' matches any
+// pieces, '?' matches one piece and '[BN]' matches one of the listed
+// pieces.  Wildcards don't cross from one side to the other so KR*K*
+// matches every position where the first side has a rook.  The only
+// possible error is a malformed pattern.
+func MatchSignature(signature, pattern string) (bool, error) {
+	pSides, err := signatureSides(pattern)
+	if err != nil {
+		return false, err
+	}
+	sSides, err := signatureSides(signature)
+	if err != nil {
+		return false, err
+	}
+	for i := range pSides {
+		matched, err := path.Match(pSides[i], sSides[i])
+		if err != nil {
+			return false, fmt.Errorf("chess: malformed signature pattern %s", pattern)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// MatchesMaterial reports whether the position's material matches the
+// signature pattern with either color as the first side.  See
+// MatchSignature for the pattern syntax.
+func (pos *Position) MatchesMaterial(pattern string) (bool, error) {
+	white := materialSignature(pos.board, White)
+	black := materialSignature(pos.board, Black)
+	matched, err := MatchSignature(white+black, pattern)
+	if err != nil || matched {
+		return matched, err
+	}
+	return MatchSignature(black+white, pattern)
+}
+
+// signatureSides splits a signature into each side's pieces starting
+// with their king.
+func signatureSides(s string) ([]string, error) {
+	i := strings.LastIndex(s, "K")
+	if !strings.HasPrefix(s, "K") || i <= 0 || strings.Count(s, "K") != 2 {
+		return nil, fmt.Errorf("chess: signature %s must contain a king for each side", s)
+	}
+	return []string{s[:i], s[i:]}, nil
+}
@@ -0,0 +1,40 @@
+package chess
+
+import "testing"
+
+func TestMaterialSignature(t *testing.T) {
+	pos := unsafeFEN("8/5p2/3rk3/8/8/4P3/4KP2/4R3 w - - 0 1")
+	if s := pos.MaterialSignature(); s != "KRPPKRP" {
+		t.Fatalf("expected signature KRPPKRP but got %s", s)
+	}
+	tests := []struct {
+		pattern string
+		matched bool
+	}{
+		{"KRPPKRP", true},
+		{"KRPKRPP", true},
+		{"KR*KR*", true},
+		{"KR??KR?", true},
+		{"K*KRP", true},
+		{"KRPKR", false},
+		{"KQ*K*", false},
+		{"K[QR]PPK[QR]P", true},
+	}
+	for _, test := range tests {
+		matched, err := pos.MatchesMaterial(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched != test.matched {
+			t.Fatalf("expected %s matched %v but got %v", test.pattern, test.matched, matched)
+		}
+	}
+	if matched, _ := MatchSignature("KRPPKRP", "KRPKRPP"); matched {
+		t.Fatal("expected MatchSignature to respect the order of the sides")
+	}
+	for _, pattern := range []string{"KRP", "RKR", "KR[KR"} {
+		if _, err := MatchSignature("KRPPKRP", pattern); err == nil {
+			t.Fatalf("expected an error for pattern %s", pattern)
+		}
+	}
+}
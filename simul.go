@@ -0,0 +1,183 @@
+package chess
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Simul is a session of many games played at once by one player, the
+// host, such as a simultaneous exhibition or a bot account playing many
+// opponents.  It numbers the boards, keeps the boards waiting for each
+// side in the order they started waiting and totals the host's results.
+// Simul is safe for concurrent use.
+type Simul struct {
+	mu     sync.Mutex
+	boards []*SimulBoard
+	// waits counts the moves so that boards can be ordered by how long
+	// they have waited
+	waits int
+	// last is the number of the board the host last moved on
+	last int
+}
+
+// SimulBoard is a board of a simul.
+type SimulBoard struct {
+	// Number is the board's number starting at 1.
+	Number int
+	// Opponent is the name of the host's opponent.
+	Opponent string
+	// Color is the host's color.
+	Color Color
+	// Game is the board's game.  Moves should be made with Simul's Move
+	// so that the queues are kept up to date.
+	Game *Game
+	// waiting is when the side to move started waiting
+	waiting int
+}
+
+// HostToMove returns true if the game is in progress and it's the host's
+// turn.
+func (b *SimulBoard) HostToMove() bool {
+	return b.Game.Outcome() == NoOutcome && b.Game.Position().Turn() == b.Color
+}
+
+// NewSimul returns a simul without boards.
+func NewSimul() *Simul {
+	return &Simul{}
+}
+
+// AddBoard adds a board for the game against the opponent where the host
+// plays the color and returns it.  Boards are numbered in the order they
+// are added.
+func (s *Simul) AddBoard(opponent string, color Color, g *Game) *SimulBoard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.waits++
+	b := &SimulBoard{Number: len(s.boards) + 1, Opponent: opponent, Color: color, Game: g, waiting: s.waits}
+	s.boards = append(s.boards, b)
+	return b
+}
+
+// Board returns the board with the number or nil if there isn't one.
+func (s *Simul) Board(n int) *SimulBoard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 1 || n > len(s.boards) {
+		return nil
+	}
+	return s.boards[n-1]
+}
+
+// Boards returns the boards in order of their numbers.
+func (s *Simul) Boards() []*SimulBoard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*SimulBoard(nil), s.boards...)
+}
+
+// Move makes the move on the board with the number for whichever side is
+// to move.  An error is returned if there is no such board or the move is
+// invalid.
+func (s *Simul) Move(n int, m *Move) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 1 || n > len(s.boards) {
+		return fmt.Errorf("chess: simul has no board %d", n)
+	}
+	b := s.boards[n-1]
+	host := b.HostToMove()
+	if err := b.Game.Move(m); err != nil {
+		return err
+	}
+	if host {
+		s.last = n
+	}
+	s.waits++
+	b.waiting = s.waits
+	return nil
+}
+
+// HostQueue returns the boards where it's the host's turn, longest waiting
+// first, as a bot answering its opponents in turn would play them.
+func (s *Simul) HostQueue() []*SimulBoard {
+	return s.queue(true)
+}
+
+// OpponentQueue returns the boards where it's the opponent's turn,
+// longest waiting first.
+func (s *Simul) OpponentQueue() []*SimulBoard {
+	return s.queue(false)
+}
+
+func (s *Simul) queue(host bool) []*SimulBoard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queue := []*SimulBoard{}
+	for _, b := range s.boards {
+		if b.Game.Outcome() == NoOutcome && b.HostToMove() == host {
+			queue = append(queue, b)
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].waiting < queue[j].waiting
+	})
+	return queue
+}
+
+// Next returns the next board after the one the host last moved on where
+// it's the host's turn, as the host of an exhibition walks around the
+// boards in order, or nil if the host isn't to move on any board.
+func (s *Simul) Next() *SimulBoard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 1; i <= len(s.boards); i++ {
+		b := s.boards[(s.last+i-1)%len(s.boards)]
+		if b.HostToMove() {
+			return b
+		}
+	}
+	return nil
+}
+
+// SimulResults are the host's results in a simul.
+type SimulResults struct {
+	Wins    int
+	Draws   int
+	Losses  int
+	Ongoing int
+}
+
+// Points returns the host's points with a point for a win and half a point
+// for a draw.
+func (r SimulResults) Points() float64 {
+	return float64(r.Wins) + float64(r.Draws)/2
+}
+
+// Results returns the host's results on every board.
+func (s *Simul) Results() SimulResults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := SimulResults{}
+	for _, b := range s.boards {
+		switch b.Game.Outcome() {
+		case NoOutcome:
+			r.Ongoing++
+		case Draw:
+			r.Draws++
+		case WhiteWon:
+			if b.Color == White {
+				r.Wins++
+			} else {
+				r.Losses++
+			}
+		case BlackWon:
+			if b.Color == Black {
+				r.Wins++
+			} else {
+				r.Losses++
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,54 @@
+package chess
+
+import "testing"
+
+func TestSimul(t *testing.T) {
+	s := NewSimul()
+	for _, opponent := range []string{"A", "B", "C"} {
+		s.AddBoard(opponent, White, NewGame())
+	}
+	s.AddBoard("D", Black, NewGame())
+	if b := s.Next(); b == nil || b.Number != 1 {
+		t.Fatalf("expected the host to start on board 1 but got %v", b)
+	}
+	move := func(n int, uci string) {
+		m, err := UCINotation{}.Decode(s.Board(n).Game.Position(), uci)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Move(n, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	move(2, "e2e4")
+	if b := s.Next(); b == nil || b.Number != 3 {
+		t.Fatalf("expected the host to continue to board 3 but got %v", b)
+	}
+	move(3, "d2d4")
+	move(4, "e2e4")
+	move(2, "e7e5")
+	if q := s.HostQueue(); len(q) != 3 || q[0].Number != 1 || q[1].Number != 4 || q[2].Number != 2 {
+		t.Fatalf("expected boards 1, 4 and 2 waiting for the host but got %v", q)
+	}
+	if q := s.OpponentQueue(); len(q) != 1 || q[0].Number != 3 {
+		t.Fatalf("expected board 3 waiting for the opponent but got %v", q)
+	}
+	if b := s.Next(); b == nil || b.Number != 4 {
+		t.Fatalf("expected the host to continue to board 4 but got %v", b)
+	}
+	s.Board(1).Game.Resign(White)
+	s.Board(4).Game.Resign(White)
+	if err := s.Board(3).Game.Draw(DrawOffer); err != nil {
+		t.Fatal(err)
+	}
+	r := s.Results()
+	if r.Wins != 1 || r.Losses != 1 || r.Draws != 1 || r.Ongoing != 1 || r.Points() != 1.5 {
+		t.Fatalf("expected a win, a loss, a draw and an ongoing game but got %+v", r)
+	}
+	if err := s.Move(5, nil); err == nil {
+		t.Fatal("expected an error for a missing board")
+	}
+	if s.Board(5) != nil {
+		t.Fatal("expected no board 5")
+	}
+}
@@ -0,0 +1,405 @@
+package chess
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// snapshotMagic starts every snapshot.
+const snapshotMagic = "CHGS"
+
+// snapshotVersion is the version of the snapshot format.  It is
+// incremented whenever the format changes.
+const snapshotVersion = 2
+
+// Snapshot returns the complete state of the game in a compact binary
+// format that can be restored with Restore: the starting position, the
+// moves with their comments, commands (such as [%clk] clock times), NAGs
+// and variations, the tag pairs, the outcome and method, and the metadata.
+// Unlike PGN, restoring a snapshot doesn't parse movetext and keeps state
+// that PGN doesn't express, such as the metadata, escape lines and the
+// layout of a game decoded with ExactPGN.  The game's notation and
+// context aren't part of the snapshot.
+func (g *Game) Snapshot() []byte {
+	w := &snapshotWriter{}
+	w.buf.WriteString(snapshotMagic)
+	w.buf.WriteByte(snapshotVersion)
+	w.writeString(g.positions[0].String())
+	w.writeUvarint(uint64(len(g.moves)))
+	for _, m := range g.moves {
+		w.writeMove(m)
+	}
+	w.writeUvarint(uint64(len(g.tagPairs)))
+	for _, tp := range g.tagPairs {
+		w.writeString(tp.Key)
+		w.writeString(tp.Value)
+	}
+	w.writeString(string(g.outcome))
+	w.buf.WriteByte(byte(g.method))
+	w.writeBool(g.ignoreAutomaticDraws)
+	w.writeComments(g.comments)
+	w.writeStrings(g.escapes)
+	keys := []string{}
+	for k := range g.metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	w.writeUvarint(uint64(len(keys)))
+	for _, k := range keys {
+		w.writeString(k)
+		w.writeString(g.metadata[k])
+	}
+	w.writeLayout(g.layout)
+	return w.buf.Bytes()
+}
+
+// Restore replaces the state of the game with a snapshot returned by
+// Snapshot.  The game keeps its notation and context.  An error is
+// returned and the game is left unchanged if the snapshot is malformed,
+// was written by an unsupported version or contains an invalid move.
+func (g *Game) Restore(snapshot []byte) error {
+	if !bytes.HasPrefix(snapshot, []byte(snapshotMagic)) {
+		return errors.New("chess: data isn't a game snapshot")
+	}
+	r := &snapshotReader{r: bytes.NewReader(snapshot[len(snapshotMagic):])}
+	if v := r.readByte(); r.err == nil && v != snapshotVersion {
+		return fmt.Errorf("chess: unsupported game snapshot version %d", v)
+	}
+	start, err := decodeFEN(r.readString())
+	if r.err == nil && err != nil {
+		return fmt.Errorf("chess: invalid game snapshot: %w", err)
+	}
+	restored := &Game{pos: start, positions: []*Position{start}, moves: []*Move{}}
+	n := r.readCount()
+	for i := 0; i < n && r.err == nil; i++ {
+		m := r.readMove()
+		if r.err != nil {
+			break
+		}
+		valid := snapshotValidMove(restored.pos, m)
+		if valid == nil {
+			return fmt.Errorf("chess: invalid move %s in game snapshot", m)
+		}
+		if err := checkSnapshotVariations(restored.pos, m); err != nil {
+			return err
+		}
+		if isIrreversible(restored.pos, valid) {
+			restored.lastIrreversible = len(restored.moves) + 1
+		}
+		m.tags = valid.tags
+		restored.moves = append(restored.moves, m)
+		restored.pos = restored.pos.Update(valid)
+		restored.positions = append(restored.positions, restored.pos)
+	}
+	n = r.readCount()
+	for i := 0; i < n && r.err == nil; i++ {
+		restored.tagPairs = append(restored.tagPairs, &TagPair{Key: r.readString(), Value: r.readString()})
+	}
+	restored.outcome = Outcome(r.readString())
+	restored.method = Method(r.readByte())
+	restored.ignoreAutomaticDraws = r.readBool()
+	restored.comments = r.readComments()
+	restored.escapes = r.readStrings()
+	n = r.readCount()
+	for i := 0; i < n && r.err == nil; i++ {
+		if restored.metadata == nil {
+			restored.metadata = map[string]string{}
+		}
+		k := r.readString()
+		restored.metadata[k] = r.readString()
+	}
+	restored.layout = r.readLayout()
+	if r.err != nil {
+		return fmt.Errorf("chess: invalid game snapshot: %w", r.err)
+	}
+	restored.notation = g.notation
+	restored.ctx = g.ctx
+	*g = *restored
+	return nil
+}
+
+// snapshotValidMove returns the legal move in the position that has the
+// squares and promotion of m or nil if there isn't one.
+func snapshotValidMove(pos *Position, m *Move) *Move {
+	if m.IsNull() {
+		if pos.inCheck {
+			return nil
+		}
+		return m
+	}
+	valid := moveSlice(pos.ValidMoves()).find(m)
+	if valid == nil || valid.promo != m.promo {
+		return nil
+	}
+	return valid
+}
+
+// checkSnapshotVariations checks that the variations of the move, which
+// is played from pos, are legal as AddVariation does and sets the tags of
+// their moves.
+func checkSnapshotVariations(pos *Position, m *Move) error {
+	for _, v := range m.variations {
+		if len(v) == 0 {
+			return errors.New("chess: empty variation in game snapshot")
+		}
+		vpos := pos
+		for _, vm := range v {
+			valid := snapshotValidMove(vpos, vm)
+			if valid == nil {
+				return fmt.Errorf("chess: invalid move %s in game snapshot variation", vm)
+			}
+			if err := checkSnapshotVariations(vpos, vm); err != nil {
+				return err
+			}
+			vm.tags = valid.tags
+			vpos = vpos.Update(valid)
+		}
+	}
+	return nil
+}
+
+// snapshotWriter writes the fields of a snapshot.
+type snapshotWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *snapshotWriter) writeUvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (w *snapshotWriter) writeString(s string) {
+	w.writeUvarint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *snapshotWriter) writeBool(b bool) {
+	if b {
+		w.buf.WriteByte(1)
+	} else {
+		w.buf.WriteByte(0)
+	}
+}
+
+func (w *snapshotWriter) writeStrings(a []string) {
+	w.writeUvarint(uint64(len(a)))
+	for _, s := range a {
+		w.writeString(s)
+	}
+}
+
+func (w *snapshotWriter) writeComments(comments []Comment) {
+	w.writeUvarint(uint64(len(comments)))
+	for _, c := range comments {
+		w.writeString(c.Annotator)
+		w.writeString(c.Text)
+	}
+}
+
+// writeMove writes the move's squares, promotion and annotations.  Squares
+// are offset by one so that the null move's NoSquare fits in a byte.
+func (w *snapshotWriter) writeMove(m *Move) {
+	w.buf.WriteByte(byte(m.s1 + 1))
+	w.buf.WriteByte(byte(m.s2 + 1))
+	w.buf.WriteByte(byte(m.promo))
+	w.writeUvarint(uint64(m.tags))
+	w.writeComments(m.preComments)
+	w.writeComments(m.comments)
+	w.writeStrings(m.nags)
+	w.writeString(m.annotator)
+	w.writeUvarint(uint64(len(m.variations)))
+	for _, v := range m.variations {
+		w.writeUvarint(uint64(len(v)))
+		for _, vm := range v {
+			w.writeMove(vm)
+		}
+	}
+	w.writeBool(m.layout != nil)
+	if m.layout != nil {
+		w.writePiece(m.layout.pgnPiece)
+		w.writeAnnotations(m.layout.annotations)
+		w.writePiece(m.layout.open)
+		w.writePiece(m.layout.close)
+		w.writeAnnotations(m.layout.pre)
+	}
+}
+
+// writeLayout writes whether the game was decoded with ExactPGN followed
+// by its layout.
+func (w *snapshotWriter) writeLayout(l *pgnLayout) {
+	w.writeBool(l != nil)
+	if l == nil {
+		return
+	}
+	w.writeUvarint(uint64(len(l.tags)))
+	for _, t := range l.tags {
+		w.writePiece(t.pgnPiece)
+		w.writeString(t.tag.Key)
+		w.writeString(t.tag.Value)
+	}
+	w.writeAnnotations(l.comments)
+	w.writePiece(l.result)
+	w.writeString(l.trailing)
+}
+
+func (w *snapshotWriter) writePiece(p pgnPiece) {
+	w.writeString(p.before)
+	w.writeString(p.text)
+}
+
+func (w *snapshotWriter) writeAnnotations(a []pgnAnnotation) {
+	w.writeUvarint(uint64(len(a)))
+	for _, rec := range a {
+		w.writePiece(rec.pgnPiece)
+		w.buf.WriteByte(byte(rec.typ))
+		w.writeString(rec.nag)
+		w.writeComments([]Comment{rec.comment})
+	}
+}
+
+// snapshotReader reads the fields of a snapshot.  After an error reads
+// return zero values and the first error is kept in err.
+type snapshotReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (r *snapshotReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *snapshotReader) readByte() byte {
+	if r.err != nil {
+		return 0
+	}
+	b, err := r.r.ReadByte()
+	r.fail(err)
+	return b
+}
+
+func (r *snapshotReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(r.r)
+	r.fail(err)
+	return v
+}
+
+// readCount reads the length of a list, which can't be longer than the
+// remaining data.
+func (r *snapshotReader) readCount() int {
+	n := r.readUvarint()
+	if n > uint64(r.r.Len()) {
+		r.fail(fmt.Errorf("length %d exceeds the remaining data", n))
+		return 0
+	}
+	return int(n)
+}
+
+func (r *snapshotReader) readString() string {
+	n := r.readCount()
+	if r.err != nil {
+		return ""
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r.r, b)
+	r.fail(err)
+	return string(b)
+}
+
+func (r *snapshotReader) readBool() bool {
+	return r.readByte() == 1
+}
+
+func (r *snapshotReader) readStrings() []string {
+	n := r.readCount()
+	var a []string
+	for i := 0; i < n && r.err == nil; i++ {
+		a = append(a, r.readString())
+	}
+	return a
+}
+
+func (r *snapshotReader) readComments() []Comment {
+	n := r.readCount()
+	var comments []Comment
+	for i := 0; i < n && r.err == nil; i++ {
+		comments = append(comments, Comment{Annotator: r.readString(), Text: r.readString()})
+	}
+	return comments
+}
+
+func (r *snapshotReader) readMove() *Move {
+	m := &Move{
+		s1:    Square(r.readByte()) - 1,
+		s2:    Square(r.readByte()) - 1,
+		promo: PieceType(r.readByte()),
+		tags:  MoveTag(r.readUvarint()),
+	}
+	if m.s1 < NoSquare || m.s1 > H8 || m.s2 < NoSquare || m.s2 > H8 {
+		r.fail(errors.New("invalid move square"))
+	}
+	m.preComments = r.readComments()
+	m.comments = r.readComments()
+	m.nags = r.readStrings()
+	m.annotator = r.readString()
+	n := r.readCount()
+	for i := 0; i < n && r.err == nil; i++ {
+		v := make([]*Move, r.readCount())
+		for j := range v {
+			v[j] = r.readMove()
+		}
+		m.variations = append(m.variations, v)
+	}
+	if r.readBool() {
+		m.layout = &moveLayout{
+			pgnPiece:    r.readPiece(),
+			annotations: r.readAnnotations(),
+			open:        r.readPiece(),
+			close:       r.readPiece(),
+			pre:         r.readAnnotations(),
+		}
+	}
+	return m
+}
+
+func (r *snapshotReader) readLayout() *pgnLayout {
+	if !r.readBool() {
+		return nil
+	}
+	l := &pgnLayout{}
+	n := r.readCount()
+	for i := 0; i < n && r.err == nil; i++ {
+		piece := r.readPiece()
+		tag := TagPair{Key: r.readString(), Value: r.readString()}
+		l.tags = append(l.tags, pgnTagLayout{pgnPiece: piece, tag: tag})
+	}
+	l.comments = r.readAnnotations()
+	l.result = r.readPiece()
+	l.trailing = r.readString()
+	return l
+}
+
+func (r *snapshotReader) readPiece() pgnPiece {
+	return pgnPiece{before: r.readString(), text: r.readString()}
+}
+
+func (r *snapshotReader) readAnnotations() []pgnAnnotation {
+	n := r.readCount()
+	var a []pgnAnnotation
+	for i := 0; i < n && r.err == nil; i++ {
+		rec := pgnAnnotation{pgnPiece: r.readPiece(), typ: pgnTokenType(r.readByte()), nag: r.readString()}
+		if c := r.readComments(); len(c) == 1 {
+			rec.comment = c[0]
+		}
+		a = append(a, rec)
+	}
+	return a
+}
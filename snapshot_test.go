@@ -0,0 +1,121 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	const pgn = `[Event "Casual"]
+[White "A"]
+[Black "B"]
+[Result "*"]
+
+{Before the game.} 1. e4 {[%clk 0:03:00]} e5 $1 (1... c5 {Sicilian}) 2. Nf3 {[%clk 0:02:58]} *`
+	opt, err := PGN(strings.NewReader(pgn), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt, Metadata(map[string]string{"id": "42"}))
+	if err := g.MoveStr("Nc6"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetClock(4, 170*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Move(NullMove()); err != nil {
+		t.Fatal(err)
+	}
+	g.Draw(DrawOffer)
+
+	restored := NewGame()
+	if err := restored.Restore(g.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	if restored.String() != g.String() {
+		t.Fatalf("expected restored game\n%s\nbut got\n%s", g, restored)
+	}
+	if restored.Method() != DrawOffer || restored.Outcome() != Draw {
+		t.Fatalf("expected a draw by offer but got %s %s", restored.Outcome(), restored.Method())
+	}
+	if id, _ := restored.GetMetadata("id"); id != "42" {
+		t.Fatalf("expected metadata id 42 but got %q", id)
+	}
+	if d, ok := restored.Moves()[3].Clock(); !ok || d != 170*time.Second {
+		t.Fatalf("expected clock 2:50 but got %s", d)
+	}
+	if restored.Position().String() != g.Position().String() {
+		t.Fatalf("expected position %s but got %s", g.Position(), restored.Position())
+	}
+}
+
+func TestSnapshotFromPosition(t *testing.T) {
+	fen, err := FEN("8/P7/8/8/8/8/8/k6K w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(fen)
+	if err := g.MoveStr("a8=Q"); err != nil {
+		t.Fatal(err)
+	}
+	restored := NewGame()
+	if err := restored.Restore(g.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Position().String() != g.Position().String() {
+		t.Fatalf("expected position %s but got %s", g.Position(), restored.Position())
+	}
+	if err := restored.MoveStr("Kb2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	g := NewGame()
+	if err := g.MoveStr("d4"); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := g.Snapshot()
+	for _, data := range [][]byte{
+		nil,
+		[]byte("PGN"),
+		append([]byte(snapshotMagic), 99),
+		snapshot[:len(snapshot)-3],
+	} {
+		restored := NewGame()
+		if err := restored.MoveStr("e4"); err != nil {
+			t.Fatal(err)
+		}
+		if err := restored.Restore(data); err == nil {
+			t.Fatalf("expected an error restoring %q", data)
+		}
+		if len(restored.Moves()) != 1 {
+			t.Fatal("expected the game to be unchanged")
+		}
+	}
+}
+
+func TestRestoreInvalidVariation(t *testing.T) {
+	tests := []func(g *Game){
+		func(g *Game) { g.moves[0].variations[0][1].s2 = E4 },
+		func(g *Game) { g.moves[0].variations[0][0].promo = Queen },
+		func(g *Game) { g.moves[0].variations[0] = nil },
+		func(g *Game) { g.moves[1].promo = Knight },
+	}
+	for i, tamper := range tests {
+		g := NewGame()
+		for _, s := range []string{"d4", "d5"} {
+			if err := g.MoveStr(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := g.AddVariation(1, "", &Move{s1: E2, s2: E4}, &Move{s1: E7, s2: E5}); err != nil {
+			t.Fatal(err)
+		}
+		tamper(g)
+		if err := NewGame().Restore(g.Snapshot()); err == nil {
+			t.Fatalf("expected an error restoring tampered snapshot %d", i)
+		}
+	}
+}
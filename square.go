@@ -22,7 +22,9 @@ func (sq Square) String() string {
 	return sq.File().String() + sq.Rank().String()
 }
 
-func (sq Square) color() Color {
+// Color returns the color of the square: White for light squares and
+// Black for dark squares such as A1.
+func (sq Square) Color() Color {
 	if ((sq / 8) % 2) == (sq % 2) {
 		return Black
 	}
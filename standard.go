@@ -0,0 +1,116 @@
+package chess
+
+import "fmt"
+
+// NamedPosition is a well known position for tests and benchmarks.
+type NamedPosition struct {
+	Name        string
+	FEN         string
+	Description string
+	// Perft holds the number of leaf nodes for depths 1 through
+	// len(Perft).  It is empty for positions without published counts.
+	Perft []int
+}
+
+// Position returns the decoded position or an error if the FEN is
+// invalid.
+func (n NamedPosition) Position() (*Position, error) {
+	return decodeFEN(n.FEN)
+}
+
+// perft counts are from https://www.chessprogramming.org/Perft_Results
+var standardPositions = []NamedPosition{
+	{
+		Name:        "startpos",
+		FEN:         startFEN,
+		Description: "The starting position.",
+		Perft:       []int{20, 400, 8902, 197281, 4865609},
+	},
+	{
+		Name:        "kiwipete",
+		FEN:         "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		Description: "Perft position 2 by Peter McKenzie which exercises castling, en passant and promotions.",
+		Perft:       []int{48, 2039, 97862, 4085603},
+	},
+	{
+		Name:        "perft3",
+		FEN:         "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		Description: "Perft position 3, an endgame with discovered checks and en passant pins.",
+		Perft:       []int{14, 191, 2812, 43238, 674624, 11030083},
+	},
+	{
+		Name:        "perft4",
+		FEN:         "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+		Description: "Perft position 4 with promotions and castling through check.",
+		Perft:       []int{6, 264, 9467, 422333, 15833292},
+	},
+	{
+		Name:        "perft4mirrored",
+		FEN:         "r2q1rk1/pP1p2pp/Q4n2/bbp1p3/Np6/1B3NBn/pPPP1PPP/R3K2R b KQ - 0 1",
+		Description: "Perft position 4 with the colors reversed.",
+		Perft:       []int{6, 264, 9467, 422333, 15833292},
+	},
+	{
+		Name:        "perft5",
+		FEN:         "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+		Description: "Perft position 5 which found bugs in several engines.",
+		Perft:       []int{44, 1486, 62379, 2103487, 89941194},
+	},
+	{
+		Name:        "perft6",
+		FEN:         "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+		Description: "Perft position 6 by Steven Edwards, a symmetrical middlegame.",
+		Perft:       []int{46, 2079, 89890, 3894594, 164075551},
+	},
+	{
+		Name:        "lucena",
+		FEN:         "1K1k4/1P6/8/8/8/8/r7/2R5 w - - 0 1",
+		Description: "The Lucena position, a rook endgame won by building a bridge.",
+	},
+	{
+		Name:        "saavedra",
+		FEN:         "8/8/1KP5/3r4/8/8/8/k7 w - - 0 1",
+		Description: "The Saavedra position, won by underpromoting to a rook.",
+	},
+	{
+		Name:        "reti",
+		FEN:         "7K/8/k1P5/7p/8/8/8/8 w - - 0 1",
+		Description: "Réti's study in which the white king draws by chasing two goals at once.",
+	},
+}
+
+// StandardPositions returns well known positions for tests and
+// benchmarks: the starting position, the perft suite positions and famous
+// endgames.
+func StandardPositions() []NamedPosition {
+	return append([]NamedPosition(nil), standardPositions...)
+}
+
+// StandardPosition returns the standard position with the given name.
+// An error is returned if there isn't one.
+func StandardPosition(name string) (NamedPosition, error) {
+	for _, n := range standardPositions {
+		if n.Name == name {
+			return n, nil
+		}
+	}
+	return NamedPosition{}, fmt.Errorf("chess: unknown standard position %s", name)
+}
+
+// Perft returns the number of leaf nodes of the move generation tree
+// of the given depth.  It is used to verify move generation against
+// known counts such as those of StandardPositions.
+func (pos *Position) Perft(depth int) int {
+	if depth <= 0 {
+		return 1
+	}
+	moves := pos.ValidMoves()
+	if depth == 1 {
+		return len(moves)
+	}
+	nodes := 0
+	for _, m := range moves {
+		nodes += pos.Update(m).Perft(depth - 1)
+	}
+	return nodes
+}
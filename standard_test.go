@@ -0,0 +1,30 @@
+package chess
+
+import "testing"
+
+func TestStandardPositions(t *testing.T) {
+	for _, n := range StandardPositions() {
+		pos, err := n.Position()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for depth := 1; depth <= len(n.Perft) && depth <= 2; depth++ {
+			if nodes := pos.Perft(depth); nodes != n.Perft[depth-1] {
+				t.Fatalf("%s expected %d nodes at depth %d but got %d", n.Name, n.Perft[depth-1], depth, nodes)
+			}
+		}
+	}
+	n, err := StandardPosition("kiwipete")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos, err := n.Position(); err != nil || pos.String() != n.FEN {
+		t.Fatalf("unexpected kiwipete position %s: %v", pos, err)
+	}
+	if _, err := (NamedPosition{Name: "bad", FEN: "8/8 w"}).Position(); err == nil {
+		t.Fatal("expected an invalid FEN to return an error")
+	}
+	if _, err := StandardPosition("unknown"); err == nil {
+		t.Fatal("expected an unknown name to return an error")
+	}
+}
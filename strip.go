@@ -0,0 +1,99 @@
+package chess
+
+type gameStripper struct {
+	comments   bool
+	nags       bool
+	variations bool
+	tags       bool
+	keep       []string
+}
+
+// StripComments is an option for Game.Strip that removes comments,
+// including commands such as clock times, and escape lines.
+func StripComments(s *gameStripper) {
+	s.comments = true
+}
+
+// StripNAGs is an option for Game.Strip that removes NAGs and move
+// suffixes.
+func StripNAGs(s *gameStripper) {
+	s.nags = true
+}
+
+// StripVariations is an option for Game.Strip that removes variations.
+func StripVariations(s *gameStripper) {
+	s.variations = true
+}
+
+// StripTags is an option for Game.Strip that removes the tag pairs other
+// than the given keys and the FEN and SetUp tag pairs, which are needed
+// to decode games that don't start from the standard position.
+func StripTags(keep ...string) func(*gameStripper) {
+	return func(s *gameStripper) {
+		s.tags = true
+		s.keep = keep
+	}
+}
+
+// Strip removes annotations and tag pairs from the game as set by the
+// options.  Without options every comment, NAG, variation and tag pair
+// other than the Seven Tag Roster and those needed to decode the game is
+// removed, which leaves the smallest valid PGN with the game's moves and
+// result.  Clones of the game aren't affected.
+func (g *Game) Strip(opts ...func(*gameStripper)) {
+	s := &gameStripper{}
+	for _, f := range opts {
+		if f != nil {
+			f(s)
+		}
+	}
+	if len(opts) == 0 {
+		s = &gameStripper{comments: true, nags: true, variations: true, tags: true, keep: SevenTagRoster}
+	}
+	if s.comments {
+		g.comments = nil
+		g.escapes = nil
+		// the layout of a game decoded with ExactPGN has its escape lines
+		g.layout = nil
+	}
+	if s.tags {
+		keep := append([]string{"FEN", "SetUp"}, s.keep...)
+		tagPairs := []*TagPair{}
+		for _, tag := range g.tagPairs {
+			for _, k := range keep {
+				if tag.Key == k {
+					tagPairs = append(tagPairs, tag)
+					break
+				}
+			}
+		}
+		g.tagPairs = tagPairs
+	}
+	g.moves = s.strip(g.moves)
+}
+
+// strip returns copies of the moves with the annotations removed so the
+// moves of clones aren't modified.
+func (s *gameStripper) strip(moves []*Move) []*Move {
+	stripped := make([]*Move, len(moves))
+	for i, m := range moves {
+		cp := *m
+		if s.comments {
+			cp.comments = nil
+			cp.preComments = nil
+		}
+		if s.nags {
+			cp.nags = nil
+		}
+		if s.variations {
+			cp.variations = nil
+		} else if len(m.variations) > 0 {
+			cp.variations = make([][]*Move, len(m.variations))
+			for j, line := range m.variations {
+				cp.variations[j] = s.strip(line)
+			}
+		}
+		stripped[i] = &cp
+	}
+	return stripped
+}
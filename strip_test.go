@@ -0,0 +1,57 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+const stripTestPGN = `[Event "Test"]
+[Site "?"]
+[FEN "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1"]
+[SetUp "1"]
+[Result "1-0"]
+[Annotator "A"]
+
+{start} 1... e5 {good} $1 (1... c5 {sicilian} 2. Nf3 $2 (2. c3)) 2. Nf3?! Nc6 1-0`
+
+func TestStrip(t *testing.T) {
+	opt, err := PGN(strings.NewReader(stripTestPGN), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(opt)
+	clone := g.Clone()
+	original := clone.String()
+	g.Strip()
+	expected := "[Event \"Test\"]\n[Site \"?\"]\n[FEN \"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1\"]\n[SetUp \"1\"]\n[Result \"1-0\"]\n\n1...e5 2.Nf3 Nc6  1-0"
+	if s := g.String(); s != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, s)
+	}
+	g = NewGame()
+	for _, san := range []string{"e4", "e5", "Nf3"} {
+		if err := g.MoveStr(san); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Strip()
+	if s := g.String(); s != "1.e4 e5 2.Nf3 *" {
+		t.Fatalf("expected a game without tags to have no blank line but got %q", s)
+	}
+	if clone.String() != original {
+		t.Fatalf("expected the clone to keep its annotations but got %s", clone)
+	}
+	opt, err = PGN(strings.NewReader(g.String()))
+	if err != nil || len(NewGame(opt).Moves()) != 3 {
+		t.Fatalf("expected the stripped game to decode but got %v", err)
+	}
+
+	g = clone.Clone()
+	g.Strip(StripNAGs, StripTags("Event", "Result"))
+	s := g.String()
+	if strings.Contains(s, "$") || strings.Contains(s, "?!") || strings.Contains(s, "Annotator") {
+		t.Fatalf("expected NAGs and tags to be removed but got %s", s)
+	}
+	if !strings.Contains(s, "[Event \"Test\"]") || !strings.Contains(s, "sicilian}") || !strings.Contains(s, "2.c3)") {
+		t.Fatalf("expected kept tags, comments and nested variations but got %s", s)
+	}
+}
@@ -0,0 +1,134 @@
+package chess
+
+// GameSummary is an overview of a game for reports and bots.
+type GameSummary struct {
+	// Opening is the name of the opening or empty if it isn't known.
+	Opening string
+	// FirstCapture is the ply of the first capture or zero if there
+	// were no captures.  Plies start at one for the first move.
+	FirstCapture int
+	// Material is the material balance (White minus Black in pawns) of
+	// each position starting with the initial position.
+	Material []int
+	// Checks is the number of moves that gave check.
+	Checks int
+	// Blunder is the ply of the move that lost the most evaluation for
+	// the side that played it or zero if evaluations aren't available or
+	// no move lost at least the blunder threshold.
+	Blunder int
+	// Outcome is the game's outcome.
+	Outcome Outcome
+	// Method is the method of the outcome.
+	Method Method
+	// Termination is the value of the Termination tag pair which
+	// explains results (such as time forfeits) that the Method can't.
+	Termination string
+}
+
+type summary struct {
+	opening   func(moves []*Move) string
+	evals     []float64
+	threshold float64
+}
+
+// SummaryOpening is an option for Summary that names the opening of the
+// game's moves, for example with an opening book.  By default the
+// Opening tag pair is used and then the ECO tag pair.
+func SummaryOpening(f func(moves []*Move) string) func(*summary) {
+	return func(s *summary) {
+		s.opening = f
+	}
+}
+
+// SummaryEvals is an option for Summary that provides the evaluation
+// in pawns from White's perspective after each ply.  By default
+// evaluations are read from the [%eval] commands of the moves' comments.
+func SummaryEvals(evals []float64) func(*summary) {
+	return func(s *summary) {
+		s.evals = evals
+	}
+}
+
+// SummaryBlunderThreshold is an option for Summary that sets the
+// evaluation loss in pawns that makes a move a blunder.  The default
+// is 2.
+func SummaryBlunderThreshold(pawns float64) func(*summary) {
+	return func(s *summary) {
+		s.threshold = pawns
+	}
+}
+
+// Summary returns a summary of the game.
+func (g *Game) Summary(opts ...func(*summary)) *GameSummary {
+	s := &summary{threshold: 2}
+	for _, f := range opts {
+		if f != nil {
+			f(s)
+		}
+	}
+	gs := &GameSummary{
+		Material: g.MaterialHistory().Balance,
+		Outcome:  g.outcome,
+		Method:   g.method,
+	}
+	if s.opening != nil {
+		gs.Opening = s.opening(g.Moves())
+	} else if tp := g.GetTagPair("Opening"); tp != nil {
+		gs.Opening = tp.Value
+	} else if tp := g.GetTagPair("ECO"); tp != nil {
+		gs.Opening = tp.Value
+	}
+	if tp := g.GetTagPair("Termination"); tp != nil {
+		gs.Termination = tp.Value
+	}
+	for i, m := range g.moves {
+		if gs.FirstCapture == 0 && (m.HasTag(Capture) || m.HasTag(EnPassant)) {
+			gs.FirstCapture = i + 1
+		}
+		if m.HasTag(Check) {
+			gs.Checks++
+		}
+	}
+	evals := s.evals
+	if evals == nil {
+		evals = commentEvals(g.moves)
+	}
+	gs.Blunder = blunderPly(g.positions, evals, s.threshold)
+	return gs
+}
+
+// blunderPly returns the ply that lost the most evaluation for the side
+// that moved if the loss is at least the threshold.  evals[i] is the
+// evaluation after the move at index i.
+func blunderPly(positions []*Position, evals []float64, threshold float64) int {
+	ply := 0
+	worst := threshold
+	for i := 1; i < len(evals) && i < len(positions); i++ {
+		loss := evals[i-1] - evals[i]
+		if positions[i].turn == Black {
+			loss = -loss
+		}
+		if loss >= worst {
+			ply = i + 1
+			worst = loss
+		}
+	}
+	return ply
+}
+
+// commentEvals returns the [%eval] of each move's comments or nil if a
+// move doesn't have one.
+func commentEvals(moves []*Move) []float64 {
+	if len(moves) == 0 {
+		return nil
+	}
+	evals := make([]float64, len(moves))
+	for i, m := range moves {
+		eval, ok := m.Eval()
+		if !ok {
+			return nil
+		}
+		evals[i] = eval.Pawns()
+	}
+	return evals
+}
@@ -0,0 +1,44 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	pgn, err := PGN(strings.NewReader(`[Opening "Ruy Lopez"]
+[Termination "normal"]
+[Result "1-0"]
+
+1. e4 {[%eval 0.3]} e5 {[%eval 0.3]} 2. Nf3 {[%eval 0.3]} Nc6 {[%eval 0.3]} 3. Bc4 {[%eval 0.2]} Nd4 {[%eval 0.5]} 4. Nxe5 {[%eval -0.3]} Qg5 {[%eval 0.3]} 5. Nxf7 {[%eval -1.5]} Qxg2 {[%eval -1.5]} 6. Rf1 {[%eval -1.5]} Qxe4+ {[%eval -1.5]} 7. Be2 {[%eval #-1]} Nf3# {[%eval #-1]} 0-1`), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	s := g.Summary()
+	if s.Opening != "Ruy Lopez" || s.Termination != "normal" {
+		t.Fatalf("expected opening and termination from tags but got %q %q", s.Opening, s.Termination)
+	}
+	if s.FirstCapture != 7 {
+		t.Fatalf("expected first capture at ply 7 but got %d", s.FirstCapture)
+	}
+	if s.Checks != 2 {
+		t.Fatalf("expected 2 checks but got %d", s.Checks)
+	}
+	if len(s.Material) != 15 || s.Material[0] != 0 || s.Material[7] != 1 || s.Material[9] != 2 || s.Material[14] != 0 {
+		t.Fatalf("unexpected material trajectory %v", s.Material)
+	}
+	if s.Blunder != 13 {
+		t.Fatalf("expected blunder at ply 13 but got %d", s.Blunder)
+	}
+	if s.Outcome != BlackWon || s.Method != Checkmate {
+		t.Fatalf("expected black to win by checkmate but got %s %s", s.Outcome, s.Method)
+	}
+
+	s = g.Summary(SummaryEvals([]float64{}), SummaryOpening(func(moves []*Move) string {
+		return "C50"
+	}))
+	if s.Blunder != 0 || s.Opening != "C50" {
+		t.Fatalf("expected options to override the defaults but got %d %q", s.Blunder, s.Opening)
+	}
+}
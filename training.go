@@ -0,0 +1,223 @@
+package chess
+
+import (
+	"io"
+	"math/rand"
+	"sort"
+)
+
+// MoveIndexCount is the number of distinct values returned by MoveIndex.
+const MoveIndexCount = 4 * 64 * 64
+
+// MoveIndex returns an index for the move in [0, MoveIndexCount) for use
+// as a policy target.  The index is s1*64 + s2 for moves and queen
+// promotions with 4096, 8192 or 12288 added for knight, bishop and rook
+// underpromotions.
+func MoveIndex(m *Move) int {
+	i := int(m.s1)*64 + int(m.s2)
+	switch m.promo {
+	case Knight:
+		i += 4096
+	case Bishop:
+		i += 2 * 4096
+	case Rook:
+		i += 3 * 4096
+	}
+	return i
+}
+
+// TrainingSample is a position from a game, the move played and the
+// game's outcome.
+type TrainingSample struct {
+	Position *Position
+	Move     *Move
+	Outcome  Outcome
+}
+
+// MoveIndex returns MoveIndex of the sample's move.
+func (s *TrainingSample) MoveIndex() int {
+	return MoveIndex(s.Move)
+}
+
+// Value returns the outcome from the perspective of the color to move:
+// 1 for a win, -1 for a loss and 0 for a draw.
+func (s *TrainingSample) Value() float32 {
+	switch {
+	case s.Outcome == Draw:
+		return 0
+	case (s.Outcome == WhiteWon) == (s.Position.Turn() == White):
+		return 1
+	}
+	return -1
+}
+
+type trainingScanner struct {
+	skipPlies int
+	// maxOccurrences limits the samples of each position if positive
+	maxOccurrences int
+	rate           float64
+	float64n       func() float64
+	// reservoir is the size of the uniform sample of the whole input if
+	// positive
+	reservoir     int
+	reservoirIntn func(int) int
+}
+
+// TrainingSkipPlies is an option for NewTrainingScanner that skips the
+// first n plies of each game, which are often book moves.
+func TrainingSkipPlies(n int) func(*trainingScanner) {
+	return func(t *trainingScanner) {
+		t.skipPlies = n
+	}
+}
+
+// TrainingDeduplicate is an option for NewTrainingScanner that only emits
+// the first occurrence of each position.
+func TrainingDeduplicate(t *trainingScanner) {
+	t.maxOccurrences = 1
+}
+
+// TrainingMaxOccurrences is an option for NewTrainingScanner that only
+// emits the first n occurrences of each position so common opening
+// positions don't dominate the samples.
+func TrainingMaxOccurrences(n int) func(*trainingScanner) {
+	return func(t *trainingScanner) {
+		t.maxOccurrences = n
+	}
+}
+
+// TrainingReservoir is an option for NewTrainingScanner that emits a
+// uniform random sample of n of the samples using reservoir sampling with
+// the random source r.  The whole input is read by the first Scan and only
+// n samples are held in memory.  Samples are emitted in input order.  If
+// r is nil the math/rand default source is used.
+func TrainingReservoir(n int, r *rand.Rand) func(*trainingScanner) {
+	return func(t *trainingScanner) {
+		t.reservoir = n
+		t.reservoirIntn = rand.Intn
+		if r != nil {
+			t.reservoirIntn = r.Intn
+		}
+	}
+}
+
+// TrainingSampleRate is an option for NewTrainingScanner that emits each
+// position with the given probability using the random source r.  If r is
+// nil the math/rand default source is used.
+func TrainingSampleRate(rate float64, r *rand.Rand) func(*trainingScanner) {
+	return func(t *trainingScanner) {
+		t.rate = rate
+		t.float64n = rand.Float64
+		if r != nil {
+			t.float64n = r.Float64
+		}
+	}
+}
+
+// TrainingScanner streams training samples from concatenated PGN games.
+// Games without a result are skipped.  Like Scanner, it is used by
+// calling Scan until it returns false and then checking Err.
+type TrainingScanner struct {
+	opts    *trainingScanner
+	games   *Scanner
+	seen    map[[16]byte]int
+	pending []*TrainingSample
+	sample  *TrainingSample
+	// filled is true once the reservoir has been filled from the input
+	filled bool
+}
+
+// NewTrainingScanner returns a training scanner reading PGN from r.
+func NewTrainingScanner(r io.Reader, opts ...func(*trainingScanner)) *TrainingScanner {
+	t := &trainingScanner{rate: 1}
+	for _, f := range opts {
+		if f != nil {
+			f(t)
+		}
+	}
+	return &TrainingScanner{opts: t, games: NewScanner(r), seen: map[[16]byte]int{}}
+}
+
+// Scan advances to the next sample and returns false when the input is
+// exhausted or an error occurs.
+func (s *TrainingScanner) Scan() bool {
+	if s.opts.reservoir > 0 && !s.filled {
+		s.fillReservoir()
+	}
+	for len(s.pending) == 0 {
+		if s.filled {
+			return false
+		}
+		if !s.games.Scan() {
+			return false
+		}
+		s.pending = s.samples(s.games.Next())
+	}
+	s.sample = s.pending[0]
+	s.pending = s.pending[1:]
+	return true
+}
+
+// Sample returns the sample from the most recent Scan.
+func (s *TrainingScanner) Sample() *TrainingSample {
+	return s.sample
+}
+
+// Err returns the error encountered while scanning games, if any.
+func (s *TrainingScanner) Err() error {
+	return s.games.Err()
+}
+
+func (s *TrainingScanner) samples(g *Game) []*TrainingSample {
+	samples := []*TrainingSample{}
+	if g.Outcome() == NoOutcome {
+		return samples
+	}
+	for i, m := range g.moves {
+		// null moves have no policy target
+		if i < s.opts.skipPlies || m.IsNull() {
+			continue
+		}
+		pos := g.positions[i]
+		if s.opts.maxOccurrences > 0 {
+			h := pos.NormalizedHash()
+			if s.seen[h] >= s.opts.maxOccurrences {
+				continue
+			}
+			s.seen[h]++
+		}
+		if s.opts.rate < 1 && s.opts.float64n() >= s.opts.rate {
+			continue
+		}
+		samples = append(samples, &TrainingSample{Position: pos, Move: m, Outcome: g.Outcome()})
+	}
+	return samples
+}
+
+// fillReservoir reads every sample keeping a uniform random sample of the
+// reservoir's size.
+func (s *TrainingScanner) fillReservoir() {
+	s.filled = true
+	type entry struct {
+		index  int
+		sample *TrainingSample
+	}
+	reservoir := []entry{}
+	n := 0
+	for s.games.Scan() {
+		for _, sample := range s.samples(s.games.Next()) {
+			if len(reservoir) < s.opts.reservoir {
+				reservoir = append(reservoir, entry{index: n, sample: sample})
+			} else if i := s.opts.reservoirIntn(n + 1); i < s.opts.reservoir {
+				reservoir[i] = entry{index: n, sample: sample}
+			}
+			n++
+		}
+	}
+	sort.Slice(reservoir, func(i, j int) bool {
+		return reservoir[i].index < reservoir[j].index
+	})
+	for _, e := range reservoir {
+		s.pending = append(s.pending, e.sample)
+	}
+}
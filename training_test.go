@@ -0,0 +1,104 @@
+package chess
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+const trainingPGN = `[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 1-0
+
+[Result "*"]
+
+1. e4 e5 *
+
+[Result "0-1"]
+
+1. e4 e5 2. Nf3 Nf6 0-1
+
+`
+
+func TestTrainingScanner(t *testing.T) {
+	tests := []struct {
+		opts    []func(*trainingScanner)
+		samples int
+	}{
+		{nil, 8},
+		{[]func(*trainingScanner){TrainingSkipPlies(2)}, 4},
+		{[]func(*trainingScanner){TrainingDeduplicate}, 4},
+		{[]func(*trainingScanner){TrainingSampleRate(0, rand.New(rand.NewSource(1)))}, 0},
+		{[]func(*trainingScanner){TrainingMaxOccurrences(2)}, 8},
+		{[]func(*trainingScanner){TrainingReservoir(3, rand.New(rand.NewSource(1)))}, 3},
+		{[]func(*trainingScanner){TrainingReservoir(100, rand.New(rand.NewSource(1)))}, 8},
+		{[]func(*trainingScanner){TrainingDeduplicate, TrainingReservoir(2, rand.New(rand.NewSource(1)))}, 2},
+	}
+	for i, test := range tests {
+		s := NewTrainingScanner(strings.NewReader(trainingPGN), test.opts...)
+		n := 0
+		for s.Scan() {
+			n++
+		}
+		if s.Err() != nil {
+			t.Fatal(s.Err())
+		}
+		if n != test.samples {
+			t.Fatalf("test %d expected %d samples but got %d", i, test.samples, n)
+		}
+	}
+	s := NewTrainingScanner(strings.NewReader(trainingPGN))
+	s.Scan()
+	sample := s.Sample()
+	if sample.MoveIndex() != int(E2)*64+int(E4) || sample.Value() != 1 {
+		t.Fatalf("unexpected first sample %d %f", sample.MoveIndex(), sample.Value())
+	}
+}
+
+func TestTrainingReservoirUniform(t *testing.T) {
+	// every sample is kept about equally often
+	r := rand.New(rand.NewSource(1))
+	counts := map[int]int{}
+	for i := 0; i < 2000; i++ {
+		s := NewTrainingScanner(strings.NewReader(trainingPGN), TrainingReservoir(2, r))
+		for s.Scan() {
+			p := s.Sample().Position.moveCount*2 - 2
+			if s.Sample().Position.Turn() == Black {
+				p++
+			}
+			counts[p]++
+		}
+	}
+	for p, n := range counts {
+		// 4 plies appear twice in 8 samples so each is kept half the time
+		if n < 850 || n > 1150 {
+			t.Fatalf("expected ply %d to be sampled about 1000 times but got %d", p, n)
+		}
+	}
+}
+
+func TestMoveIndex(t *testing.T) {
+	if i := MoveIndex(&Move{s1: A7, s2: A8, promo: Rook}); i != 3*4096+int(A7)*64+int(A8) {
+		t.Fatalf("unexpected rook promotion index %d", i)
+	}
+}
+
+func TestTrainingSampleRateDefaultSource(t *testing.T) {
+	s := NewTrainingScanner(strings.NewReader(trainingPGN), TrainingSampleRate(0.5, nil))
+	for s.Scan() {
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrainingReservoirDefaultSource(t *testing.T) {
+	s := NewTrainingScanner(strings.NewReader(trainingPGN), TrainingReservoir(2, nil))
+	n := 0
+	for s.Scan() {
+		n++
+	}
+	if err := s.Err(); err != nil || n != 2 {
+		t.Fatalf("expected 2 samples but got %d and %v", n, err)
+	}
+}
@@ -0,0 +1,75 @@
+package chess
+
+// Variations returns the lines played instead of the move, such as the
+// recursive annotation variations of PGN decoded with PreservePGN or
+// those added with the Game's AddVariation method.  Each line begins
+// with a move from the same position as the receiver.
+func (m *Move) Variations() [][]*Move {
+	variations := make([][]*Move, len(m.variations))
+	for i, v := range m.variations {
+		variations[i] = append([]*Move(nil), v...)
+	}
+	return variations
+}
+
+// A GameNode is a position in the tree of a game's mainline and its
+// variations.
+type GameNode struct {
+	// Parent is the node before the move or nil for the root.
+	Parent *GameNode
+	// Move is the move that reached the node or nil for the root.
+	Move *Move
+	// Position is the position after the move.
+	Position *Position
+	// Children are the moves played from the position.  The first child
+	// continues the line and the rest are its variations.
+	Children []*GameNode
+}
+
+// Tree returns the game as a tree whose root is the starting position.
+// Following the first child of each node gives the mainline.
+func (g *Game) Tree() *GameNode {
+	root := &GameNode{Position: g.positions[0]}
+	buildTree(root, g.moves)
+	return root
+}
+
+// Ply returns the number of moves from the root to the node.
+func (n *GameNode) Ply() int {
+	ply := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		ply++
+	}
+	return ply
+}
+
+// Line returns the moves from the root to the node.
+func (n *GameNode) Line() []*Move {
+	moves := make([]*Move, n.Ply())
+	for i, node := len(moves)-1, n; i >= 0; i, node = i-1, node.Parent {
+		moves[i] = node.Move
+	}
+	return moves
+}
+
+// Mainline returns the nodes that continue the line from the node by
+// following each first child.
+func (n *GameNode) Mainline() []*GameNode {
+	nodes := []*GameNode{}
+	for node := n; len(node.Children) > 0; node = node.Children[0] {
+		nodes = append(nodes, node.Children[0])
+	}
+	return nodes
+}
+
+// buildTree adds the line of moves and their variations below the node.
+func buildTree(parent *GameNode, moves []*Move) {
+	for _, m := range moves {
+		node := &GameNode{Parent: parent, Move: m, Position: parent.Position.Update(m)}
+		parent.Children = append(parent.Children, node)
+		for _, v := range m.variations {
+			buildTree(parent, v)
+		}
+		parent = node
+	}
+}
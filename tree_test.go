@@ -0,0 +1,43 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGameTree(t *testing.T) {
+	pgn, err := PGN(strings.NewReader("1. e4 (1. d4 d5 (1... Nf6 2. c4) 2. c4) 1... e5 (1... c5 2. Nf3) 2. Nf3 *"), PreservePGN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(pgn)
+	if v := g.Moves()[0].Variations(); len(v) != 1 || len(v[0]) != 3 || len(v[0][0].Variations()) != 0 || len(v[0][1].Variations()) != 1 {
+		t.Fatalf("unexpected variations %v", v)
+	}
+	root := g.Tree()
+	if root.Move != nil || len(root.Children) != 2 {
+		t.Fatalf("expected the root to have the mainline and one variation but got %d children", len(root.Children))
+	}
+	mainline := root.Mainline()
+	if len(mainline) != 3 || mainline[2].Position.String() != g.Position().String() {
+		t.Fatalf("expected the mainline to end in the game's position")
+	}
+	d4 := root.Children[1]
+	if d4.Move.String() != "d2d4" || len(d4.Children) != 2 {
+		t.Fatalf("expected 1. d4 with two replies")
+	}
+	c4 := d4.Children[1].Children[0]
+	if c4.Ply() != 3 {
+		t.Fatalf("expected ply 3 but got %d", c4.Ply())
+	}
+	line := []string{}
+	for _, m := range c4.Line() {
+		line = append(line, m.String())
+	}
+	if s := strings.Join(line, " "); s != "d2d4 g8f6 c2c4" {
+		t.Fatalf("expected line d2d4 g8f6 c2c4 but got %s", s)
+	}
+	if !strings.Contains(g.String(), "(1.d4 d5 (1...Nf6 2.c4) 2.c4)") {
+		t.Fatalf("expected variations to be encoded but got %s", g.String())
+	}
+}
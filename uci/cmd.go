@@ -59,7 +59,7 @@ var (
 				break
 			}
 		}
-		return nil
+		return e.setDefaults()
 	}}
 
 	// CmdIsReady corresponds to the "isready" command:
@@ -257,7 +257,7 @@ func (cmd CmdGo) String() string {
 		a = append(a, "nodes", fmt.Sprint(cmd.Nodes))
 	}
 	if cmd.Mate > 0 {
-		a = append(a, "mate", fmt.Sprint(cmd.Nodes))
+		a = append(a, "mate", fmt.Sprint(cmd.Mate))
 	}
 	if cmd.MoveTime > 0 {
 		a = append(a, "movetime", msecStr(cmd.MoveTime))
@@ -305,12 +305,32 @@ func (CmdGo) ProcessResponse(e *Engine) error {
 		err := info.UnmarshalText([]byte(text))
 		if err == nil {
 			results.Info = *info
+			if len(info.PV) > 0 {
+				results.MultiPV = addMultiPV(results.MultiPV, *info)
+			}
 		}
 	}
 	e.results = results
 	return nil
 }
 
+// addMultiPV replaces the info with the same multipv or inserts it in order.
+func addMultiPV(infos []Info, info Info) []Info {
+	for i, existing := range infos {
+		if existing.Multipv == info.Multipv {
+			infos[i] = info
+			return infos
+		}
+		if existing.Multipv > info.Multipv {
+			infos = append(infos, Info{})
+			copy(infos[i+1:], infos[i:])
+			infos[i] = info
+			return infos
+		}
+	}
+	return append(infos, info)
+}
+
 func parseIDLine(s string) (string, string, error) {
 	if strings.HasPrefix(s, "id") == false {
 		return "", "", errors.New("uci: invalid id line")
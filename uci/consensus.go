@@ -0,0 +1,136 @@
+package uci
+
+import (
+	"context"
+	"sync"
+
+	"github.com/notnil/chess"
+)
+
+// An Analyst is an engine and the search it analyses positions with.
+// Several analysts may share an engine, for example to compare searches
+// to different depths.
+type Analyst struct {
+	Name   string
+	Engine *Engine
+	Go     CmdGo
+}
+
+// AnalystResult is an analyst's choice of move in a position.  The score
+// is from the point of view of the side to move.
+type AnalystResult struct {
+	Name  string
+	Move  *chess.Move
+	Score Score
+}
+
+// Consensus is the agreement of several analysts about a position.
+type Consensus struct {
+	// Results are the analysts' results in the order of the analysts.
+	Results []AnalystResult
+	// Move is the move chosen by the most analysts with ties going to the
+	// move of the earliest analyst.
+	Move *chess.Move
+	// Agreement is the fraction of the analysts that chose Move.
+	Agreement float64
+	// Spread is the difference in centipawns between the highest and
+	// lowest scores with scores capped at 1000 centipawns as with
+	// MoveEval.Loss.
+	Spread int
+}
+
+// NewConsensus returns the consensus of the results.
+func NewConsensus(results []AnalystResult) *Consensus {
+	c := &Consensus{Results: results}
+	if len(results) == 0 {
+		return c
+	}
+	counts := map[string]int{}
+	for _, r := range results {
+		if r.Move != nil {
+			counts[r.Move.String()]++
+		}
+	}
+	best := 0
+	low, high := lossCP(results[0].Score), lossCP(results[0].Score)
+	for _, r := range results {
+		if r.Move != nil && counts[r.Move.String()] > best {
+			best = counts[r.Move.String()]
+			c.Move = r.Move
+		}
+		if cp := lossCP(r.Score); cp < low {
+			low = cp
+		} else if cp > high {
+			high = cp
+		}
+	}
+	c.Agreement = float64(best) / float64(len(results))
+	c.Spread = high - low
+	return c
+}
+
+// Unanimous returns true if every analyst chose the same move.
+func (c *Consensus) Unanimous() bool {
+	return c.Move != nil && c.Agreement == 1
+}
+
+// AnalyzeConsensus searches the position with each analyst and returns
+// their consensus.  Different engines search concurrently and analysts
+// sharing an engine search one after another.
+func AnalyzeConsensus(pos *chess.Position, analysts ...Analyst) (*Consensus, error) {
+	return analyzeConsensus(context.Background(), pos, analysts...)
+}
+
+// analyzeConsensus is like AnalyzeConsensus but stops the searches when
+// the context is canceled.
+func analyzeConsensus(ctx context.Context, pos *chess.Position, analysts ...Analyst) (*Consensus, error) {
+	results := make([]AnalystResult, len(analysts))
+	errs := make([]error, len(analysts))
+	groups := map[*Engine][]int{}
+	order := []*Engine{}
+	for i, a := range analysts {
+		if _, ok := groups[a.Engine]; !ok {
+			order = append(order, a.Engine)
+		}
+		groups[a.Engine] = append(groups[a.Engine], i)
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(order))
+	for _, e := range order {
+		go func(e *Engine, indexes []int) {
+			defer wg.Done()
+			for _, i := range indexes {
+				search, err := e.searchMoves(ctx, pos, analysts[i].Go)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				results[i] = AnalystResult{Name: analysts[i].Name, Move: search.BestMove, Score: search.bestLine().Score}
+			}
+		}(e, groups[e])
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewConsensus(results), nil
+}
+
+// GameConsensus returns the consensus of the analysts for the position
+// before each move of the game.  Analysis stops with the context's error if
+// the game's context is canceled, including searches already running,
+// which are sent the stop command.
+func GameConsensus(g *chess.Game, analysts ...Analyst) ([]*Consensus, error) {
+	positions := g.Positions()
+	consensus := []*Consensus{}
+	for i := range g.Moves() {
+		c, err := analyzeConsensus(g.Context(), positions[i], analysts...)
+		if err != nil {
+			return nil, err
+		}
+		consensus = append(consensus, c)
+	}
+	return consensus, nil
+}
@@ -0,0 +1,125 @@
+package uci_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/uci"
+)
+
+func TestNewConsensus(t *testing.T) {
+	move := func(s string) *chess.Move {
+		m, err := chess.UCINotation{}.Decode(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	c := uci.NewConsensus([]uci.AnalystResult{
+		{Name: "a", Move: move("e2e4"), Score: uci.Score{CP: 30}},
+		{Name: "b", Move: move("d2d4"), Score: uci.Score{CP: 20}},
+		{Name: "c", Move: move("d2d4"), Score: uci.Score{CP: -10}},
+		{Name: "d", Move: move("e2e4"), Score: uci.Score{Mate: 5}},
+	})
+	if c.Move.String() != "e2e4" || c.Agreement != 0.5 || c.Unanimous() {
+		t.Fatalf("expected the tie to go to the first analyst's e2e4 with half agreeing but got %s %v", c.Move, c.Agreement)
+	}
+	if c.Spread != 1010 {
+		t.Fatalf("expected a spread of 1010 but got %d", c.Spread)
+	}
+}
+
+const fakeSearchEngine = `#!/bin/sh
+while read line; do
+	case "$line" in
+	"go depth 1"*)
+		echo "info depth 1 score cp 10 pv e2e4"
+		echo "bestmove e2e4"
+		;;
+	go*)
+		echo "info depth 8 multipv 1 score cp 35 pv d2d4"
+		echo "info depth 8 multipv 2 score cp -100 pv a2a3"
+		echo "bestmove d2d4"
+		;;
+	quit)
+		exit 0
+		;;
+	esac
+done
+`
+
+func TestAnalyzeConsensus(t *testing.T) {
+	path, cleanup := writeFakeEngine(t, fakeSearchEngine)
+	defer cleanup()
+	engines := []*uci.Engine{}
+	for i := 0; i < 2; i++ {
+		eng, err := uci.New(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer eng.Close()
+		engines = append(engines, eng)
+	}
+	analysts := []uci.Analyst{
+		{Name: "shallow", Engine: engines[0], Go: uci.CmdGo{Depth: 1}},
+		{Name: "deep", Engine: engines[0], Go: uci.CmdGo{Depth: 8}},
+		{Name: "other", Engine: engines[1], Go: uci.CmdGo{Depth: 8}},
+	}
+	g := chess.NewGame()
+	if err := g.MoveStr("e4"); err != nil {
+		t.Fatal(err)
+	}
+	consensus, err := uci.GameConsensus(g, analysts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(consensus) != 1 {
+		t.Fatalf("expected a consensus for each move but got %d", len(consensus))
+	}
+	c := consensus[0]
+	if c.Move.String() != "d2d4" || c.Agreement != 2.0/3 || c.Spread != 25 {
+		t.Fatalf("expected d2d4 with two thirds agreeing and a spread of 25 but got %s %v %d", c.Move, c.Agreement, c.Spread)
+	}
+	for i, r := range c.Results {
+		if r.Name != analysts[i].Name {
+			t.Fatalf("expected the results in the order of the analysts but got %s at %d", r.Name, i)
+		}
+	}
+}
+
+// fakeInfiniteEngine only ends its searches when told to stop.
+const fakeInfiniteEngine = `#!/bin/sh
+while read line; do
+	case "$line" in
+	stop)
+		echo "info depth 3 score cp 10 pv e2e4"
+		echo "bestmove e2e4"
+		;;
+	quit)
+		exit 0
+		;;
+	esac
+done
+`
+
+func TestGameConsensusCanceled(t *testing.T) {
+	path, cleanup := writeFakeEngine(t, fakeInfiniteEngine)
+	defer cleanup()
+	eng, err := uci.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	g := chess.NewGame(chess.GameContext(ctx))
+	if err := g.MoveStr("e4"); err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = uci.GameConsensus(g, uci.Analyst{Name: "a", Engine: eng, Go: uci.CmdGo{Infinite: true}})
+	if err != context.Canceled {
+		t.Fatalf("expected the running search to be stopped but got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"time"
 )
 
 // Engine represents a UCI compliant chess engine (e.g. Stockfish, Shredder, etc.).
@@ -22,6 +23,13 @@ type Engine struct {
 	options map[string]Option
 	results SearchResults
 	mu      *sync.RWMutex
+	hooks   []func(LogEntry)
+	// exited is closed once the process has exited
+	exited          chan struct{}
+	shutdownTimeout time.Duration
+	threads         int
+	hash            int
+	nice            *int
 }
 
 // Debug is an option for the New function to add logging for debugging.  This will
@@ -51,11 +59,27 @@ func New(path string, opts ...func(e *Engine)) (*Engine, error) {
 	cmd := exec.Command(path)
 	cmd.Stdin = rIn
 	cmd.Stdout = wOut
-	e := &Engine{cmd: cmd, in: wIn, out: rOut, mu: &sync.RWMutex{}, logger: log.New(os.Stdout, "uci", log.LstdFlags)}
+	e := &Engine{cmd: cmd, in: wIn, out: rOut, mu: &sync.RWMutex{}, logger: log.New(os.Stdout, "uci", log.LstdFlags),
+		exited: make(chan struct{}), shutdownTimeout: defaultShutdownTimeout}
 	for _, opt := range opts {
 		opt(e)
 	}
-	go e.cmd.Run()
+	if len(e.hooks) > 0 {
+		cmd.Stderr = &stderrWriter{e: e}
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("uci: failed to start %s %w", path, err)
+	}
+	go func() {
+		cmd.Wait()
+		close(e.exited)
+	}()
+	if e.nice != nil {
+		if err := setPriority(cmd.Process.Pid, *e.nice); err != nil {
+			e.kill()
+			return nil, fmt.Errorf("uci: failed to set the priority of %s %w", path, err)
+		}
+	}
 	return e, nil
 }
 
@@ -139,18 +163,37 @@ func (e *Engine) Run(cmds ...Cmd) error {
 }
 
 // Close releases readers, writers, and processes associated with the
-// Engine.  It also invokes the CmdQuit to signal the engine to terminate.
+// Engine.  It invokes the CmdQuit to signal the engine to terminate and
+// kills the process if it hasn't exited by the ShutdownTimeout.
 func (e *Engine) Close() error {
-	if err := e.Run(CmdQuit); err != nil {
-		return err
-	}
-	if err := e.in.Close(); err != nil {
-		return err
+	err := e.Run(CmdQuit)
+	e.in.Close()
+	e.out.Close()
+	if e.shutdownTimeout > 0 {
+		timer := time.NewTimer(e.shutdownTimeout)
+		defer timer.Stop()
+		select {
+		case <-e.exited:
+			return err
+		case <-timer.C:
+		}
 	}
-	if err := e.out.Close(); err != nil {
-		return err
+	e.kill()
+	return err
+}
+
+// kill kills the process unless it has already exited and waits for it.
+// The pipes are closed so the process's I/O ends.
+func (e *Engine) kill() {
+	e.in.Close()
+	e.out.Close()
+	select {
+	case <-e.exited:
+		return
+	default:
 	}
-	return e.cmd.Process.Kill()
+	e.cmd.Process.Kill()
+	<-e.exited
 }
 
 func (e *Engine) processCommandLocked(cmd Cmd) error {
@@ -163,6 +206,7 @@ func (e *Engine) processCommand(cmd Cmd) error {
 	if e.debug {
 		e.logger.Println(cmd.String())
 	}
+	e.log(LogSent, cmd.String())
 	if _, err := fmt.Fprintln(e.in, cmd.String()); err != nil {
 		return err
 	}
@@ -177,5 +221,6 @@ func (e *Engine) readLine(scanner *bufio.Scanner) string {
 	if e.debug {
 		e.logger.Println(s)
 	}
+	e.log(LogReceived, s)
 	return s
 }
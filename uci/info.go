@@ -17,6 +17,9 @@ type SearchResults struct {
 	BestMove *chess.Move
 	Ponder   *chess.Move
 	Info     Info
+	// MultiPV is the most recent info with a pv for each line ordered by
+	// multipv.  It has more than one entry when the MultiPV option is set.
+	MultiPV []Info
 }
 
 // Info corresponds to the "info" engine output:
@@ -0,0 +1,66 @@
+package uci
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultShutdownTimeout is how long Close waits for the engine to quit.
+const defaultShutdownTimeout = time.Second
+
+// ShutdownTimeout is an option for the New function that sets how long
+// Close waits for the engine to exit after the quit command before the
+// process is killed.  The default is one second and zero kills the
+// process immediately.
+func ShutdownTimeout(d time.Duration) func(e *Engine) {
+	return func(e *Engine) {
+		e.shutdownTimeout = d
+	}
+}
+
+// Threads is an option for the New function that sets the engine's
+// Threads option after each CmdUCI if the engine supports it, so services
+// running many engines can keep each one to a share of the CPUs.
+func Threads(n int) func(e *Engine) {
+	return func(e *Engine) {
+		e.threads = n
+	}
+}
+
+// Hash is an option for the New function that sets the engine's Hash
+// option, the size of its hash table in megabytes, after each CmdUCI if
+// the engine supports it.
+func Hash(mb int) func(e *Engine) {
+	return func(e *Engine) {
+		e.hash = mb
+	}
+}
+
+// Priority is an option for the New function that sets the scheduling
+// priority of the engine process as with nice, where 19 is the lowest
+// priority.  Raising the priority above the default usually requires
+// privileges.  New returns an error if the priority can't be set, which
+// is always the case on platforms without setpriority such as Windows.
+func Priority(nice int) func(e *Engine) {
+	return func(e *Engine) {
+		e.nice = &nice
+	}
+}
+
+// setDefaults sets the Threads and Hash options the engine supports.  It
+// is called with the lock held after the options are read.
+func (e *Engine) setDefaults() error {
+	defaults := []struct {
+		name  string
+		value int
+	}{{"Threads", e.threads}, {"Hash", e.hash}}
+	for _, d := range defaults {
+		if _, ok := e.options[d.name]; !ok || d.value <= 0 {
+			continue
+		}
+		if err := e.processCommand(CmdSetOption{Name: d.name, Value: fmt.Sprint(d.value)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
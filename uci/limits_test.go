@@ -0,0 +1,82 @@
+package uci_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess/uci"
+)
+
+func TestShutdownTimeout(t *testing.T) {
+	// the engine ignores quit so Close has to kill it
+	path, cleanup := writeFakeEngine(t, "#!/bin/sh\nexec sleep 30\n")
+	defer cleanup()
+	eng, err := uci.New(path, uci.ShutdownTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := eng.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("expected the engine to be killed after the timeout but Close took %s", d)
+	}
+}
+
+const fakeOptionsEngine = `#!/bin/sh
+while read line; do
+	case "$line" in
+	uci)
+		echo "id name Fake"
+		echo "option name Threads type spin default 1 min 1 max 512"
+		echo uciok
+		;;
+	quit)
+		exit 0
+		;;
+	esac
+done
+`
+
+func TestResourceDefaults(t *testing.T) {
+	path, cleanup := writeFakeEngine(t, fakeOptionsEngine)
+	defer cleanup()
+	var mu sync.Mutex
+	sent := []string{}
+	hook := func(entry uci.LogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		if entry.Source == uci.LogSent {
+			sent = append(sent, entry.Text)
+		}
+	}
+	opts := []func(*uci.Engine){uci.Threads(2), uci.Hash(64), uci.LogHook(hook)}
+	if runtime.GOOS == "linux" {
+		opts = append(opts, uci.Priority(10))
+	}
+	eng, err := uci.New(path, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.Run(uci.CmdUCI); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.Close(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// Hash isn't set because the engine doesn't have the option
+	expected := []string{"uci", "setoption name Threads value 2", "quit"}
+	if len(sent) != len(expected) {
+		t.Fatalf("expected %q to be sent but got %q", expected, sent)
+	}
+	for i := range expected {
+		if sent[i] != expected[i] {
+			t.Fatalf("expected %q to be sent but got %q", expected, sent)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package uci
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A LogSource is where a logged line came from.
+type LogSource int
+
+const (
+	// LogSent is a command written to the engine.
+	LogSent LogSource = iota
+	// LogReceived is a line of the engine's protocol output.
+	LogReceived
+	// LogStderr is a line the engine wrote to stderr.
+	LogStderr
+)
+
+func (s LogSource) String() string {
+	switch s {
+	case LogSent:
+		return "sent"
+	case LogReceived:
+		return "received"
+	case LogStderr:
+		return "stderr"
+	}
+	return "unknown"
+}
+
+// LogEntry is a line of communication with the engine.
+type LogEntry struct {
+	Time   time.Time
+	Source LogSource
+	Text   string
+}
+
+// LogHook is an option for the New function that calls f with every
+// command sent to the engine, every line of its output and every line it
+// writes to stderr, which is otherwise discarded.  Stderr is read in the
+// background so f must be safe for concurrent use.  LogHook may be used
+// more than once to add several hooks.
+func LogHook(f func(LogEntry)) func(e *Engine) {
+	return func(e *Engine) {
+		e.hooks = append(e.hooks, f)
+	}
+}
+
+// log passes the line to the hooks.
+func (e *Engine) log(source LogSource, text string) {
+	if len(e.hooks) == 0 {
+		return
+	}
+	entry := LogEntry{Time: time.Now(), Source: source, Text: text}
+	for _, f := range e.hooks {
+		f(entry)
+	}
+}
+
+// stderrWriter logs each complete line written to it.
+type stderrWriter struct {
+	e   *Engine
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *stderrWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			return len(p), nil
+		}
+		w.e.log(LogStderr, strings.TrimRight(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+}
@@ -0,0 +1,85 @@
+package uci_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess/uci"
+)
+
+const fakeEngine = `#!/bin/sh
+while read line; do
+	case "$line" in
+	isready)
+		echo "warming up" >&2
+		echo readyok
+		;;
+	quit)
+		exit 0
+		;;
+	esac
+done
+`
+
+// writeFakeEngine writes the shell script to a temporary file and returns
+// its path and a function that removes it.
+func writeFakeEngine(t *testing.T, script string) (string, func()) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	dir, err := ioutil.TempDir("", "uci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "engine")
+	if err := ioutil.WriteFile(path, []byte(script), 0755); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestLogHook(t *testing.T) {
+	path, cleanup := writeFakeEngine(t, fakeEngine)
+	defer cleanup()
+	var mu sync.Mutex
+	entries := []uci.LogEntry{}
+	hook := func(entry uci.LogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, entry)
+	}
+	start := time.Now()
+	eng, err := uci.New(path, uci.LogHook(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	if err := eng.Run(uci.CmdIsReady); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[uci.LogSource]string{uci.LogSent: "isready", uci.LogReceived: "readyok", uci.LogStderr: "warming up"}
+	for deadline := time.Now().Add(time.Second); ; {
+		mu.Lock()
+		logged := append([]uci.LogEntry(nil), entries...)
+		mu.Unlock()
+		found := 0
+		for _, entry := range logged {
+			if expected[entry.Source] == entry.Text && !entry.Time.Before(start) {
+				found++
+			}
+		}
+		if found == len(expected) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected sent, received and stderr entries but got %+v", logged)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,111 @@
+package uci
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/internal/weighted"
+	"github.com/notnil/chess/opening"
+)
+
+// HumanPolicy chooses moves among an engine's candidates the way a human
+// might, to make bot opponents less robotic.  Candidates are the MultiPV
+// lines of a search so the engine's MultiPV option should be above one.
+type HumanPolicy struct {
+	// Temperature in centipawns controls how often weaker candidates are
+	// chosen.  Candidates are weighted by exp(-gap/Temperature) where gap
+	// is how far their score is below the best.  Zero always chooses the
+	// best candidate.
+	Temperature float64
+	// BlunderRate is the probability from 0 to 1 of deliberately choosing
+	// a sub-optimal candidate.  Smaller eval gaps are more likely.
+	BlunderRate float64
+	// MaxGap is the largest gap in centipawns of a candidate that can be
+	// chosen.  Zero allows any gap.
+	MaxGap int
+	// Book, if set, is an explorer whose moves are played while the
+	// position is in it, weighted by how often they were played.
+	Book *opening.Explorer
+	// BookMinGames is the fewest games a book move needs to be played.
+	BookMinGames int
+}
+
+// Choose returns the move to play in the position given the results of
+// searching it.  A book move is returned if the position is in Book.
+// Otherwise a candidate is chosen by BlunderRate and Temperature falling
+// back to the best move if there are no candidates.  If r is nil the
+// math/rand default source is used.
+func (p HumanPolicy) Choose(pos *chess.Position, results SearchResults, r *rand.Rand) *chess.Move {
+	if m := p.bookMove(pos, r); m != nil {
+		return m
+	}
+	if len(results.MultiPV) == 0 {
+		return results.BestMove
+	}
+	best := bestScore(results.MultiPV)
+	moves := []*chess.Move{}
+	gaps := []int{}
+	for _, info := range results.MultiPV {
+		gap := best - scoreValue(info.Score)
+		if p.MaxGap > 0 && gap > p.MaxGap {
+			continue
+		}
+		moves = append(moves, info.PV[0])
+		gaps = append(gaps, gap)
+	}
+	float64n := rand.Float64
+	if r != nil {
+		float64n = r.Float64
+	}
+	if float64n() < p.BlunderRate {
+		weights := make([]float64, len(gaps))
+		for i, gap := range gaps {
+			if gap > 0 {
+				weights[i] = 100 / float64(100+gap)
+			}
+		}
+		if i := weighted.Index(weights, r); i != -1 {
+			return moves[i]
+		}
+	}
+	weights := make([]float64, len(gaps))
+	for i, gap := range gaps {
+		switch {
+		case gap == 0:
+			weights[i] = 1
+		case p.Temperature > 0:
+			weights[i] = math.Exp(-float64(gap) / p.Temperature)
+		}
+	}
+	return moves[weighted.Index(weights, r)]
+}
+
+// bookMove returns a book move weighted by its games or nil if the
+// position isn't in the book.
+func (p HumanPolicy) bookMove(pos *chess.Position, r *rand.Rand) *chess.Move {
+	if p.Book == nil {
+		return nil
+	}
+	stats := p.Book.Position(pos)
+	if stats == nil {
+		return nil
+	}
+	moves := []*chess.Move{}
+	weights := []float64{}
+	for _, s := range stats.Moves {
+		if s.Games() < p.BookMinGames {
+			continue
+		}
+		m, err := chess.UCINotation{}.Decode(pos, s.Move)
+		if err != nil {
+			continue
+		}
+		moves = append(moves, m)
+		weights = append(weights, float64(s.Games()))
+	}
+	if i := weighted.Index(weights, r); i != -1 {
+		return moves[i]
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package uci_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/opening"
+	"github.com/notnil/chess/uci"
+)
+
+func policyResults(t *testing.T) uci.SearchResults {
+	results := uci.SearchResults{}
+	for i, s := range []string{"e2e4", "d2d4", "g1f3", "g2g4"} {
+		m, err := chess.UCINotation{}.Decode(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cp := []int{40, 30, 20, -150}[i]
+		results.MultiPV = append(results.MultiPV, uci.Info{Multipv: i + 1, PV: []*chess.Move{m}, Score: uci.Score{CP: cp}})
+	}
+	return results
+}
+
+func choices(p uci.HumanPolicy, pos *chess.Position, results uci.SearchResults) map[string]int {
+	r := rand.New(rand.NewSource(1))
+	seen := map[string]int{}
+	for i := 0; i < 200; i++ {
+		seen[p.Choose(pos, results, r).String()]++
+	}
+	return seen
+}
+
+func TestHumanPolicy(t *testing.T) {
+	pos := chess.StartingPosition()
+	results := policyResults(t)
+
+	if seen := choices(uci.HumanPolicy{}, pos, results); seen["e2e4"] != 200 {
+		t.Fatalf("expected only the best move without temperature but got %v", seen)
+	}
+	seen := choices(uci.HumanPolicy{Temperature: 20, MaxGap: 100}, pos, results)
+	if seen["g2g4"] != 0 || seen["d2d4"] == 0 || seen["e2e4"] <= seen["d2d4"] || seen["d2d4"] <= seen["g1f3"] {
+		t.Fatalf("expected choices weighted by gap within the max gap but got %v", seen)
+	}
+	seen = choices(uci.HumanPolicy{BlunderRate: 1}, pos, results)
+	if seen["e2e4"] != 0 || seen["d2d4"] <= seen["g2g4"] {
+		t.Fatalf("expected only sub-optimal moves favoring small gaps but got %v", seen)
+	}
+	if m := (uci.HumanPolicy{}).Choose(pos, uci.SearchResults{BestMove: results.MultiPV[2].PV[0]}, rand.New(rand.NewSource(1))); m.String() != "g1f3" {
+		t.Fatalf("expected the best move without candidates but got %s", m)
+	}
+}
+
+func TestHumanPolicyBook(t *testing.T) {
+	book := opening.NewExplorer()
+	pgn := "1. e4 e5 1-0\n\n1. e4 c5 0-1\n\n1. d4 d5 1/2-1/2\n\n1. c4 e5 1-0\n"
+	if _, err := book.AddPGN(strings.NewReader(pgn)); err != nil {
+		t.Fatal(err)
+	}
+	results := policyResults(t)
+	seen := choices(uci.HumanPolicy{Book: book, BookMinGames: 1}, chess.StartingPosition(), results)
+	if seen["e2e4"] == 0 || seen["d2d4"] == 0 || seen["c2c4"] == 0 || seen["e2e4"] <= seen["d2d4"] {
+		t.Fatalf("expected book moves weighted by games but got %v", seen)
+	}
+	seen = choices(uci.HumanPolicy{Book: book, BookMinGames: 2}, chess.StartingPosition(), results)
+	if seen["e2e4"] != 200 {
+		t.Fatalf("expected only the book move with two games but got %v", seen)
+	}
+}
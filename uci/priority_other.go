@@ -0,0 +1,9 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package uci
+
+import "errors"
+
+func setPriority(pid, nice int) error {
+	return errors.New("uci: process priority isn't supported on this platform")
+}
@@ -0,0 +1,9 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package uci
+
+import "syscall"
+
+func setPriority(pid, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+}
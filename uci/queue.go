@@ -0,0 +1,272 @@
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/notnil/chess"
+)
+
+// Queue annotates games with the engine's evaluation of each move in the
+// [%eval] command of the move's comments.  Games are evaluated one move at
+// a time so long runs can report their progress, be paused and resumed,
+// and be saved with their partial results and loaded after a restart.
+type Queue struct {
+	engine   *Engine
+	cmd      CmdGo
+	progress func(Progress)
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	jobs    []*Job
+	paused  bool
+	stopped bool
+	current *Job
+}
+
+// Job is a game in the queue.  Done is the number of plies of the game
+// that have been evaluated.
+type Job struct {
+	ID   string
+	Game *chess.Game
+	Done int
+}
+
+// Complete returns true if every ply of the game has been evaluated.
+func (j Job) Complete() bool {
+	return j.Done >= len(j.Game.Moves())
+}
+
+// Progress is the progress of a queue.
+type Progress struct {
+	Jobs           int
+	CompletedJobs  int
+	Plies          int
+	CompletedPlies int
+}
+
+// Percent returns the percentage of plies that have been evaluated.  An
+// empty queue is 100 percent complete.
+func (p Progress) Percent() float64 {
+	if p.Plies == 0 {
+		return 100
+	}
+	return float64(p.CompletedPlies) / float64(p.Plies) * 100
+}
+
+// QueueProgress is an option for the NewQueue function that calls f with
+// the queue's progress after each ply is evaluated.  It is called from
+// the goroutine running the queue.
+func QueueProgress(f func(Progress)) func(q *Queue) {
+	return func(q *Queue) {
+		q.progress = f
+	}
+}
+
+// NewQueue returns an empty queue that searches each position with the
+// engine and go command.
+func NewQueue(e *Engine, cmd CmdGo, opts ...func(q *Queue)) *Queue {
+	q := &Queue{engine: e, cmd: cmd}
+	q.cond = sync.NewCond(&q.mu)
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Add adds a copy of the game to the end of the queue.  The game itself
+// isn't modified and its annotated copy is returned by Jobs.  The job is
+// skipped once the game's context is canceled and a search of its
+// position that is running is sent the stop command.
+func (q *Queue) Add(id string, g *chess.Game) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, &Job{ID: id, Game: g.Clone()})
+}
+
+// Jobs returns a copy of each job in the queue with its annotated game.
+func (q *Queue) Jobs() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := []Job{}
+	for _, j := range q.jobs {
+		jobs = append(jobs, Job{ID: j.ID, Game: j.Game.Clone(), Done: j.Done})
+	}
+	return jobs
+}
+
+// Progress returns the progress of the queue.
+func (q *Queue) Progress() Progress {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.progressLocked()
+}
+
+func (q *Queue) progressLocked() Progress {
+	p := Progress{Jobs: len(q.jobs)}
+	for _, j := range q.jobs {
+		p.Plies += len(j.Game.Moves())
+		p.CompletedPlies += j.Done
+		if j.Complete() {
+			p.CompletedJobs++
+		}
+	}
+	return p
+}
+
+// Pause pauses the queue after the ply being evaluated.  Run waits until
+// the queue is resumed or stopped.
+func (q *Queue) Pause() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.paused = true
+}
+
+// Resume resumes a paused queue.
+func (q *Queue) Resume() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.paused = false
+	q.cond.Broadcast()
+}
+
+// Stop makes Run return after the ply being evaluated.  The queue can be
+// saved and run again later.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	q.cond.Broadcast()
+}
+
+// Run evaluates the plies of the queue's games in order until every game
+// is complete, the queue is stopped or an error occurs.  Jobs added while
+// the queue is running are evaluated by the same run.
+func (q *Queue) Run() error {
+	q.mu.Lock()
+	q.stopped = false
+	q.mu.Unlock()
+	for {
+		j, pos, ok := q.next()
+		if !ok {
+			return nil
+		}
+		eval, ok, err := q.evaluate(j, pos)
+		if err != nil && j.Game.Context().Err() != nil {
+			// the job was canceled during the search
+			continue
+		} else if err != nil {
+			return err
+		}
+		q.mu.Lock()
+		if ok {
+			if err := j.Game.SetEval(j.Done+1, eval); err != nil {
+				q.mu.Unlock()
+				return err
+			}
+		}
+		j.Done++
+		p := q.progressLocked()
+		q.mu.Unlock()
+		if q.progress != nil {
+			q.progress(p)
+		}
+	}
+}
+
+// next waits while the queue is paused and returns the first incomplete
+// job whose context isn't canceled and the position after its next ply.
+// False is returned if the queue is stopped or complete.
+func (q *Queue) next() (*Job, *chess.Position, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.paused && !q.stopped {
+		q.cond.Wait()
+	}
+	if q.stopped {
+		return nil, nil, false
+	}
+	for _, j := range q.jobs {
+		if !j.Complete() && j.Game.Context().Err() == nil {
+			return j, j.Game.Positions()[j.Done+1], true
+		}
+	}
+	return nil, nil, false
+}
+
+// evaluate searches the position and returns its evaluation from White's
+// perspective.  False is returned for positions where the game is over.
+func (q *Queue) evaluate(j *Job, pos *chess.Position) (chess.Eval, bool, error) {
+	if j != q.current {
+		if err := q.engine.Run(CmdUCINewGame, CmdIsReady); err != nil {
+			return chess.Eval{}, false, err
+		}
+		q.current = j
+	}
+	if pos.Status() != chess.NoMethod {
+		return chess.Eval{}, false, nil
+	}
+	results, err := q.engine.searchMoves(j.Game.Context(), pos, q.cmd)
+	if err != nil {
+		return chess.Eval{}, false, err
+	}
+	best := results.bestLine()
+	eval := chess.Eval{Centipawns: best.Score.CP, Mate: best.Score.Mate, Depth: best.Depth}
+	if pos.Turn() == chess.Black {
+		eval.Centipawns, eval.Mate = -eval.Centipawns, -eval.Mate
+	}
+	return eval, true, nil
+}
+
+// queueJobEscape begins the escape line written before each game by Save.
+const queueJobEscape = "job "
+
+// Save writes the queue's games as PGN with the evaluations so far.  Each
+// game is preceded by a PGN escape line with the job's ID and progress,
+// which most programs ignore, so the file can be loaded by Load.
+func (q *Queue) Save(w io.Writer) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	bw := bufio.NewWriter(w)
+	for _, j := range q.jobs {
+		fmt.Fprintf(bw, "%%%s%d %s\n%s\n\n", queueJobEscape, j.Done, j.ID, j.Game)
+	}
+	return bw.Flush()
+}
+
+// Load adds the games written by Save to the end of the queue with their
+// evaluations and progress.
+func (q *Queue) Load(r io.Reader) error {
+	scanner := chess.NewScanner(r, chess.PreservePGN)
+	jobs := []*Job{}
+	for scanner.Scan() {
+		g := scanner.Next()
+		j := &Job{Game: g}
+		for _, line := range g.EscapeLines() {
+			if !strings.HasPrefix(line, queueJobEscape) {
+				continue
+			}
+			parts := strings.SplitN(strings.TrimPrefix(line, queueJobEscape), " ", 2)
+			done, err := strconv.Atoi(parts[0])
+			if err != nil || done < 0 || done > len(g.Moves()) {
+				return fmt.Errorf("uci: invalid queue job %q", line)
+			}
+			j.Done = done
+			if len(parts) == 2 {
+				j.ID = parts[1]
+			}
+		}
+		jobs = append(jobs, j)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, jobs...)
+	return nil
+}
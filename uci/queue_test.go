@@ -0,0 +1,101 @@
+package uci_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/uci"
+)
+
+const fakeEvalEngine = `#!/bin/sh
+while read line; do
+	case "$line" in
+	isready)
+		echo readyok
+		;;
+	go*)
+		echo "info depth 8 multipv 1 score cp 30 pv e2e4"
+		echo "info depth 8 multipv 2 score cp -40 pv a2a3"
+		echo "bestmove e2e4"
+		;;
+	quit)
+		exit 0
+		;;
+	esac
+done
+`
+
+func TestQueue(t *testing.T) {
+	path, cleanup := writeFakeEngine(t, fakeEvalEngine)
+	defer cleanup()
+	eng, err := uci.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	opt, err := chess.PGN(strings.NewReader("1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7# 1-0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := chess.NewGame(opt)
+
+	var q *uci.Queue
+	percents := []float64{}
+	q = uci.NewQueue(eng, uci.CmdGo{Depth: 8}, uci.QueueProgress(func(p uci.Progress) {
+		percents = append(percents, p.Percent())
+		if p.CompletedPlies == 3 {
+			q.Stop()
+		}
+	}))
+	q.Add("miniature", g)
+	if err := q.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if p := q.Progress(); p.CompletedPlies != 3 || p.Plies != 7 || p.CompletedJobs != 0 {
+		t.Fatalf("expected the queue to stop after 3 of 7 plies but got %+v", p)
+	}
+	if len(percents) != 3 || percents[2] != float64(3)/7*100 {
+		t.Fatalf("expected progress after each ply but got %v", percents)
+	}
+	if _, ok := g.Moves()[0].Eval(); ok {
+		t.Fatal("expected the added game not to be modified")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := q.Save(buf); err != nil {
+		t.Fatal(err)
+	}
+	resumed := uci.NewQueue(eng, uci.CmdGo{Depth: 8})
+	if err := resumed.Load(buf); err != nil {
+		t.Fatal(err)
+	}
+	resumed.Pause()
+	done := make(chan error)
+	go func() { done <- resumed.Run() }()
+	if p := resumed.Progress(); p.CompletedPlies != 3 {
+		t.Fatalf("expected the paused queue to keep its progress but got %+v", p)
+	}
+	resumed.Resume()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	jobs := resumed.Jobs()
+	if len(jobs) != 1 || jobs[0].ID != "miniature" || !jobs[0].Complete() {
+		t.Fatalf("expected the loaded job to complete but got %+v", jobs)
+	}
+	moves := jobs[0].Game.Moves()
+	for i, m := range moves[:len(moves)-1] {
+		want := chess.Eval{Centipawns: 30, Depth: 8}
+		if i%2 == 0 {
+			want.Centipawns = -30
+		}
+		if e, ok := m.Eval(); !ok || e != want {
+			t.Fatalf("expected ply %d to have eval %v but got %v", i+1, want, e)
+		}
+	}
+	if _, ok := moves[len(moves)-1].Eval(); ok {
+		t.Fatal("expected no eval after checkmate")
+	}
+}
@@ -0,0 +1,191 @@
+package uci
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/notnil/chess"
+)
+
+// Screener compares the moves of games to an engine's candidates to
+// screen players for engine assistance.
+type Screener struct {
+	Engine *Engine
+	// Go is the search run on each position.
+	Go CmdGo
+	// Candidates is the number of engine moves played moves are ranked
+	// among.  It sets the engine's MultiPV option.  Zero ranks among one.
+	Candidates int
+	// SkipPlies is the number of plies at the start of each game, usually
+	// opening theory, that aren't screened.
+	SkipPlies int
+}
+
+// PlayerReport is the screening of a player's moves.  Moves with only one
+// legal alternative and null moves aren't screened.
+type PlayerReport struct {
+	// Moves is the number of moves screened.
+	Moves int
+	// Matches is the number of moves that were the engine's first choice.
+	Matches int
+	// Ranked is the number of moves that were among the candidates and
+	// RankSum the sum of their ranks where 1 is the first choice.
+	Ranked  int
+	RankSum int
+	// Losses are the centipawn losses of the moves as with MoveEval.Loss.
+	Losses []int
+}
+
+// MatchRate returns the fraction of the moves that were the engine's
+// first choice.
+func (r *PlayerReport) MatchRate() float64 {
+	if r.Moves == 0 {
+		return 0
+	}
+	return float64(r.Matches) / float64(r.Moves)
+}
+
+// AverageRank returns the average rank of the moves that were among the
+// candidates.
+func (r *PlayerReport) AverageRank() float64 {
+	if r.Ranked == 0 {
+		return 0
+	}
+	return float64(r.RankSum) / float64(r.Ranked)
+}
+
+// AverageLoss returns the average centipawn loss of the moves.
+func (r *PlayerReport) AverageLoss() float64 {
+	if len(r.Losses) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, loss := range r.Losses {
+		sum += loss
+	}
+	return float64(sum) / float64(len(r.Losses))
+}
+
+// LossDistribution returns the number of moves with a loss of at most
+// each of the ascending bounds and not within an earlier bound.  The last
+// count is of the moves with a greater loss.  For example bounds of 0, 50
+// and 100 count perfect moves, inaccuracies, mistakes and the rest.
+func (r *PlayerReport) LossDistribution(bounds ...int) []int {
+	counts := make([]int, len(bounds)+1)
+	for _, loss := range r.Losses {
+		i := 0
+		for i < len(bounds) && loss > bounds[i] {
+			i++
+		}
+		counts[i]++
+	}
+	return counts
+}
+
+func (r *PlayerReport) add(other *PlayerReport) {
+	r.Moves += other.Moves
+	r.Matches += other.Matches
+	r.Ranked += other.Ranked
+	r.RankSum += other.RankSum
+	r.Losses = append(r.Losses, other.Losses...)
+}
+
+// GameReport is the screening of each player of a game.
+type GameReport struct {
+	White PlayerReport
+	Black PlayerReport
+}
+
+// Screen screens the moves of the game.  Screening stops with the
+// context's error if the game's context is canceled, including a search
+// already running, which is sent the stop command.
+func (s Screener) Screen(g *chess.Game) (*GameReport, error) {
+	candidates := s.Candidates
+	if candidates < 1 {
+		candidates = 1
+	}
+	if err := s.Engine.Run(CmdSetOption{Name: "MultiPV", Value: fmt.Sprint(candidates)}, CmdIsReady); err != nil {
+		return nil, err
+	}
+	report := &GameReport{}
+	positions := g.Positions()
+	for i, m := range g.Moves() {
+		pos := positions[i]
+		if i < s.SkipPlies || m.IsNull() || len(pos.ValidMoves()) < 2 {
+			continue
+		}
+		player := &report.White
+		if pos.Turn() == chess.Black {
+			player = &report.Black
+		}
+		if err := s.screenMove(g.Context(), player, pos, m); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (s Screener) screenMove(ctx context.Context, player *PlayerReport, pos *chess.Position, m *chess.Move) error {
+	results, err := s.Engine.searchMoves(ctx, pos, s.Go)
+	if err != nil {
+		return err
+	}
+	lines := results.MultiPV
+	if len(lines) == 0 && results.BestMove != nil {
+		lines = []Info{{PV: []*chess.Move{results.BestMove}, Score: results.Info.Score}}
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("uci: engine returned no move for position %s", pos)
+	}
+	ev := MoveEval{Best: lines[0].PV[0], BestScore: lines[0].Score, Played: m}
+	rank := 0
+	for j, line := range lines {
+		if line.PV[0].String() == m.String() {
+			rank = j + 1
+			ev.PlayedScore = line.Score
+			break
+		}
+	}
+	if rank == 0 {
+		results, err := s.Engine.searchMoves(ctx, pos, s.Go, m)
+		if err != nil {
+			return err
+		}
+		ev.PlayedScore = results.Info.Score
+	} else {
+		player.Ranked++
+		player.RankSum += rank
+	}
+	if rank == 1 {
+		player.Matches++
+	}
+	player.Moves++
+	player.Losses = append(player.Losses, ev.Loss())
+	return nil
+}
+
+// ScreenGames screens the moves of the games and returns the combined
+// reports of each player by the name in the White and Black tag pairs.
+func (s Screener) ScreenGames(games []*chess.Game) (map[string]*PlayerReport, error) {
+	players := map[string]*PlayerReport{}
+	for _, g := range games {
+		report, err := s.Screen(g)
+		if err != nil {
+			return nil, err
+		}
+		for _, side := range []struct {
+			tag    string
+			report *PlayerReport
+		}{{"White", &report.White}, {"Black", &report.Black}} {
+			name := "?"
+			if tag := g.GetTagPair(side.tag); tag != nil {
+				name = tag.Value
+			}
+			if players[name] == nil {
+				players[name] = &PlayerReport{}
+			}
+			players[name].add(side.report)
+		}
+	}
+	return players, nil
+}
@@ -0,0 +1,68 @@
+package uci_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/uci"
+)
+
+const fakeMultiPVEngine = `#!/bin/sh
+while read line; do
+	case "$line" in
+	isready)
+		echo readyok
+		;;
+	*searchmoves*)
+		m=${line##* }
+		echo "info depth 5 score cp -50 pv $m"
+		echo "bestmove $m"
+		;;
+	go*)
+		echo "info depth 5 multipv 1 score cp 30 pv e2e4"
+		echo "info depth 5 multipv 2 score cp 20 pv d2d4"
+		echo "bestmove e2e4"
+		;;
+	quit)
+		exit 0
+		;;
+	esac
+done
+`
+
+func TestScreener(t *testing.T) {
+	path, cleanup := writeFakeEngine(t, fakeMultiPVEngine)
+	defer cleanup()
+	eng, err := uci.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	opt, err := chess.PGN(strings.NewReader("[White \"A\"]\n[Black \"B\"]\n\n1. e4 d5 2. d4 *"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := chess.NewGame(opt)
+	s := uci.Screener{Engine: eng, Go: uci.CmdGo{Depth: 5}, Candidates: 2}
+	players, err := s.ScreenGames([]*chess.Game{g, g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	white, black := players["A"], players["B"]
+	if white == nil || black == nil {
+		t.Fatalf("expected reports for both players but got %v", players)
+	}
+	if white.Moves != 4 || white.MatchRate() != 0.5 || white.AverageRank() != 1.5 || white.AverageLoss() != 5 {
+		t.Fatalf("expected half the moves to match with rank 1.5 and loss 5 but got %+v", white)
+	}
+	if black.Moves != 2 || black.Matches != 0 || black.Ranked != 0 || black.AverageLoss() != 80 {
+		t.Fatalf("expected no matches and a loss of 80 but got %+v", black)
+	}
+	if d := white.LossDistribution(0, 50, 100); d[0] != 2 || d[1] != 2 || d[2] != 0 || d[3] != 0 {
+		t.Fatalf("expected two perfect moves and two inaccuracies but got %v", d)
+	}
+	if d := black.LossDistribution(0, 50, 100); d[2] != 2 {
+		t.Fatalf("expected two mistakes but got %v", d)
+	}
+}
@@ -0,0 +1,122 @@
+package uci
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/notnil/chess"
+)
+
+// SearchMoves searches the position with the go command restricted to the
+// moves using searchmoves and returns the results.  No moves searches every
+// move.
+func (e *Engine) SearchMoves(pos *chess.Position, cmd CmdGo, moves ...*chess.Move) (SearchResults, error) {
+	return e.searchMoves(context.Background(), pos, cmd, moves...)
+}
+
+// searchMoves is like SearchMoves but sends the stop command if the
+// context is canceled during the search, which ends it with the best move
+// found so far, and then returns the context's error.
+func (e *Engine) searchMoves(ctx context.Context, pos *chess.Position, cmd CmdGo, moves ...*chess.Move) (SearchResults, error) {
+	if err := ctx.Err(); err != nil {
+		return SearchResults{}, err
+	}
+	cmd.SearchMoves = moves
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+			e.Run(CmdStop)
+		case <-done:
+		}
+	}()
+	err := e.Run(CmdPosition{Position: pos}, cmd)
+	close(done)
+	// a stop sent after the search ended must not reach the next one
+	wg.Wait()
+	if err != nil {
+		return SearchResults{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return SearchResults{}, err
+	}
+	return e.SearchResults(), nil
+}
+
+// MoveEval compares a move played in a position to the engine's choice.
+// Scores are from the point of view of the side to move.
+type MoveEval struct {
+	Best        *chess.Move
+	BestScore   Score
+	Played      *chess.Move
+	PlayedScore Score
+}
+
+// maxLossCP caps the centipawn value of scores so that mates and
+// overwhelming advantages don't dominate averages of the loss.
+const maxLossCP = 1000
+
+// Loss returns the centipawns lost by playing the move instead of the best
+// move.  Scores are capped at 1000 centipawns with mates at the cap as is
+// usual for average centipawn loss.
+func (ev MoveEval) Loss() int {
+	loss := lossCP(ev.BestScore) - lossCP(ev.PlayedScore)
+	if loss < 0 {
+		return 0
+	}
+	return loss
+}
+
+func lossCP(s Score) int {
+	switch {
+	case s.Mate > 0:
+		return maxLossCP
+	case s.Mate < 0:
+		return -maxLossCP
+	case s.CP > maxLossCP:
+		return maxLossCP
+	case s.CP < -maxLossCP:
+		return -maxLossCP
+	}
+	return s.CP
+}
+
+// EvaluateMove compares the move to the engine's choice in the position.
+// The position is searched with the go command and, if the engine prefers
+// another move, searched again restricted to the move with searchmoves so
+// only the played move is evaluated.
+func (e *Engine) EvaluateMove(pos *chess.Position, move *chess.Move, cmd CmdGo) (MoveEval, error) {
+	results, err := e.SearchMoves(pos, cmd)
+	if err != nil {
+		return MoveEval{}, err
+	}
+	if results.BestMove == nil {
+		return MoveEval{}, fmt.Errorf("uci: engine returned no move for position %s", pos)
+	}
+	best := results.bestLine()
+	ev := MoveEval{Best: results.BestMove, BestScore: best.Score, Played: move, PlayedScore: best.Score}
+	if results.BestMove.String() == move.String() {
+		return ev, nil
+	}
+	results, err = e.SearchMoves(pos, cmd, move)
+	if err != nil {
+		return MoveEval{}, err
+	}
+	ev.PlayedScore = results.bestLine().Score
+	return ev, nil
+}
+
+// bestLine returns the info of the best line of the search.  The most
+// recent info is the last line printed, which is the worst line when the
+// MultiPV option is above one, so the first MultiPV line is used if there
+// is one.
+func (r SearchResults) bestLine() Info {
+	if len(r.MultiPV) > 0 {
+		return r.MultiPV[0]
+	}
+	return r.Info
+}
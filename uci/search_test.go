@@ -0,0 +1,76 @@
+package uci_test
+
+import (
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/uci"
+)
+
+func TestCmdGoSearchMoves(t *testing.T) {
+	e4, err := chess.UCINotation{}.Decode(nil, "e2e4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d4, err := chess.UCINotation{}.Decode(nil, "d2d4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := uci.CmdGo{Depth: 10, Mate: 3, SearchMoves: []*chess.Move{e4, d4}}
+	if s := cmd.String(); s != "go depth 10 mate 3 searchmoves e2e4 d2d4" {
+		t.Fatalf("expected go depth 10 mate 3 searchmoves e2e4 d2d4 but got %q", s)
+	}
+}
+
+func TestCmdGoMate(t *testing.T) {
+	// mate is written with its own value rather than the node limit
+	cmd := uci.CmdGo{Nodes: 5000, Mate: 2}
+	if s := cmd.String(); s != "go nodes 5000 mate 2" {
+		t.Fatalf("expected go nodes 5000 mate 2 but got %q", s)
+	}
+	if s := (uci.CmdGo{Mate: 4}).String(); s != "go mate 4" {
+		t.Fatalf("expected go mate 4 but got %q", s)
+	}
+}
+
+func TestMoveEvalLoss(t *testing.T) {
+	tests := []struct {
+		best   uci.Score
+		played uci.Score
+		loss   int
+	}{
+		{uci.Score{CP: 50}, uci.Score{CP: -20}, 70},
+		{uci.Score{CP: 50}, uci.Score{CP: 60}, 0},
+		{uci.Score{Mate: 2}, uci.Score{CP: 300}, 700},
+		{uci.Score{CP: 2500}, uci.Score{Mate: -4}, 2000},
+	}
+	for _, test := range tests {
+		ev := uci.MoveEval{BestScore: test.best, PlayedScore: test.played}
+		if loss := ev.Loss(); loss != test.loss {
+			t.Fatalf("expected a loss of %d from %+v to %+v but got %d", test.loss, test.best, test.played, loss)
+		}
+	}
+}
+
+func TestEvaluateMoveMultiPV(t *testing.T) {
+	path, cleanup := writeFakeEngine(t, fakeMultiPVEngine)
+	defer cleanup()
+	eng, err := uci.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	pos := chess.StartingPosition()
+	d4, err := chess.UCINotation{}.Decode(pos, "d2d4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the engine prints the worse d2d4 line last
+	ev, err := eng.EvaluateMove(pos, d4, uci.CmdGo{Depth: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.BestScore.CP != 30 || ev.PlayedScore.CP != -50 || ev.Loss() != 80 {
+		t.Fatalf("expected the best line's score 30 and a loss of 80 but got %+v", ev)
+	}
+}
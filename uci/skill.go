@@ -0,0 +1,146 @@
+package uci
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+// SkillPreset is a profile that limits the strength of an engine so bots can
+// offer adjustable difficulty without configuring each engine's options.
+// Engines ignore options they don't support so a preset can combine several
+// ways of weakening play.
+type SkillPreset struct {
+	// Name describes the preset.
+	Name string
+	// Elo is the target rating set with the UCI_LimitStrength and UCI_Elo
+	// options.  Zero leaves strength unlimited.
+	Elo int
+	// SkillLevel is the value of Stockfish's Skill Level option from 0 to 20.
+	SkillLevel int
+	// Nodes caps the nodes searched per move if positive.
+	Nodes int
+	// Depth caps the search depth in plies if positive.
+	Depth int
+	// MoveTime is the time searched per move if positive.
+	MoveTime time.Duration
+	// Candidates is the number of lines searched with the MultiPV option.
+	// When above one the move is chosen randomly among candidates scoring
+	// within Margin of the best.
+	Candidates int
+	// Margin is the largest gap in centipawns from the best candidate of a
+	// move that can be chosen.
+	Margin int
+}
+
+var (
+	// SkillBeginner plays quick, often inaccurate moves.
+	SkillBeginner = SkillPreset{Name: "Beginner", SkillLevel: 0, Nodes: 1000, MoveTime: 50 * time.Millisecond, Candidates: 5, Margin: 300}
+	// SkillCasual plays reasonable moves with regular mistakes.
+	SkillCasual = SkillPreset{Name: "Casual", SkillLevel: 3, Nodes: 10000, MoveTime: 100 * time.Millisecond, Candidates: 4, Margin: 150}
+	// SkillIntermediate targets a rating of 1500.
+	SkillIntermediate = SkillPreset{Name: "Intermediate", Elo: 1500, SkillLevel: 8, MoveTime: 100 * time.Millisecond, Candidates: 3, Margin: 60}
+	// SkillAdvanced targets a rating of 1900.
+	SkillAdvanced = SkillPreset{Name: "Advanced", Elo: 1900, SkillLevel: 13, MoveTime: 200 * time.Millisecond, Candidates: 2, Margin: 30}
+	// SkillExpert targets a rating of 2400.
+	SkillExpert = SkillPreset{Name: "Expert", Elo: 2400, SkillLevel: 18, MoveTime: 500 * time.Millisecond, Candidates: 1}
+	// SkillMaximum plays at full strength.
+	SkillMaximum = SkillPreset{Name: "Maximum", SkillLevel: 20, MoveTime: time.Second, Candidates: 1}
+
+	// SkillPresets are the presets from weakest to strongest.
+	SkillPresets = []SkillPreset{SkillBeginner, SkillCasual, SkillIntermediate, SkillAdvanced, SkillExpert, SkillMaximum}
+)
+
+// Cmds returns the setoption commands that configure an engine for the
+// preset.
+func (p SkillPreset) Cmds() []Cmd {
+	candidates := p.Candidates
+	if candidates < 1 {
+		candidates = 1
+	}
+	cmds := []Cmd{
+		CmdSetOption{Name: "Skill Level", Value: fmt.Sprint(p.SkillLevel)},
+		CmdSetOption{Name: "UCI_LimitStrength", Value: fmt.Sprint(p.Elo > 0)},
+		CmdSetOption{Name: "MultiPV", Value: fmt.Sprint(candidates)},
+	}
+	if p.Elo > 0 {
+		cmds = append(cmds, CmdSetOption{Name: "UCI_Elo", Value: fmt.Sprint(p.Elo)})
+	}
+	return cmds
+}
+
+// Go returns the go command limiting the search to the preset's nodes,
+// depth and move time.
+func (p SkillPreset) Go() CmdGo {
+	return CmdGo{Nodes: p.Nodes, Depth: p.Depth, MoveTime: p.MoveTime}
+}
+
+// ChooseMove returns a move from the search results.  It picks randomly
+// among the MultiPV lines scoring within Margin of the best and falls back
+// to the best move if there are no lines.  If r is nil the math/rand
+// default source is used.
+func (p SkillPreset) ChooseMove(results SearchResults, r *rand.Rand) *chess.Move {
+	if len(results.MultiPV) == 0 {
+		return results.BestMove
+	}
+	best := bestScore(results.MultiPV)
+	candidates := []*chess.Move{}
+	for _, info := range results.MultiPV {
+		if best-scoreValue(info.Score) <= p.Margin {
+			candidates = append(candidates, info.PV[0])
+		}
+	}
+	intn := rand.Intn
+	if r != nil {
+		intn = r.Intn
+	}
+	return candidates[intn(len(candidates))]
+}
+
+// SetSkill configures the engine for the preset.
+func (e *Engine) SetSkill(p SkillPreset) error {
+	return e.Run(append(p.Cmds(), CmdIsReady)...)
+}
+
+// SkillMove searches the position with the preset's limits and returns the
+// move chosen by the preset.  The engine should be configured with SetSkill
+// first.
+func (e *Engine) SkillMove(p SkillPreset, pos *chess.Position, r *rand.Rand) (*chess.Move, error) {
+	if err := e.Run(CmdPosition{Position: pos}, p.Go()); err != nil {
+		return nil, err
+	}
+	move := p.ChooseMove(e.SearchResults(), r)
+	if move == nil {
+		return nil, fmt.Errorf("uci: engine returned no move for position %s", pos)
+	}
+	return move, nil
+}
+
+// mateScore is the centipawn value of a mate in zero used to order mates
+// above any centipawn score.
+const mateScore = 100000
+
+// scoreValue returns the score in centipawns with mates ranked by distance.
+func scoreValue(s Score) int {
+	switch {
+	case s.Mate > 0:
+		return mateScore - s.Mate
+	case s.Mate < 0:
+		return -mateScore - s.Mate
+	}
+	return s.CP
+}
+
+// bestScore returns the highest score value of the lines, which mustn't be
+// empty.
+func bestScore(lines []Info) int {
+	best := scoreValue(lines[0].Score)
+	for _, info := range lines[1:] {
+		if v := scoreValue(info.Score); v > best {
+			best = v
+		}
+	}
+	return best
+}
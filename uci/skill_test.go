@@ -0,0 +1,64 @@
+package uci_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/uci"
+)
+
+func TestSkillPresetCmds(t *testing.T) {
+	cmds := uci.SkillPreset{Elo: 1600, SkillLevel: 10, Candidates: 3}.Cmds()
+	expected := []string{
+		"setoption name Skill Level value 10",
+		"setoption name UCI_LimitStrength value true",
+		"setoption name MultiPV value 3",
+		"setoption name UCI_Elo value 1600",
+	}
+	if len(cmds) != len(expected) {
+		t.Fatalf("expected %d commands but got %d", len(expected), len(cmds))
+	}
+	for i, cmd := range cmds {
+		if cmd.String() != expected[i] {
+			t.Fatalf("expected %q but got %q", expected[i], cmd.String())
+		}
+	}
+	if s := uci.SkillBeginner.Go().String(); s != "go nodes 1000 movetime 50" {
+		t.Fatalf("expected go nodes 1000 movetime 50 but got %q", s)
+	}
+}
+
+func TestSkillPresetChooseMove(t *testing.T) {
+	line := func(s string, cp, mate int) uci.Info {
+		m, err := chess.UCINotation{}.Decode(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return uci.Info{PV: []*chess.Move{m}, Score: uci.Score{CP: cp, Mate: mate}}
+	}
+	results := uci.SearchResults{MultiPV: []uci.Info{
+		line("e2e4", 40, 0),
+		line("d2d4", 30, 0),
+		line("g1f3", -200, 0),
+	}}
+	r := rand.New(rand.NewSource(1))
+	p := uci.SkillPreset{Candidates: 3, Margin: 20}
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		seen[p.ChooseMove(results, r).String()] = true
+	}
+	if len(seen) != 2 || !seen["e2e4"] || !seen["d2d4"] {
+		t.Fatalf("expected e2e4 and d2d4 to be chosen but got %v", seen)
+	}
+
+	results.MultiPV = append(results.MultiPV, line("d1h5", 0, 3))
+	for i := 0; i < 10; i++ {
+		if m := p.ChooseMove(results, r).String(); m != "d1h5" {
+			t.Fatalf("expected the mate d1h5 but got %s", m)
+		}
+	}
+	if m := p.ChooseMove(results, nil).String(); m != "d1h5" {
+		t.Fatalf("expected the default source to choose d1h5 but got %s", m)
+	}
+}
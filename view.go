@@ -0,0 +1,108 @@
+package chess
+
+import "time"
+
+// GameView is a read-only view of a game at the time it was created that
+// can be shared with other goroutines, such as those broadcasting a game
+// to spectators, while the game continues.  It has no methods that modify
+// it and shares no data with the game, so reading it never races with the
+// game's moves.  GameView is safe for concurrent use.
+type GameView struct {
+	tagPairs  []*TagPair
+	moves     []*Move
+	positions []*Position
+	outcome   Outcome
+	method    Method
+	// lastIrreversible and ignoreAutomaticDraws are kept for Game
+	lastIrreversible     int
+	ignoreAutomaticDraws bool
+}
+
+// View returns a read-only view of the game as it is now.  View must be
+// called by the goroutine modifying the game, or while holding the lock
+// guarding it, and the view can then be handed to any goroutine.
+func (g *Game) View() *GameView {
+	v := &GameView{
+		outcome:              g.outcome,
+		method:               g.method,
+		lastIrreversible:     g.lastIrreversible,
+		ignoreAutomaticDraws: g.ignoreAutomaticDraws,
+	}
+	for _, tp := range g.tagPairs {
+		v.tagPairs = append(v.tagPairs, &TagPair{Key: tp.Key, Value: tp.Value})
+	}
+	// moves are copied with their annotations, which hold the clocks
+	v.moves = filterAnnotations(g.moves, func(string) bool { return true })
+	for _, pos := range g.positions {
+		v.positions = append(v.positions, pos.copy())
+	}
+	return v
+}
+
+// Position returns a copy of the current position.
+func (v *GameView) Position() *Position {
+	return v.positions[len(v.positions)-1].copy()
+}
+
+// Positions returns copies of the positions of the game starting with the
+// starting position.
+func (v *GameView) Positions() []*Position {
+	positions := make([]*Position, len(v.positions))
+	for i, pos := range v.positions {
+		positions[i] = pos.copy()
+	}
+	return positions
+}
+
+// Moves returns the moves of the game with their annotations.
+func (v *GameView) Moves() []*Move {
+	return append([]*Move(nil), v.moves...)
+}
+
+// TagPairs returns copies of the game's tag pairs.
+func (v *GameView) TagPairs() []*TagPair {
+	tagPairs := []*TagPair{}
+	for _, tp := range v.tagPairs {
+		tagPairs = append(tagPairs, &TagPair{Key: tp.Key, Value: tp.Value})
+	}
+	return tagPairs
+}
+
+// Outcome returns the game's outcome.
+func (v *GameView) Outcome() Outcome {
+	return v.outcome
+}
+
+// Method returns the method by which the game's outcome was decided.
+func (v *GameView) Method() Method {
+	return v.method
+}
+
+// Clock returns the remaining time on the color's clock from the [%clk]
+// command of its most recent move.  False is returned if the color hasn't
+// moved or its move has no clock.
+func (v *GameView) Clock(color Color) (time.Duration, bool) {
+	for i := len(v.moves) - 1; i >= 0; i-- {
+		if v.positions[i].turn == color {
+			return v.moves[i].Clock()
+		}
+	}
+	return 0, false
+}
+
+// Game returns a new game with the view's tag pairs, moves and outcome
+// that the caller may modify, such as to encode it as PGN.
+func (v *GameView) Game() *Game {
+	positions := v.Positions()
+	return &Game{
+		notation:             AlgebraicNotation{},
+		tagPairs:             v.TagPairs(),
+		moves:                filterAnnotations(v.moves, func(string) bool { return true }),
+		positions:            positions,
+		pos:                  positions[len(positions)-1],
+		outcome:              v.outcome,
+		method:               v.method,
+		lastIrreversible:     v.lastIrreversible,
+		ignoreAutomaticDraws: v.ignoreAutomaticDraws,
+	}
+}
@@ -0,0 +1,81 @@
+package chess
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGameView(t *testing.T) {
+	g := NewGame()
+	g.AddTagPair("White", "A")
+	for _, s := range []string{"e4", "e5", "Nf3"} {
+		if err := g.MoveStr(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.SetClock(1, 3*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetClock(3, 175*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	v := g.View()
+	if err := g.MoveStr("Nc6"); err != nil {
+		t.Fatal(err)
+	}
+	g.AddTagPair("White", "B")
+	if len(v.Moves()) != 3 || len(v.Positions()) != 4 {
+		t.Fatalf("expected the view to have 3 moves but got %d", len(v.Moves()))
+	}
+	if v.Position().Turn() != Black {
+		t.Fatalf("expected black to move in the view but got %s", v.Position().Turn())
+	}
+	if d, ok := v.Clock(White); !ok || d != 175*time.Second {
+		t.Fatalf("expected white's clock 2:55 but got %s", d)
+	}
+	if _, ok := v.Clock(Black); ok {
+		t.Fatal("expected black's move to have no clock")
+	}
+	if tp := v.TagPairs(); len(tp) != 1 || tp[0].Value != "A" {
+		t.Fatalf("expected the view's tag pairs to be unchanged but got %v", tp)
+	}
+	cp := v.Game()
+	if err := cp.MoveStr("d6"); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Moves()) != 3 {
+		t.Fatal("expected modifying the view's game not to change the view")
+	}
+}
+
+func TestGameViewConcurrent(t *testing.T) {
+	g := NewGame()
+	views := make(chan *GameView)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range views {
+				for _, pos := range v.Positions() {
+					pos.ValidMoves()
+				}
+				v.Position().Status()
+				v.Clock(White)
+				_ = v.Game().String()
+			}
+		}()
+	}
+	for i := 0; i < 20 && g.Outcome() == NoOutcome; i++ {
+		if err := g.Move(g.ValidMoves()[0]); err != nil {
+			t.Fatal(err)
+		}
+		v := g.View()
+		for j := 0; j < 4; j++ {
+			views <- v
+		}
+	}
+	close(views)
+	wg.Wait()
+}
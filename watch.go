@@ -0,0 +1,142 @@
+package chess
+
+import (
+	"context"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// PGNUpdate is an incremental update to a game in a watched PGN file.
+type PGNUpdate struct {
+	// Index is the game's index in the file.
+	Index int
+	// Game is the game as currently written.
+	Game *Game
+	// New is true the first time the game is seen.
+	New bool
+	// Moves are the moves appended since the previous update.
+	Moves []*Move
+}
+
+// PGNWatcher tails a PGN file that is being appended to, such as a live
+// tournament broadcast, and reports games that are added or updated.
+// Games that are only partially written (ex. a half written move) are
+// reported up to their last complete move.  PGNWatcher is safe for
+// concurrent use.
+type PGNWatcher struct {
+	path  string
+	games []*Game
+	err   error
+	mu    *sync.Mutex
+}
+
+// NewPGNWatcher returns a watcher for the PGN file at the given path.  The
+// file doesn't have to exist until it is polled.
+func NewPGNWatcher(path string) *PGNWatcher {
+	return &PGNWatcher{path: path, mu: &sync.Mutex{}}
+}
+
+// Poll reads the file once and returns updates for games that are new
+// or have changed since the previous poll.  If the file has been
+// truncated or rewritten so that a game no longer extends its previous
+// version, the game is reported as new.
+func (w *PGNWatcher) Poll() ([]*PGNUpdate, error) {
+	b, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	updates := []*PGNUpdate{}
+	games := []*Game{}
+	for i, txt := range splitPGNGames(string(b)) {
+		g, err := decodePartialPGN(txt)
+		if err != nil {
+			// the tag section may be partially written
+			break
+		}
+		games = append(games, g)
+		var prev *Game
+		if i < len(w.games) {
+			prev = w.games[i]
+		}
+		if u := diffGames(i, prev, g); u != nil {
+			updates = append(updates, u)
+		}
+	}
+	w.games = games
+	return updates, nil
+}
+
+// DefaultWatchInterval is the polling interval Watch uses when the given
+// interval isn't positive.
+const DefaultWatchInterval = time.Second
+
+// Watch polls the file at the given interval until the context is
+// canceled and sends updates on the returned channel.  The channel is
+// closed when watching stops.  Errors reading the file (for example
+// while it is being replaced) are available from Err and polling
+// continues.  An interval that isn't positive is replaced by
+// DefaultWatchInterval.
+func (w *PGNWatcher) Watch(ctx context.Context, interval time.Duration) <-chan *PGNUpdate {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ch := make(chan *PGNUpdate)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			updates, err := w.Poll()
+			w.mu.Lock()
+			w.err = err
+			w.mu.Unlock()
+			for _, u := range updates {
+				select {
+				case ch <- u:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Err returns the error from the most recent poll made by Watch.
+func (w *PGNWatcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func diffGames(index int, prev, g *Game) *PGNUpdate {
+	if prev == nil || !extendsGame(prev, g) {
+		return &PGNUpdate{Index: index, Game: g, New: true, Moves: g.Moves()}
+	}
+	if len(g.moves) == len(prev.moves) && g.outcome == prev.outcome {
+		return nil
+	}
+	return &PGNUpdate{Index: index, Game: g, Moves: g.Moves()[len(prev.moves):]}
+}
+
+// extendsGame returns true if g starts from the same position as prev
+// and its moves begin with prev's moves.
+func extendsGame(prev, g *Game) bool {
+	if len(g.moves) < len(prev.moves) || prev.positions[0].String() != g.positions[0].String() {
+		return false
+	}
+	for i, m := range prev.moves {
+		if m.String() != g.moves[i].String() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package chess
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPGNWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "live.pgn")
+	w := NewPGNWatcher(path)
+
+	steps := []struct {
+		pgn     string
+		updates int
+		moves   []int
+	}{
+		{"[White \"A\"]\n[Black \"B\"]\n\n1. e4 e5 2. N", 1, []int{2}},
+		{"[White \"A\"]\n[Black \"B\"]\n\n1. e4 e5 2. Nf3 *", 1, []int{1}},
+		{"[White \"A\"]\n[Black \"B\"]\n\n1. e4 e5 2. Nf3 *", 0, nil},
+		{"[White \"A\"]\n[Black \"B\"]\n\n1. e4 e5 2. Nf3 Nc6 *\n\n[White \"C\"]\n[Black \"D\"]\n\n1. d4 *", 2, []int{1, 1}},
+	}
+	for i, step := range steps {
+		if err := ioutil.WriteFile(path, []byte(step.pgn), 0644); err != nil {
+			t.Fatal(err)
+		}
+		updates, err := w.Poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(updates) != step.updates {
+			t.Fatalf("step %d expected %d updates but got %d", i, step.updates, len(updates))
+		}
+		for j, u := range updates {
+			if len(u.Moves) != step.moves[j] {
+				t.Fatalf("step %d expected update %d to have %d moves but got %d", i, j, step.moves[j], len(u.Moves))
+			}
+		}
+	}
+}
+
+func TestPGNWatcherZeroInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "live.pgn")
+	if err := ioutil.WriteFile(path, []byte("1. e4 *"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// a zero interval polls at the default interval instead of panicking
+	u, ok := <-NewPGNWatcher(path).Watch(ctx, 0)
+	if !ok || len(u.Moves) != 1 {
+		t.Fatalf("expected an update with one move but got %v", u)
+	}
+}
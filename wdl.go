@@ -0,0 +1,56 @@
+package chess
+
+import "math"
+
+// WDL is the probability of each outcome of a game from one side's
+// perspective.  The probabilities sum to one.
+type WDL struct {
+	Win  float64
+	Draw float64
+	Loss float64
+}
+
+// Expected returns the expected score where a win is worth one point and
+// a draw half a point.
+func (w WDL) Expected() float64 {
+	return w.Win + w.Draw/2
+}
+
+// WDLModel converts engine scores in centipawns to outcome probabilities
+// using a logistic curve for each of the win and loss probabilities.  The
+// draw probability is what remains so level positions are mostly drawn
+// and the draw probability shrinks as the score grows.
+type WDLModel struct {
+	// Center is the score in centipawns at which winning is as likely as
+	// not.
+	Center float64
+	// Scale is the spread of the curves in centipawns.  Smaller values
+	// make the probabilities change more quickly with the score.
+	Scale float64
+}
+
+// DefaultWDLModel is the model used by the package, for example by the
+// evaluation bar of the Board's Draw method.
+var DefaultWDLModel = WDLModel{Center: 200, Scale: 80}
+
+// WDL returns the outcome probabilities for the score in centipawns from
+// the perspective of the side being evaluated.
+func (m WDLModel) WDL(centipawns int) WDL {
+	cp := float64(centipawns)
+	win := 1 / (1 + math.Exp((m.Center-cp)/m.Scale))
+	loss := 1 / (1 + math.Exp((m.Center+cp)/m.Scale))
+	return WDL{Win: win, Draw: 1 - win - loss, Loss: loss}
+}
+
+// Accuracy returns the accuracy of a move from 0 to 100 given the scores
+// in centipawns of the side that moved before and after the move.  Moves
+// that don't lower the expected score are 100% accurate and accuracy
+// falls off exponentially as the expected score drops.
+func (m WDLModel) Accuracy(before, after int) float64 {
+	drop := 100 * (m.WDL(before).Expected() - m.WDL(after).Expected())
+	if drop <= 0 {
+		return 100
+	}
+	accuracy := 103.1668*math.Exp(-0.04354*drop) - 3.1669
+	return math.Max(0, math.Min(100, accuracy))
+}
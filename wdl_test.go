@@ -0,0 +1,43 @@
+package chess
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWDLModel(t *testing.T) {
+	m := DefaultWDLModel
+	level := m.WDL(0)
+	if math.Abs(level.Win-level.Loss) > 1e-9 || level.Draw < level.Win {
+		t.Fatalf("expected a level position to be mostly drawn but got %+v", level)
+	}
+	prev := level.Expected()
+	for _, cp := range []int{50, 100, 300, 1000} {
+		w := m.WDL(cp)
+		if math.Abs(w.Win+w.Draw+w.Loss-1) > 1e-9 {
+			t.Fatalf("expected probabilities to sum to one but got %+v", w)
+		}
+		if w.Expected() <= prev {
+			t.Fatalf("expected the expected score to grow with the score at %d", cp)
+		}
+		prev = w.Expected()
+		if opp := m.WDL(-cp); math.Abs(opp.Win-w.Loss) > 1e-9 {
+			t.Fatalf("expected the model to be symmetric at %d", cp)
+		}
+	}
+	if w := m.WDL(1000); w.Win < 0.99 {
+		t.Fatalf("expected a winning score to be nearly certain but got %+v", w)
+	}
+}
+
+func TestWDLAccuracy(t *testing.T) {
+	m := DefaultWDLModel
+	if a := m.Accuracy(50, 80); a != 100 {
+		t.Fatalf("expected an improving move to be 100%% accurate but got %f", a)
+	}
+	small := m.Accuracy(50, 20)
+	blunder := m.Accuracy(50, -500)
+	if !(small > blunder && small < 100 && blunder >= 0) {
+		t.Fatalf("expected accuracy to fall with the loss but got %f and %f", small, blunder)
+	}
+}